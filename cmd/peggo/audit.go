@@ -0,0 +1,45 @@
+package main
+
+import (
+	cli "github.com/jawher/mow.cli"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
+)
+
+// auditCmdSubset contains actions that inspect the signing audit log.
+//
+// $ peggo audit
+func auditCmdSubset(cmd *cli.Cmd) {
+	cmd.Command(
+		"verify",
+		"Verifies the hash chain of the signing audit log",
+		verifyAuditLogCmd,
+	)
+}
+
+func verifyAuditLogCmd(cmd *cli.Cmd) {
+	var dataDir *string
+
+	initStateOptions(cmd, &dataDir)
+
+	cmd.Action = func() {
+		dir := auditLogDir(*dataDir)
+
+		n, last, err := audit.Verify(dir)
+		if err != nil {
+			log.WithError(err).WithField("verified_records", n).Fatalln("signing audit log is corrupted")
+		}
+
+		if last == nil {
+			log.WithField("dir", dir).Infoln("signing audit log is empty")
+			return
+		}
+
+		log.WithFields(log.Fields{
+			"dir":       dir,
+			"last_seq":  last.Seq,
+			"last_hash": last.Hash.Hex(),
+		}).Infof("verified %d signing audit records", n)
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/pkg/errors"
+	"github.com/xlab/closer"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/offline"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+)
+
+// exportConfirmsCmd writes the valsets and batches not confirmed by the orchestrator yet, along with their
+// checkpoint digests, to a bundle that is carried to the host holding the Ethereum key.
+//
+// $ peggo tx export-confirms
+func exportConfirmsCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos params
+		cosmosChainID   *string
+		cosmosGRPC      *string
+		tendermintRPC   *string
+		cosmosGasPrices *string
+
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+
+		cosmosKeyFrom       *string
+		cosmosKeyPassphrase *string
+		cosmosPrivKey       *string
+		cosmosUseLedger     *bool
+		cosmosSigner        *string
+	)
+
+	initCosmosOptions(
+		cmd,
+		&cosmosChainID,
+		&cosmosGRPC,
+		&tendermintRPC,
+		&cosmosGasPrices,
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+		&cosmosSigner,
+	)
+
+	file := cmd.StringOpt("file", "", "Write the bundle to the given path instead of stdout")
+
+	cmd.Action = func() {
+		// ensure a clean exit
+		defer closer.Close()
+
+		keyring, err := cosmos.NewKeyring(cosmos.KeyringConfig{
+			KeyringDir:     *cosmosKeyringDir,
+			KeyringAppName: *cosmosKeyringAppName,
+			KeyringBackend: *cosmosKeyringBackend,
+			KeyFrom:        *cosmosKeyFrom,
+			KeyPassphrase:  *cosmosKeyPassphrase,
+			PrivateKey:     *cosmosPrivKey,
+			SignerURI:      *cosmosSigner,
+			UseLedger:      *cosmosUseLedger,
+		})
+		orShutdown(errors.Wrap(err, "failed to initialize Injective keyring"))
+
+		net, err := cosmos.NewNetwork(keyring, nil, cosmos.NetworkConfig{
+			ChainID:          *cosmosChainID,
+			ValidatorAddress: keyring.Addr.String(),
+			CosmosGRPC:       *cosmosGRPC,
+			TendermintRPC:    *tendermintRPC,
+			GasPrice:         *cosmosGasPrices,
+		})
+		orShutdown(errors.Wrap(err, "failed to connect to Injective network"))
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Minute)
+		defer cancelFn()
+
+		params, err := net.PeggyParams(ctx)
+		orShutdown(errors.Wrap(err, "failed to query peggy params"))
+
+		peggyID, err := offline.PeggyID(params)
+		orShutdown(err)
+
+		bundle, err := offline.Export(ctx, net, *cosmosChainID, peggyID, keyring.Addr)
+		orShutdown(err)
+
+		var w io.Writer = os.Stdout
+		if *file != "" {
+			f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			orShutdown(err)
+			closer.Bind(func() { _ = f.Close() })
+
+			w = f
+		}
+
+		orShutdown(bundle.Write(w))
+
+		log.WithFields(log.Fields{
+			"orchestrator": keyring.Addr.String(),
+			"valsets":      len(bundle.Valsets),
+			"batches":      len(bundle.Batches),
+		}).Infoln("exported confirmation bundle")
+	}
+}
+
+// signConfirmsCmd signs the checkpoints of an exported bundle with the Ethereum key. It needs no network access,
+// the checkpoints are recorded in the slashing protection database of the offline host.
+//
+// $ peggo tx sign-confirms
+func signConfirmsCmd(cmd *cli.Cmd) {
+	var (
+		// Ethereum Key Management
+		ethKeystoreDir *string
+		ethKeyFrom     *string
+		ethPassphrase  *string
+		ethPrivKey     *string
+		ethUseLedger   *bool
+
+		ethRemoteSigner    *string
+		ethRemoteSignerAPI *string
+		ethSigner          *string
+
+		// Local state
+		dataDir *string
+
+		// Misc
+		alwaysAutoConfirm *bool
+	)
+
+	initEthereumKeyOptions(
+		cmd,
+		&ethKeystoreDir,
+		&ethKeyFrom,
+		&ethPassphrase,
+		&ethPrivKey,
+		&ethUseLedger,
+		&ethRemoteSigner,
+		&ethRemoteSignerAPI,
+		&ethSigner,
+	)
+
+	initStateOptions(cmd, &dataDir)
+
+	initInteractiveOptions(
+		cmd,
+		&alwaysAutoConfirm,
+	)
+
+	out := cmd.StringOpt("out", "signed-confirms.json", "Write the signed bundle to the given path")
+	file := cmd.StringArg("BUNDLE", "", "Bundle exported with `peggo tx export-confirms`")
+
+	cmd.Action = func() {
+		// ensure a clean exit
+		defer closer.Close()
+
+		f, err := os.Open(*file)
+		orShutdown(err)
+		closer.Bind(func() { _ = f.Close() })
+
+		bundle, err := offline.ReadBundle(f)
+		orShutdown(err)
+
+		ethKeyFromAddress, _, personalSignFn, err := initEthereumAccountsManager(
+			0,
+			ethKeystoreDir,
+			ethKeyFrom,
+			ethPassphrase,
+			ethPrivKey,
+			ethUseLedger,
+			ethRemoteSigner,
+			ethRemoteSignerAPI,
+			ethSigner,
+		)
+		orShutdown(errors.Wrap(err, "failed to initialize Ethereum keyring"))
+
+		log.WithFields(log.Fields{
+			"chain_id":     bundle.ChainID,
+			"peggy_id":     bundle.PeggyID.Hex(),
+			"orchestrator": bundle.Orchestrator,
+			"eth_signer":   ethKeyFromAddress.Hex(),
+			"exported_at":  bundle.ExportedAt,
+		}).Infoln("loaded confirmation bundle")
+
+		for _, vs := range bundle.Valsets {
+			log.WithFields(log.Fields{"valset_nonce": vs.Valset.Nonce, "height": vs.Valset.Height, "validators": len(vs.Valset.Members), "digest": vs.Digest.Hex()}).Infoln("valset to confirm")
+		}
+
+		for _, b := range bundle.Batches {
+			log.WithFields(log.Fields{"token_contract": b.Batch.TokenContract, "batch_nonce": b.Batch.BatchNonce, "txs": len(b.Batch.Transactions), "digest": b.Digest.Hex()}).Infoln("batch to confirm")
+		}
+
+		if len(bundle.Valsets) == 0 && len(bundle.Batches) == 0 {
+			log.Infoln("nothing to sign")
+			return
+		}
+
+		actionConfirmed := *alwaysAutoConfirm || stdinConfirm("Sign these checkpoints with the Ethereum key? [y/N]: ")
+		if !actionConfirmed {
+			return
+		}
+
+		stateStore, err := state.NewStore(*dataDir)
+		orShutdown(err)
+		closer.Bind(func() { _ = stateStore.Close() })
+
+		err = bundle.Sign(ethKeyFromAddress, peggy.PersonalSignConfirmSigner(personalSignFn), stateStore)
+		orShutdown(err)
+
+		signed, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		orShutdown(err)
+		closer.Bind(func() { _ = signed.Close() })
+
+		orShutdown(bundle.Write(signed))
+
+		log.WithField("file", *out).Infof("signed %d valsets and %d batches", len(bundle.Valsets), len(bundle.Batches))
+	}
+}
+
+// importConfirmsCmd broadcasts the confirmations of a signed bundle that were not sent yet.
+//
+// $ peggo tx import-confirms
+func importConfirmsCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos params
+		cosmosChainID   *string
+		cosmosGRPC      *string
+		tendermintRPC   *string
+		cosmosGasPrices *string
+
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+
+		cosmosKeyFrom       *string
+		cosmosKeyPassphrase *string
+		cosmosPrivKey       *string
+		cosmosUseLedger     *bool
+		cosmosSigner        *string
+
+		// Local state
+		dataDir *string
+
+		// Misc
+		alwaysAutoConfirm *bool
+	)
+
+	initCosmosOptions(
+		cmd,
+		&cosmosChainID,
+		&cosmosGRPC,
+		&tendermintRPC,
+		&cosmosGasPrices,
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+		&cosmosSigner,
+	)
+
+	initStateOptions(cmd, &dataDir)
+
+	initInteractiveOptions(
+		cmd,
+		&alwaysAutoConfirm,
+	)
+
+	file := cmd.StringArg("BUNDLE", "", "Bundle signed with `peggo tx sign-confirms`")
+
+	cmd.Action = func() {
+		// ensure a clean exit
+		defer closer.Close()
+
+		f, err := os.Open(*file)
+		orShutdown(err)
+		closer.Bind(func() { _ = f.Close() })
+
+		bundle, err := offline.ReadBundle(f)
+		orShutdown(err)
+		orShutdown(bundle.VerifySignatures())
+
+		if bundle.ChainID != *cosmosChainID {
+			orShutdown(errors.Errorf("confirmation bundle was exported from chain %s, not %s", bundle.ChainID, *cosmosChainID))
+		}
+
+		keyring, err := cosmos.NewKeyring(cosmos.KeyringConfig{
+			KeyringDir:     *cosmosKeyringDir,
+			KeyringAppName: *cosmosKeyringAppName,
+			KeyringBackend: *cosmosKeyringBackend,
+			KeyFrom:        *cosmosKeyFrom,
+			KeyPassphrase:  *cosmosKeyPassphrase,
+			PrivateKey:     *cosmosPrivKey,
+			SignerURI:      *cosmosSigner,
+			UseLedger:      *cosmosUseLedger,
+		})
+		orShutdown(errors.Wrap(err, "failed to initialize Injective keyring"))
+
+		log.WithFields(log.Fields{
+			"orchestrator": keyring.Addr.String(),
+			"eth_signer":   bundle.EthSigner.Hex(),
+			"valsets":      len(bundle.Valsets),
+			"batches":      len(bundle.Batches),
+		}).Infoln("loaded signed confirmation bundle")
+
+		actionConfirmed := *alwaysAutoConfirm || stdinConfirm("Broadcast the confirmations? [y/N]: ")
+		if !actionConfirmed {
+			return
+		}
+
+		// confirmations are recorded in the audit log of this host, like the ones sent by the orchestrator
+		auditLog, err := audit.Open(auditLogDir(*dataDir), audit.DefaultMaxFileSize)
+		orShutdown(errors.Wrap(err, "failed to open signing audit log"))
+		closer.Bind(func() { _ = auditLog.Close() })
+
+		net, err := cosmos.NewNetwork(keyring, bundle.ConfirmSigner(), cosmos.NetworkConfig{
+			ChainID:          *cosmosChainID,
+			ValidatorAddress: keyring.Addr.String(),
+			CosmosGRPC:       *cosmosGRPC,
+			TendermintRPC:    *tendermintRPC,
+			GasPrice:         *cosmosGasPrices,
+			AuditLog:         auditLog,
+		})
+		orShutdown(errors.Wrap(err, "failed to connect to Injective network"))
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Minute)
+		defer cancelFn()
+
+		valsets, batches, err := offline.Import(ctx, net, bundle, keyring.Addr)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{"sent_valsets": valsets, "sent_batches": batches}).Fatalln("failed to broadcast confirmations")
+		}
+
+		log.Infof("broadcast %d valset and %d batch confirmations", valsets, batches)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
+	"github.com/InjectiveLabs/peggo/orchestrator/remotekey"
 )
 
 var emptyEthAddress = ethcmn.Address{}
@@ -32,6 +33,9 @@ func initEthereumAccountsManager(
 	ethPassphrase *string,
 	ethPrivKey *string,
 	ethUseLedger *bool,
+	ethRemoteSigner *string,
+	ethRemoteSignerAPI *string,
+	ethSigner *string,
 ) (
 	ethKeyFromAddress ethcmn.Address,
 	signerFn bind.SignerFn,
@@ -117,6 +121,56 @@ func initEthereumAccountsManager(
 
 		return ethKeyFromAddress, signerFn, personalSignFn, nil
 
+	case len(*ethSigner) > 0:
+		key, err := remotekey.Open(*ethSigner)
+		if err != nil {
+			err = errors.Wrap(err, "failed to open Ethereum signer")
+			return emptyEthAddress, nil, nil, err
+		}
+
+		ethKeyFromAddress = ethcrypto.PubkeyToAddress(*key.PublicKey())
+		if len(*ethKeyFrom) > 0 && ethcmn.HexToAddress(*ethKeyFrom) != ethKeyFromAddress {
+			err = errors.Errorf("Ethereum from address does not match address %s of the signer", ethKeyFromAddress)
+			return emptyEthAddress, nil, nil, err
+		}
+
+		return ethKeyFromAddress, keystore.DigestSignerFn(ethChainID, key), keystore.DigestPersonalSignFn(key), nil
+
+	case len(*ethRemoteSigner) > 0:
+		ethKeyFromAddress = ethcmn.HexToAddress(*ethKeyFrom)
+		if ethKeyFromAddress == (ethcmn.Address{}) {
+			err = errors.New("cannot use remote signer without from address specified")
+			return emptyEthAddress, nil, nil, err
+		}
+
+		var remoteSigner keystore.RemoteSigner
+		switch *ethRemoteSignerAPI {
+		case "web3signer":
+			remoteSigner, err = keystore.NewWeb3Signer(*ethRemoteSigner, 0)
+		case "clef":
+			remoteSigner, err = keystore.NewClefSigner(*ethRemoteSigner, 0)
+		default:
+			err = errors.Errorf("unsupported remote signer API %q", *ethRemoteSignerAPI)
+		}
+
+		if err != nil {
+			return emptyEthAddress, nil, nil, err
+		}
+
+		signerFn, err := remoteSigner.SignerFn(ethChainID, ethKeyFromAddress)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to load remote key for %s", ethKeyFromAddress)
+			return emptyEthAddress, nil, nil, err
+		}
+
+		personalSignFn, err := remoteSigner.PersonalSignFn(ethKeyFromAddress)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to load remote key for %s", ethKeyFromAddress)
+			return emptyEthAddress, nil, nil, err
+		}
+
+		return ethKeyFromAddress, signerFn, personalSignFn, nil
+
 	case len(*ethPrivKey) > 0:
 		ethPk, err := crypto.HexToECDSA(*ethPrivKey)
 		if err != nil {
@@ -31,8 +31,12 @@ func main() {
 	}
 
 	app.Command("orchestrator", "Starts the orchestrator main loop.", orchestratorCmd)
+	app.Command("signer", "Starts the signer holding the orchestrator keys, for an orchestrator running on another host.", signerCmd)
 	app.Command("q query", "Query commands that can get state info from Peggy.", queryCmdSubset)
 	app.Command("tx", "Transactions for Peggy governance and maintenance.", txCmdSubset)
+	app.Command("audit", "Inspect the log of every signature produced with the Ethereum key.", auditCmdSubset)
+	app.Command("outflow", "List or approve the batches held by the outflow limits.", outflowCmdSubset)
+	app.Command("slashing-protection", "Export or import the slashing protection database of the Ethereum key.", slashingProtectionCmdSubset)
 	app.Command("version", "Print the version information and exit.", versionCmd)
 
 	_ = app.Run(os.Args)
@@ -1,6 +1,11 @@
 package main
 
-import cli "github.com/jawher/mow.cli"
+import (
+	cli "github.com/jawher/mow.cli"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+)
 
 // initGlobalOptions defines some global CLI options, that are useful for most parts of the app.
 // Before adding option to there, consider moving it into the actual Cmd.
@@ -86,6 +91,7 @@ func initCosmosKeyOptions(
 	cosmosKeyPassphrase **string,
 	cosmosPrivKey **string,
 	cosmosUseLedger **bool,
+	cosmosSigner **string,
 ) {
 	*cosmosKeyringBackend = cmd.String(cli.StringOpt{
 		Name:   "cosmos-keyring",
@@ -133,6 +139,12 @@ func initCosmosKeyOptions(
 		EnvVar: "PEGGO_COSMOS_USE_LEDGER",
 		Value:  false,
 	})
+
+	*cosmosSigner = cmd.String(cli.StringOpt{
+		Name:   "cosmos-signer",
+		Desc:   "Specify the URI of the Cosmos key in a signing backend: vault://host:port/<transit mount>/<key> or pkcs11:token=<label>;object=<label>?module-path=<path>.",
+		EnvVar: "PEGGO_COSMOS_SIGNER",
+	})
 }
 
 func initEthereumKeyOptions(
@@ -142,6 +154,9 @@ func initEthereumKeyOptions(
 	ethPassphrase **string,
 	ethPrivKey **string,
 	ethUseLedger **bool,
+	ethRemoteSigner **string,
+	ethRemoteSignerAPI **string,
+	ethSigner **string,
 ) {
 	*ethKeystoreDir = cmd.String(cli.StringOpt{
 		Name:   "eth-keystore-dir",
@@ -173,6 +188,38 @@ func initEthereumKeyOptions(
 		EnvVar: "PEGGO_ETH_USE_LEDGER",
 		Value:  false,
 	})
+
+	*ethRemoteSigner = cmd.String(cli.StringOpt{
+		Name:   "eth-remote-signer",
+		Desc:   "Specify the URL (or Clef IPC path) of a remote signer holding the Ethereum key of --eth-from.",
+		EnvVar: "PEGGO_ETH_REMOTE_SIGNER",
+	})
+
+	*ethRemoteSignerAPI = cmd.String(cli.StringOpt{
+		Name:   "eth-remote-signer-api",
+		Desc:   "Specify the API of the remote signer: web3signer (eth1 HTTP API) or clef (external JSON-RPC API).",
+		EnvVar: "PEGGO_ETH_REMOTE_SIGNER_API",
+		Value:  "web3signer",
+	})
+
+	*ethSigner = cmd.String(cli.StringOpt{
+		Name:   "eth-signer",
+		Desc:   "Specify the URI of the Ethereum key in a signing backend: vault://host:port/<transit mount>/<key> or pkcs11:token=<label>;object=<label>?module-path=<path>.",
+		EnvVar: "PEGGO_ETH_SIGNER",
+	})
+}
+
+// initStateOptions sets options for peggo's local state.
+func initStateOptions(
+	cmd *cli.Cmd,
+	dataDir **string,
+) {
+	*dataDir = cmd.String(cli.StringOpt{
+		Name:   "data-dir",
+		Desc:   "Specify the directory where peggo keeps its local state (e.g. oracle progress, slashing protection).",
+		EnvVar: "PEGGO_DATA_DIR",
+		Value:  defaultDataDir(),
+	})
 }
 
 // initStatsdOptions sets options for StatsD metrics.
@@ -234,6 +281,8 @@ type Config struct {
 	cosmosGRPC      *string
 	tendermintRPC   *string
 	cosmosGasPrices *string
+	claimsTxSize    *int
+	confirmsTxSize  *int
 
 	// Cosmos Key Management
 	cosmosKeyringDir     *string
@@ -244,14 +293,23 @@ type Config struct {
 	cosmosKeyPassphrase *string
 	cosmosPrivKey       *string
 	cosmosUseLedger     *bool
+	cosmosSigner        *string
 
 	// Ethereum params
 	ethChainID            *int
 	ethNodeRPC            *string
 	ethNodeAlchemyWS      *string
+	ethNodeWS             *string
+	ethVerifyRPCs         *string
+	ethVerifyQuorum       *int
+	ethVerifyReceipts     *bool
 	ethGasPriceAdjustment *float64
 	ethMaxGasPrice        *string
 
+	// Ethereum event confirmations
+	ethConfirmationPolicy *string
+	ethConfirmationDepth  *int
+
 	// Ethereum Key Management
 	ethKeystoreDir *string
 	ethKeyFrom     *string
@@ -259,17 +317,49 @@ type Config struct {
 	ethPrivKey     *string
 	ethUseLedger   *bool
 
+	ethRemoteSigner    *string
+	ethRemoteSignerAPI *string
+	ethSigner          *string
+
+	// Signer daemon holding both keys
+	signerAddr     *string
+	signerTLSCA    *string
+	signerTLSCert  *string
+	signerTLSKey   *string
+	signerInsecure *bool
+
 	// Relayer config
 	relayValsets          *bool
 	relayValsetOffsetDur  *string
 	relayBatches          *bool
 	relayBatchOffsetDur   *string
+	relayBatchMargin      *float64
 	pendingTxWaitDuration *string
 
 	// Batch requester config
 	minBatchFeeUSD *float64
+	batchPolicies  *string
+	batchGasMargin *float64
+
+	batchGasBase         *int
+	batchGasPerValidator *int
+	batchGasPerTx        *int
+
+	// Starvation guard
+	forceBatchAge    *string
+	maxForcedBatches *int
+
+	batchRequestGrace *string
+
+	// Signer outflow limits
+	outflowLimits *string
 
 	coingeckoApi *string
+
+	// Local state
+	dataDir *string
+
+	auditLogMaxSize *int
 }
 
 func initConfig(cmd *cli.Cmd) Config {
@@ -303,6 +393,20 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  "", // example: 500000000inj
 	})
 
+	cfg.claimsTxSize = cmd.Int(cli.IntOpt{
+		Name:   "cosmos-claims-tx-size",
+		Desc:   "Specify the max total size (in bytes) of Ethereum event claims bundled into one Injective transaction",
+		EnvVar: "PEGGO_COSMOS_CLAIMS_TX_SIZE",
+		Value:  peggy.DefaultMaxClaimsTxSize,
+	})
+
+	cfg.confirmsTxSize = cmd.Int(cli.IntOpt{
+		Name:   "cosmos-confirms-tx-size",
+		Desc:   "Specify the max total size (in bytes) of batch confirmations bundled into one Injective transaction",
+		EnvVar: "PEGGO_COSMOS_CONFIRMS_TX_SIZE",
+		Value:  peggy.DefaultMaxConfirmsTxSize,
+	})
+
 	cfg.cosmosKeyringBackend = cmd.String(cli.StringOpt{
 		Name:   "cosmos-keyring",
 		Desc:   "Specify Cosmos keyring backend (os|file|kwallet|pass|test)",
@@ -350,6 +454,12 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  false,
 	})
 
+	cfg.cosmosSigner = cmd.String(cli.StringOpt{
+		Name:   "cosmos-signer",
+		Desc:   "Specify the URI of the Cosmos key in a signing backend: vault://host:port/<transit mount>/<key> or pkcs11:token=<label>;object=<label>?module-path=<path>.",
+		EnvVar: "PEGGO_COSMOS_SIGNER",
+	})
+
 	/** Ethereum **/
 
 	cfg.ethChainID = cmd.Int(cli.IntOpt{
@@ -361,7 +471,7 @@ func initConfig(cmd *cli.Cmd) Config {
 
 	cfg.ethNodeRPC = cmd.String(cli.StringOpt{
 		Name:   "eth-node-http",
-		Desc:   "Specify HTTP endpoint for an Ethereum node.",
+		Desc:   "Specify HTTP endpoint for an Ethereum node. Multiple comma-separated endpoints enable health-checked failover between them.",
 		EnvVar: "PEGGO_ETH_RPC",
 		Value:  "http://localhost:1317",
 	})
@@ -373,6 +483,34 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  "",
 	})
 
+	cfg.ethNodeWS = cmd.String(cli.StringOpt{
+		Name:   "eth-node-ws",
+		Desc:   "Specify websocket endpoint for an Ethereum node. If set, Oracle subscribes to new heads and Peggy events instead of waiting for the next poll.",
+		EnvVar: "PEGGO_ETH_WS",
+		Value:  "",
+	})
+
+	cfg.ethVerifyRPCs = cmd.String(cli.StringOpt{
+		Name:   "eth-verify-rpcs",
+		Desc:   "Specify comma-separated HTTP endpoints of independent Ethereum providers. If set, Oracle only claims events confirmed by a quorum of them.",
+		EnvVar: "PEGGO_ETH_VERIFY_RPCS",
+		Value:  "",
+	})
+
+	cfg.ethVerifyQuorum = cmd.Int(cli.IntOpt{
+		Name:   "eth-verify-quorum",
+		Desc:   "Specify the number of verifying Ethereum providers that must agree on an event. Defaults to a majority of them.",
+		EnvVar: "PEGGO_ETH_VERIFY_QUORUM",
+		Value:  0,
+	})
+
+	cfg.ethVerifyReceipts = cmd.Bool(cli.BoolOpt{
+		Name:   "eth-verify-receipts",
+		Desc:   "If enabled, Oracle only claims events proven to be included in their block by rebuilding the receipts trie. The block header is validated by the verifying Ethereum providers, if set.",
+		EnvVar: "PEGGO_ETH_VERIFY_RECEIPTS",
+		Value:  false,
+	})
+
 	cfg.ethGasPriceAdjustment = cmd.Float64(cli.Float64Opt{
 		Name:   "eth_gas_price_adjustment",
 		Desc:   "gas price adjustment for Ethereum transactions",
@@ -387,6 +525,20 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  "500gwei",
 	})
 
+	cfg.ethConfirmationPolicy = cmd.String(cli.StringOpt{
+		Name:   "eth-confirmation-policy",
+		Desc:   "Specify when an Ethereum block is safe to attest to: depth, finalized or safe",
+		EnvVar: "PEGGO_ETH_CONFIRMATION_POLICY",
+		Value:  "depth",
+	})
+
+	cfg.ethConfirmationDepth = cmd.Int(cli.IntOpt{
+		Name:   "eth-confirmation-depth",
+		Desc:   "Specify the number of Ethereum block confirmations when using the depth confirmation policy",
+		EnvVar: "PEGGO_ETH_CONFIRMATION_DEPTH",
+		Value:  12,
+	})
+
 	cfg.ethKeystoreDir = cmd.String(cli.StringOpt{
 		Name:   "eth-keystore-dir",
 		Desc:   "Specify Ethereum keystore dir (Geth-format) prefix.",
@@ -418,6 +570,58 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  false,
 	})
 
+	cfg.ethRemoteSigner = cmd.String(cli.StringOpt{
+		Name:   "eth-remote-signer",
+		Desc:   "Specify the URL (or Clef IPC path) of a remote signer holding the Ethereum key of --eth-from.",
+		EnvVar: "PEGGO_ETH_REMOTE_SIGNER",
+	})
+
+	cfg.ethRemoteSignerAPI = cmd.String(cli.StringOpt{
+		Name:   "eth-remote-signer-api",
+		Desc:   "Specify the API of the remote signer: web3signer (eth1 HTTP API) or clef (external JSON-RPC API).",
+		EnvVar: "PEGGO_ETH_REMOTE_SIGNER_API",
+		Value:  "web3signer",
+	})
+
+	cfg.ethSigner = cmd.String(cli.StringOpt{
+		Name:   "eth-signer",
+		Desc:   "Specify the URI of the Ethereum key in a signing backend: vault://host:port/<transit mount>/<key> or pkcs11:token=<label>;object=<label>?module-path=<path>.",
+		EnvVar: "PEGGO_ETH_SIGNER",
+	})
+
+	/** Signer daemon **/
+
+	cfg.signerAddr = cmd.String(cli.StringOpt{
+		Name:   "signer-addr",
+		Desc:   "Specify the address of a peggo signer holding the Ethereum and Injective keys, in place of local keys. --eth-from and --cosmos-from, if set, must match its keys.",
+		EnvVar: "PEGGO_SIGNER_ADDR",
+	})
+
+	cfg.signerTLSCA = cmd.String(cli.StringOpt{
+		Name:   "signer-tls-ca",
+		Desc:   "Specify the CA certificates verifying the peggo signer TLS certificate. System roots are used if empty.",
+		EnvVar: "PEGGO_SIGNER_TLS_CA",
+	})
+
+	cfg.signerTLSCert = cmd.String(cli.StringOpt{
+		Name:   "signer-tls-cert",
+		Desc:   "Specify the TLS client certificate presented to the peggo signer.",
+		EnvVar: "PEGGO_SIGNER_TLS_CERT",
+	})
+
+	cfg.signerTLSKey = cmd.String(cli.StringOpt{
+		Name:   "signer-tls-key",
+		Desc:   "Specify the TLS client private key presented to the peggo signer.",
+		EnvVar: "PEGGO_SIGNER_TLS_KEY",
+	})
+
+	cfg.signerInsecure = cmd.Bool(cli.BoolOpt{
+		Name:   "signer-insecure",
+		Desc:   "Connect to the peggo signer over plaintext gRPC. Only use over a trusted private link.",
+		EnvVar: "PEGGO_SIGNER_INSECURE",
+		Value:  false,
+	})
+
 	/** Relayer **/
 
 	cfg.relayValsets = cmd.Bool(cli.BoolOpt{
@@ -448,6 +652,12 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  "5m",
 	})
 
+	cfg.relayBatchMargin = cmd.Float64(cli.Float64Opt{
+		Name:   "relay_batch_margin",
+		Desc:   "If set, relayer will broadcast batches only when their fees exceed the simulated gas cost of submitting them times this margin (e.g. 1.1)",
+		EnvVar: "PEGGO_RELAY_BATCH_MARGIN",
+	})
+
 	cfg.pendingTxWaitDuration = cmd.String(cli.StringOpt{
 		Name:   "relay_pending_tx_wait_duration",
 		Desc:   "If set, relayer will broadcast pending batches/valsetupdate only after pendingTxWaitDuration has passed",
@@ -464,6 +674,67 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  float64(23.3),
 	})
 
+	cfg.batchPolicies = cmd.String(cli.StringOpt{
+		Name:   "batch-policies",
+		Desc:   "Specify a TOML file with per-token batch request policies. Tokens without a policy use min_batch_fee_usd.",
+		EnvVar: "PEGGO_BATCH_POLICIES",
+	})
+
+	cfg.batchGasMargin = cmd.Float64(cli.Float64Opt{
+		Name:   "batch-gas-margin",
+		Desc:   "If set, batches are requested only when their fees exceed the estimated Ethereum gas cost of relaying them times this margin (e.g. 1.2).",
+		EnvVar: "PEGGO_BATCH_GAS_MARGIN",
+	})
+
+	cfg.batchGasBase = cmd.Int(cli.IntOpt{
+		Name:   "batch-gas-base",
+		Desc:   "Specify the gas of submitBatch without signatures and withdrawals, used by batch-gas-margin.",
+		EnvVar: "PEGGO_BATCH_GAS_BASE",
+		Value:  120_000,
+	})
+
+	cfg.batchGasPerValidator = cmd.Int(cli.IntOpt{
+		Name:   "batch-gas-per-validator",
+		Desc:   "Specify the gas of submitBatch per valset member, used by batch-gas-margin.",
+		EnvVar: "PEGGO_BATCH_GAS_PER_VALIDATOR",
+		Value:  7_000,
+	})
+
+	cfg.batchGasPerTx = cmd.Int(cli.IntOpt{
+		Name:   "batch-gas-per-tx",
+		Desc:   "Specify the gas of submitBatch per withdrawal, used by batch-gas-margin.",
+		EnvVar: "PEGGO_BATCH_GAS_PER_TX",
+		Value:  35_000,
+	})
+
+	cfg.forceBatchAge = cmd.String(cli.StringOpt{
+		Name:   "batch-force-age",
+		Desc:   "If set, a batch is requested regardless of fees once a withdrawal has waited this long (e.g. 24h). Batch policies can set their own max_withdrawal_age.",
+		EnvVar: "PEGGO_BATCH_FORCE_AGE",
+	})
+
+	cfg.maxForcedBatches = cmd.Int(cli.IntOpt{
+		Name:   "batch-max-forced",
+		Desc:   "Specify the max number of batches forced per token a day, 0 for no cap. Batch policies can set their own max_forced_batches.",
+		EnvVar: "PEGGO_BATCH_MAX_FORCED",
+		Value:  1,
+	})
+
+	cfg.batchRequestGrace = cmd.String(cli.StringOpt{
+		Name:   "batch-request-grace",
+		Desc:   "Specify how long to wait for the valset member elected to request the batch of a token before requesting it, 0 to request right away.",
+		EnvVar: "PEGGO_BATCH_REQUEST_GRACE",
+		Value:  "5m",
+	})
+
+	/** Outflow limits **/
+
+	cfg.outflowLimits = cmd.String(cli.StringOpt{
+		Name:   "outflow-limits",
+		Desc:   "Specify a JSON file with per-token limits on the amounts withdrawn by signed batches. Batches over the limits are held until approved with `peggo outflow approve`.",
+		EnvVar: "PEGGO_OUTFLOW_LIMITS",
+	})
+
 	/** Coingecko **/
 
 	cfg.coingeckoApi = cmd.String(cli.StringOpt{
@@ -473,5 +744,16 @@ func initConfig(cmd *cli.Cmd) Config {
 		Value:  "https://api.coingecko.com/api/v3",
 	})
 
+	/** State **/
+
+	initStateOptions(cmd, &cfg.dataDir)
+
+	cfg.auditLogMaxSize = cmd.Int(cli.IntOpt{
+		Name:   "audit-log-max-size",
+		Desc:   "Specify the size (in bytes) above which the signing audit log in <data-dir>/audit is rotated. Rotated files are kept.",
+		EnvVar: "PEGGO_AUDIT_LOG_MAX_SIZE",
+		Value:  audit.DefaultMaxFileSize,
+	})
+
 	return cfg
 }
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	cli "github.com/jawher/mow.cli"
 	"github.com/pkg/errors"
@@ -12,9 +14,16 @@ import (
 	log "github.com/xlab/suplog"
 
 	"github.com/InjectiveLabs/peggo/orchestrator"
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
+	"github.com/InjectiveLabs/peggo/orchestrator/batchpolicy"
 	"github.com/InjectiveLabs/peggo/orchestrator/cosmos"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
+	"github.com/InjectiveLabs/peggo/orchestrator/outflow"
 	"github.com/InjectiveLabs/peggo/orchestrator/pricefeed"
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
 	"github.com/InjectiveLabs/peggo/orchestrator/version"
 	chaintypes "github.com/InjectiveLabs/sdk-go/chain/types"
 )
@@ -41,13 +50,16 @@ func orchestratorCmd(cmd *cli.Cmd) {
 				KeyFrom:        *cfg.cosmosKeyFrom,
 				KeyPassphrase:  *cfg.cosmosKeyPassphrase,
 				PrivateKey:     *cfg.cosmosPrivKey,
+				SignerURI:      *cfg.cosmosSigner,
 				UseLedger:      *cfg.cosmosUseLedger,
 			}
 			cosmosNetworkCfg = cosmos.NetworkConfig{
-				ChainID:       *cfg.cosmosChainID,
-				CosmosGRPC:    *cfg.cosmosGRPC,
-				TendermintRPC: *cfg.tendermintRPC,
-				GasPrice:      *cfg.cosmosGasPrices,
+				ChainID:           *cfg.cosmosChainID,
+				CosmosGRPC:        *cfg.cosmosGRPC,
+				TendermintRPC:     *cfg.tendermintRPC,
+				GasPrice:          *cfg.cosmosGasPrices,
+				MaxClaimsTxSize:   *cfg.claimsTxSize,
+				MaxConfirmsTxSize: *cfg.confirmsTxSize,
 			}
 			ethNetworkCfg = ethereum.NetworkConfig{
 				EthNodeRPC:            *cfg.ethNodeRPC,
@@ -55,6 +67,10 @@ func orchestratorCmd(cmd *cli.Cmd) {
 				MaxGasPrice:           *cfg.ethMaxGasPrice,
 				PendingTxWaitDuration: *cfg.pendingTxWaitDuration,
 				EthNodeAlchemyWS:      *cfg.ethNodeAlchemyWS,
+				EthNodeWS:             *cfg.ethNodeWS,
+				EthVerifyRPCs:         *cfg.ethVerifyRPCs,
+				EthVerifyQuorum:       *cfg.ethVerifyQuorum,
+				EthVerifyReceipts:     *cfg.ethVerifyReceipts,
 			}
 		)
 
@@ -70,25 +86,58 @@ func orchestratorCmd(cmd *cli.Cmd) {
 			"go_arch":    version.GoArch,
 		}).Infoln("Peggo - Peggy module companion binary for bridging assets between Injective and Ethereum")
 
+		// every signature produced with the Ethereum key is recorded in the audit log
+		auditLog, err := audit.Open(auditLogDir(*cfg.dataDir), int64(*cfg.auditLogMaxSize))
+		orShutdown(errors.Wrap(err, "failed to open signing audit log"))
+		closer.Bind(func() { _ = auditLog.Close() })
+
+		cosmosNetworkCfg.AuditLog = auditLog
+		ethNetworkCfg.AuditLog = auditLog
+
 		// 1. Connect to Injective network
 
-		cosmosKeyring, err := cosmos.NewKeyring(cosmosKeyringCfg)
-		orShutdown(errors.Wrap(err, "failed to initialize Injective keyring"))
-		log.Infoln("initialized Injective keyring", cosmosKeyring.Addr.String())
-
-		ethKeyFromAddress, signerFn, personalSignFn, err := initEthereumAccountsManager(
-			uint64(*cfg.ethChainID),
-			cfg.ethKeystoreDir,
-			cfg.ethKeyFrom,
-			cfg.ethPassphrase,
-			cfg.ethPrivKey,
-			cfg.ethUseLedger,
+		var (
+			cosmosKeyring     cosmos.Keyring
+			ethKeyFromAddress gethcommon.Address
+			signerFn          bind.SignerFn
+			confirmSigner     peggy.ConfirmSigner
 		)
-		orShutdown(errors.Wrap(err, "failed to initialize Ethereum keyring"))
-		log.Infoln("initialized Ethereum keyring", ethKeyFromAddress.String())
+
+		if len(*cfg.signerAddr) > 0 {
+			signer, err := dialSigner(cfg)
+			orShutdown(err)
+			closer.Bind(func() { _ = signer.Close() })
+
+			cosmosKeyring, err = cosmos.NewTxSignerKeyring(signer.InjectiveTxSigner(), *cfg.cosmosKeyFrom)
+			orShutdown(errors.Wrap(err, "failed to initialize Injective keyring"))
+
+			ethKeyFromAddress, signerFn, confirmSigner = signer.EthereumAddress(), signer.EthereumSignerFn(uint64(*cfg.ethChainID)), signer
+			log.WithFields(log.Fields{"signer": *cfg.signerAddr, "injective_addr": cosmosKeyring.Addr.String(), "eth_addr": ethKeyFromAddress.String()}).Infoln("using keys of peggo signer")
+		} else {
+			cosmosKeyring, err = cosmos.NewKeyring(cosmosKeyringCfg)
+			orShutdown(errors.Wrap(err, "failed to initialize Injective keyring"))
+			log.Infoln("initialized Injective keyring", cosmosKeyring.Addr.String())
+
+			var personalSignFn keystore.PersonalSignFn
+			ethKeyFromAddress, signerFn, personalSignFn, err = initEthereumAccountsManager(
+				uint64(*cfg.ethChainID),
+				cfg.ethKeystoreDir,
+				cfg.ethKeyFrom,
+				cfg.ethPassphrase,
+				cfg.ethPrivKey,
+				cfg.ethUseLedger,
+				cfg.ethRemoteSigner,
+				cfg.ethRemoteSignerAPI,
+				cfg.ethSigner,
+			)
+			orShutdown(errors.Wrap(err, "failed to initialize Ethereum keyring"))
+			log.Infoln("initialized Ethereum keyring", ethKeyFromAddress.String())
+
+			confirmSigner = peggy.PersonalSignConfirmSigner(personalSignFn)
+		}
 
 		cosmosNetworkCfg.ValidatorAddress = cosmosKeyring.Addr.String()
-		cosmosNetwork, err := cosmos.NewNetwork(cosmosKeyring, personalSignFn, cosmosNetworkCfg)
+		cosmosNetwork, err := cosmos.NewNetwork(cosmosKeyring, confirmSigner, cosmosNetworkCfg)
 		orShutdown(err)
 		log.WithFields(log.Fields{"chain_id": *cfg.cosmosChainID, "gas_price": *cfg.cosmosGasPrices}).Infoln("connected to Injective network")
 
@@ -108,7 +157,7 @@ func orchestratorCmd(cmd *cli.Cmd) {
 
 		// 2. Connect to ethereum network
 
-		ethNetwork, err := ethereum.NewNetwork(peggyContractAddr, ethKeyFromAddress, signerFn, ethNetworkCfg)
+		ethNetwork, err := ethereum.NewNetwork(ctx, peggyContractAddr, ethKeyFromAddress, signerFn, ethNetworkCfg)
 		orShutdown(err)
 
 		log.WithFields(log.Fields{
@@ -138,9 +187,65 @@ func orchestratorCmd(cmd *cli.Cmd) {
 			orShutdown(err)
 		}
 
+		if *cfg.ethConfirmationDepth < 0 {
+			orShutdown(errors.New("eth confirmation depth cannot be negative"))
+		}
+
+		ethConfirmations, err := orchestrator.ParseConfirmationPolicy(*cfg.ethConfirmationPolicy, uint64(*cfg.ethConfirmationDepth))
+		orShutdown(err)
+
+		stateStore, err := state.NewStore(*cfg.dataDir)
+		orShutdown(err)
+		closer.Bind(func() { _ = stateStore.Close() })
+
+		log.WithField("data_dir", *cfg.dataDir).Debugln("opened local state store")
+
+		var (
+			outflowLimits *outflow.Limits
+			outflowQueue  *outflow.Queue
+		)
+
+		if len(*cfg.outflowLimits) > 0 {
+			outflowLimits, err = outflow.LoadLimits(*cfg.outflowLimits)
+			orShutdown(err)
+
+			outflowQueue, err = outflow.NewQueue(outflowQueueDir(*cfg.dataDir))
+			orShutdown(err)
+
+			log.WithFields(log.Fields{"file": *cfg.outflowLimits, "tokens": len(outflowLimits.Tokens), "default": outflowLimits.Default != nil}).Infoln("loaded outflow limits")
+		}
+
+		var forceBatchAge time.Duration
+		if len(*cfg.forceBatchAge) > 0 {
+			forceBatchAge, err = time.ParseDuration(*cfg.forceBatchAge)
+			orShutdown(errors.Wrap(err, "invalid --batch-force-age"))
+		}
+
+		if *cfg.maxForcedBatches < 0 {
+			orShutdown(errors.New("--batch-max-forced cannot be negative"))
+		}
+
+		batchRequestGrace, err := time.ParseDuration(*cfg.batchRequestGrace)
+		orShutdown(errors.Wrap(err, "invalid --batch-request-grace"))
+
+		var batchPolicies *batchpolicy.Policies
+		if len(*cfg.batchPolicies) > 0 {
+			batchPolicies, err = batchpolicy.LoadPolicies(*cfg.batchPolicies)
+			orShutdown(err)
+
+			log.WithFields(log.Fields{"file": *cfg.batchPolicies, "tokens": len(batchPolicies.Tokens), "default": batchPolicies.Default != nil}).Infoln("loaded batch policies")
+		}
+
+		batchGas := orchestrator.BatchGas{
+			Base:         uint64(*cfg.batchGasBase),
+			PerValidator: uint64(*cfg.batchGasPerValidator),
+			PerTx:        uint64(*cfg.batchGasPerTx),
+		}
+
 		orchestratorCfg := orchestrator.Config{
 			CosmosAddr:           cosmosKeyring.Addr,
 			EthereumAddr:         ethKeyFromAddress,
+			PeggyContractAddr:    peggyContractAddr,
 			MinBatchFeeUSD:       *cfg.minBatchFeeUSD,
 			ERC20ContractMapping: erc20ContractMapping,
 			RelayValsetOffsetDur: valsetDur,
@@ -148,6 +253,17 @@ func orchestratorCmd(cmd *cli.Cmd) {
 			RelayValsets:         *cfg.relayValsets,
 			RelayBatches:         *cfg.relayBatches,
 			RelayerMode:          !isValidator,
+			EthConfirmations:     ethConfirmations,
+			OutflowLimits:        outflowLimits,
+			OutflowQueue:         outflowQueue,
+			BatchPolicies:        batchPolicies,
+			BatchGasMargin:       *cfg.batchGasMargin,
+			BatchGas:             batchGas,
+			ForceBatchAge:        forceBatchAge,
+			MaxForcedBatches:     *cfg.maxForcedBatches,
+			BatchRequestGrace:    batchRequestGrace,
+			RelayBatchMargin:     *cfg.relayBatchMargin,
+			GasPriceAdjustment:   *cfg.ethGasPriceAdjustment,
 		}
 
 		// Create peggo and run it
@@ -155,6 +271,7 @@ func orchestratorCmd(cmd *cli.Cmd) {
 			cosmosNetwork,
 			ethNetwork,
 			pricefeed.NewCoingeckoPriceFeed(100, &pricefeed.Config{BaseURL: *cfg.coingeckoApi}),
+			stateStore,
 			orchestratorCfg,
 		)
 		orShutdown(err)
@@ -169,3 +286,37 @@ func orchestratorCmd(cmd *cli.Cmd) {
 		closer.Hold()
 	}
 }
+
+// dialSigner connects to the peggo signer and checks that it signs for the configured networks and keys
+func dialSigner(cfg Config) (*signerd.Client, error) {
+	var tlsCfg *tls.Config
+	if *cfg.signerInsecure {
+		log.Warningln("connecting to peggo signer over plaintext gRPC, make sure the link is trusted")
+	} else {
+		var err error
+		if tlsCfg, err = signerd.ClientTLSConfig(*cfg.signerTLSCA, *cfg.signerTLSCert, *cfg.signerTLSKey); err != nil {
+			return nil, err
+		}
+	}
+
+	signer, err := signerd.Dial(*cfg.signerAddr, tlsCfg, signerd.DefaultRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case signer.EthereumChainID() != uint64(*cfg.ethChainID):
+		err = errors.Errorf("peggo signer signs for Ethereum chain ID %d, not %d", signer.EthereumChainID(), *cfg.ethChainID)
+	case signer.InjectiveChainID() != *cfg.cosmosChainID:
+		err = errors.Errorf("peggo signer signs for Injective chain ID %s, not %s", signer.InjectiveChainID(), *cfg.cosmosChainID)
+	case len(*cfg.ethKeyFrom) > 0 && gethcommon.HexToAddress(*cfg.ethKeyFrom) != signer.EthereumAddress():
+		err = errors.Errorf("peggo signer holds Ethereum key %s, not %s", signer.EthereumAddress().Hex(), *cfg.ethKeyFrom)
+	}
+
+	if err != nil {
+		_ = signer.Close()
+		return nil, err
+	}
+
+	return signer, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strconv"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	cli "github.com/jawher/mow.cli"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/outflow"
+)
+
+// outflowCmdSubset contains actions on the batches held by the outflow limits of the signer. They work on the
+// files in <data-dir>/outflow, so they can run along the orchestrator.
+//
+// $ peggo outflow
+func outflowCmdSubset(cmd *cli.Cmd) {
+	cmd.Command(
+		"held",
+		"Lists the batches held by the outflow limits",
+		listHeldBatchesCmd,
+	)
+
+	cmd.Command(
+		"approve",
+		"Approves a held batch, the orchestrator signs it in its next loop",
+		approveHeldBatchCmd,
+	)
+}
+
+func listHeldBatchesCmd(cmd *cli.Cmd) {
+	var dataDir *string
+
+	initStateOptions(cmd, &dataDir)
+
+	cmd.Action = func() {
+		queue, err := outflow.NewQueue(outflowQueueDir(*dataDir))
+		orShutdown(err)
+
+		held, err := queue.Held()
+		orShutdown(err)
+
+		if len(held) == 0 {
+			log.Infoln("no batch is held")
+			return
+		}
+
+		for _, b := range held {
+			fields := log.Fields{
+				"token_contract": b.TokenContract,
+				"batch_nonce":    b.BatchNonce,
+				"amount":         b.Amount.String(),
+				"digest":         b.Digest.Hex(),
+				"held_at":        b.HeldAt,
+				"reason":         b.Reason,
+			}
+
+			if b.USDValue != nil {
+				fields["usd_value"] = b.USDValue.StringFixed(2)
+			}
+
+			log.WithFields(fields).Infoln("held batch")
+		}
+	}
+}
+
+func approveHeldBatchCmd(cmd *cli.Cmd) {
+	var dataDir *string
+
+	initStateOptions(cmd, &dataDir)
+
+	tokenContract := cmd.StringArg("TOKEN_CONTRACT", "", "ERC20 contract of the held batch")
+	batchNonce := cmd.StringArg("BATCH_NONCE", "", "Nonce of the held batch")
+
+	cmd.Action = func() {
+		if !gethcommon.IsHexAddress(*tokenContract) {
+			orShutdown(errors.Errorf("invalid token contract %q", *tokenContract))
+		}
+
+		nonce, err := strconv.ParseUint(*batchNonce, 10, 64)
+		orShutdown(errors.Wrap(err, "invalid batch nonce"))
+
+		queue, err := outflow.NewQueue(outflowQueueDir(*dataDir))
+		orShutdown(err)
+
+		b, err := queue.Approve(*tokenContract, nonce)
+		orShutdown(err)
+
+		log.WithFields(log.Fields{
+			"token_contract": b.TokenContract,
+			"batch_nonce":    b.BatchNonce,
+			"amount":         b.Amount.String(),
+			"digest":         b.Digest.Hex(),
+		}).Infoln("approved held batch")
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"net"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	cli "github.com/jawher/mow.cli"
+	"github.com/pkg/errors"
+	"github.com/xlab/closer"
+	log "github.com/xlab/suplog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos"
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd"
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd/signerpb"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	"github.com/InjectiveLabs/peggo/orchestrator/version"
+)
+
+// signerCmd action runs the signer daemon: it holds the Ethereum and Injective keys of the orchestrator
+// and signs checkpoints and transactions for it over gRPC, without talking to any RPC endpoint.
+//
+// $ peggo signer
+func signerCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+		cosmosKeyFrom        *string
+		cosmosKeyPassphrase  *string
+		cosmosPrivKey        *string
+		cosmosUseLedger      *bool
+		cosmosSigner         *string
+
+		// Ethereum Key Management
+		ethKeystoreDir     *string
+		ethKeyFrom         *string
+		ethPassphrase      *string
+		ethPrivKey         *string
+		ethUseLedger       *bool
+		ethRemoteSigner    *string
+		ethRemoteSignerAPI *string
+		ethSigner          *string
+
+		// Local state
+		dataDir *string
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+		&cosmosSigner,
+	)
+
+	initEthereumKeyOptions(
+		cmd,
+		&ethKeystoreDir,
+		&ethKeyFrom,
+		&ethPassphrase,
+		&ethPrivKey,
+		&ethUseLedger,
+		&ethRemoteSigner,
+		&ethRemoteSignerAPI,
+		&ethSigner,
+	)
+
+	initStateOptions(cmd, &dataDir)
+
+	cosmosChainID := cmd.String(cli.StringOpt{
+		Name:   "cosmos-chain-id",
+		Desc:   "Specify Chain ID of the Cosmos network. Injective transactions for other chains are refused.",
+		EnvVar: "PEGGO_COSMOS_CHAIN_ID",
+		Value:  "888",
+	})
+
+	ethChainID := cmd.Int(cli.IntOpt{
+		Name:   "eth-chain-id",
+		Desc:   "Specify Chain ID of the Ethereum network. Ethereum transactions for other chains are refused.",
+		EnvVar: "PEGGO_ETH_CHAIN_ID",
+		Value:  42,
+	})
+
+	peggyID := cmd.String(cli.StringOpt{
+		Name:   "peggy-id",
+		Desc:   "Specify the peggy ID (bytes32 hex) of the Peggy contract. Checkpoints for other peggy IDs are refused.",
+		EnvVar: "PEGGO_PEGGY_ID",
+	})
+
+	peggyContract := cmd.String(cli.StringOpt{
+		Name:   "peggy-contract",
+		Desc:   "Specify the address of the Peggy contract. Ethereum transactions to other addresses are refused.",
+		EnvVar: "PEGGO_PEGGY_CONTRACT",
+	})
+
+	listenAddr := cmd.String(cli.StringOpt{
+		Name:   "listen",
+		Desc:   "Specify the address the signer gRPC server listens on.",
+		EnvVar: "PEGGO_SIGNER_LISTEN",
+		Value:  "127.0.0.1:9400",
+	})
+
+	tlsCert := cmd.String(cli.StringOpt{
+		Name:   "tls-cert",
+		Desc:   "Specify the TLS certificate of the signer gRPC server.",
+		EnvVar: "PEGGO_SIGNER_TLS_CERT",
+	})
+
+	tlsKey := cmd.String(cli.StringOpt{
+		Name:   "tls-key",
+		Desc:   "Specify the TLS private key of the signer gRPC server.",
+		EnvVar: "PEGGO_SIGNER_TLS_KEY",
+	})
+
+	tlsClientCA := cmd.String(cli.StringOpt{
+		Name:   "tls-client-ca",
+		Desc:   "If set, orchestrators must present a TLS client certificate signed by one of the CAs of this file.",
+		EnvVar: "PEGGO_SIGNER_TLS_CLIENT_CA",
+	})
+
+	insecure := cmd.Bool(cli.BoolOpt{
+		Name:   "insecure",
+		Desc:   "Serve plaintext gRPC without TLS. Only use over a trusted private link.",
+		EnvVar: "PEGGO_SIGNER_INSECURE",
+		Value:  false,
+	})
+
+	cmd.Before = func() {
+		initMetrics(cmd)
+	}
+
+	cmd.Action = func() {
+		// ensure a clean exit
+		defer closer.Close()
+
+		log.WithFields(log.Fields{
+			"version":    version.AppVersion,
+			"git":        version.GitCommit,
+			"build_date": version.BuildDate,
+		}).Infoln("Peggo signer - holds the orchestrator keys and signs for it")
+
+		if *cosmosUseLedger || *ethUseLedger {
+			log.Fatalln("cannot use Ledger for the signer, since signatures must be realtime")
+		}
+
+		if len(*peggyID) == 0 {
+			log.Fatalln("peggy ID must be set with --peggy-id")
+		}
+
+		if !gethcommon.IsHexAddress(*peggyContract) {
+			log.Fatalln("Peggy contract address must be set with --peggy-contract")
+		}
+
+		peggyIDBytes, err := hexToBytes(*peggyID)
+		orShutdown(errors.Wrap(err, "invalid peggy ID"))
+
+		if len(peggyIDBytes) != gethcommon.HashLength {
+			orShutdown(errors.Errorf("peggy ID must be %d bytes long", gethcommon.HashLength))
+		}
+
+		cosmosKeyring, err := cosmos.NewKeyring(cosmos.KeyringConfig{
+			KeyringDir:     *cosmosKeyringDir,
+			KeyringAppName: *cosmosKeyringAppName,
+			KeyringBackend: *cosmosKeyringBackend,
+			KeyFrom:        *cosmosKeyFrom,
+			KeyPassphrase:  *cosmosKeyPassphrase,
+			PrivateKey:     *cosmosPrivKey,
+			SignerURI:      *cosmosSigner,
+			UseLedger:      *cosmosUseLedger,
+		})
+		orShutdown(errors.Wrap(err, "failed to initialize Injective keyring"))
+		log.Infoln("initialized Injective keyring", cosmosKeyring.Addr.String())
+
+		ethKeyFromAddress, signerFn, personalSignFn, err := initEthereumAccountsManager(
+			uint64(*ethChainID),
+			ethKeystoreDir,
+			ethKeyFrom,
+			ethPassphrase,
+			ethPrivKey,
+			ethUseLedger,
+			ethRemoteSigner,
+			ethRemoteSignerAPI,
+			ethSigner,
+		)
+		orShutdown(errors.Wrap(err, "failed to initialize Ethereum keyring"))
+		log.Infoln("initialized Ethereum keyring", ethKeyFromAddress.String())
+
+		stateStore, err := state.NewStore(*dataDir)
+		orShutdown(err)
+		closer.Bind(func() { _ = stateStore.Close() })
+
+		auditLog, err := audit.Open(auditLogDir(*dataDir), audit.DefaultMaxFileSize)
+		orShutdown(errors.Wrap(err, "failed to open signing audit log"))
+		closer.Bind(func() { _ = auditLog.Close() })
+
+		server, err := signerd.NewServer(signerd.Config{
+			PeggyID:                gethcommon.BytesToHash(peggyIDBytes),
+			EthereumChainID:        uint64(*ethChainID),
+			InjectiveChainID:       *cosmosChainID,
+			PeggyContractAddr:      gethcommon.HexToAddress(*peggyContract),
+			EthereumAddr:           ethKeyFromAddress,
+			EthereumSignerFn:       signerFn,
+			EthereumPersonalSignFn: personalSignFn,
+			InjectiveKeyring:       cosmosKeyring,
+			InjectiveAddr:          cosmosKeyring.Addr,
+			SlashingProtection:     stateStore,
+			AuditLog:               auditLog,
+		})
+		orShutdown(err)
+
+		var opts []grpc.ServerOption
+		switch {
+		case *insecure:
+			log.Warningln("serving plaintext gRPC, make sure the link to the orchestrator is trusted")
+		case len(*tlsCert) == 0 || len(*tlsKey) == 0:
+			orShutdown(errors.New("TLS certificate and key must be set with --tls-cert and --tls-key, or use --insecure"))
+		default:
+			tlsCfg, err := signerd.ServerTLSConfig(*tlsCert, *tlsKey, *tlsClientCA)
+			orShutdown(err)
+
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+		}
+
+		grpcServer := grpc.NewServer(opts...)
+		signerpb.RegisterSignerServer(grpcServer, server)
+		closer.Bind(grpcServer.GracefulStop)
+
+		listener, err := net.Listen("tcp", *listenAddr)
+		orShutdown(errors.Wrap(err, "failed to listen"))
+
+		log.WithFields(log.Fields{
+			"listen":          *listenAddr,
+			"peggy_id":        *peggyID,
+			"peggy_contract":  *peggyContract,
+			"eth_chain_id":    *ethChainID,
+			"cosmos_chain_id": *cosmosChainID,
+		}).Infoln("signer is ready")
+
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.WithError(err).Errorln("signer gRPC server stopped")
+				closer.Close()
+			}
+		}()
+
+		closer.Hold()
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	cli "github.com/jawher/mow.cli"
+	"github.com/xlab/closer"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+)
+
+// slashingProtectionCmdSubset contains actions that move the slashing protection database
+// (every valset and batch checkpoint signed with the Ethereum key) between hosts.
+//
+// $ peggo slashing-protection
+func slashingProtectionCmdSubset(cmd *cli.Cmd) {
+	cmd.Command(
+		"export",
+		"Exports the checkpoints signed on this host",
+		exportSlashingProtectionCmd,
+	)
+
+	cmd.Command(
+		"import",
+		"Imports checkpoints signed on another host, refusing any that conflicts with the local ones",
+		importSlashingProtectionCmd,
+	)
+}
+
+func exportSlashingProtectionCmd(cmd *cli.Cmd) {
+	var dataDir *string
+
+	initStateOptions(cmd, &dataDir)
+
+	file := cmd.StringOpt("file", "", "Write the interchange file to the given path instead of stdout")
+
+	cmd.Action = func() {
+		// ensure a clean exit
+		defer closer.Close()
+
+		store, err := state.NewStore(*dataDir)
+		orShutdown(err)
+		closer.Bind(func() { _ = store.Close() })
+
+		var w io.Writer = os.Stdout
+		if *file != "" {
+			f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			orShutdown(err)
+			closer.Bind(func() { _ = f.Close() })
+
+			w = f
+		}
+
+		if err := store.ExportSignedDigests(w); err != nil {
+			log.WithError(err).Fatalln("failed to export slashing protection database")
+		}
+	}
+}
+
+func importSlashingProtectionCmd(cmd *cli.Cmd) {
+	var dataDir *string
+
+	initStateOptions(cmd, &dataDir)
+
+	file := cmd.StringArg("FILE", "", "Interchange file exported by another host")
+
+	cmd.Action = func() {
+		// ensure a clean exit
+		defer closer.Close()
+
+		store, err := state.NewStore(*dataDir)
+		orShutdown(err)
+		closer.Bind(func() { _ = store.Close() })
+
+		f, err := os.Open(*file)
+		orShutdown(err)
+		closer.Bind(func() { _ = f.Close() })
+
+		n, err := store.ImportSignedDigests(f)
+		if err != nil {
+			log.WithError(err).Fatalln("failed to import slashing protection database")
+		}
+
+		log.WithField("data_dir", *dataDir).Infof("imported %d signed checkpoints", n)
+	}
+}
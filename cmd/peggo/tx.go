@@ -23,6 +23,24 @@ func txCmdSubset(cmd *cli.Cmd) {
 		"Submits an Ethereum key that will be used to sign messages on behalf of your Validator",
 		registerEthKeyCmd,
 	)
+
+	cmd.Command(
+		"export-confirms",
+		"Exports the valsets and batches pending confirmation, for signing on an offline host",
+		exportConfirmsCmd,
+	)
+
+	cmd.Command(
+		"sign-confirms",
+		"Signs an exported bundle of valsets and batches with the Ethereum key, without network access",
+		signConfirmsCmd,
+	)
+
+	cmd.Command(
+		"import-confirms",
+		"Broadcasts the valset and batch confirmations of a signed bundle",
+		importConfirmsCmd,
+	)
 }
 
 func registerEthKeyCmd(cmd *cli.Cmd) {
@@ -42,6 +60,7 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 		cosmosKeyPassphrase *string
 		cosmosPrivKey       *string
 		cosmosUseLedger     *bool
+		cosmosSigner        *string
 
 		// Ethereum Key Management
 		ethKeystoreDir *string
@@ -50,6 +69,10 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 		ethPrivKey     *string
 		ethUseLedger   *bool
 
+		ethRemoteSigner    *string
+		ethRemoteSignerAPI *string
+		ethSigner          *string
+
 		// Misc
 		alwaysAutoConfirm *bool
 	)
@@ -71,6 +94,7 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 		&cosmosKeyPassphrase,
 		&cosmosPrivKey,
 		&cosmosUseLedger,
+		&cosmosSigner,
 	)
 
 	initEthereumKeyOptions(
@@ -80,6 +104,9 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 		&ethPassphrase,
 		&ethPrivKey,
 		&ethUseLedger,
+		&ethRemoteSigner,
+		&ethRemoteSignerAPI,
+		&ethSigner,
 	)
 
 	initInteractiveOptions(
@@ -102,6 +129,7 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 			KeyFrom:        *cosmosKeyFrom,
 			KeyPassphrase:  *cosmosKeyPassphrase,
 			PrivateKey:     *cosmosPrivKey,
+			SignerURI:      *cosmosSigner,
 			UseLedger:      *cosmosUseLedger,
 		}
 
@@ -115,6 +143,9 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 			ethPassphrase,
 			ethPrivKey,
 			ethUseLedger,
+			ethRemoteSigner,
+			ethRemoteSignerAPI,
+			ethSigner,
 		)
 		if err != nil {
 			log.WithError(err).Fatalln("failed to init Ethereum account")
@@ -128,7 +159,7 @@ func registerEthKeyCmd(cmd *cli.Cmd) {
 			return
 		}
 
-		net, err := cosmos.NewNetwork(keyring, personalSignFn, cosmos.NetworkConfig{
+		net, err := cosmos.NewNetwork(keyring, peggy.PersonalSignConfirmSigner(personalSignFn), cosmos.NetworkConfig{
 			ChainID:          *cosmosChainID,
 			ValidatorAddress: keyring.Addr.String(),
 			CosmosGRPC:       *cosmosGRPC,
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -116,6 +117,25 @@ func checkStatsdPrefix(s string) string {
 	return s
 }
 
+// defaultDataDir returns the default location of peggo's local state.
+func defaultDataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".peggo"
+	}
+
+	return filepath.Join(home, ".peggo")
+}
+
+// auditLogDir returns the directory of the signing audit log, kept next to the local state
+func auditLogDir(dataDir string) string {
+	return filepath.Join(dataDir, "audit")
+}
+
+func outflowQueueDir(dataDir string) string {
+	return filepath.Join(dataDir, "outflow")
+}
+
 func hexToBytes(str string) ([]byte, error) {
 	if strings.HasPrefix(str, "0x") {
 		str = str[2:]
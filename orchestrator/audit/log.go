@@ -0,0 +1,391 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+const (
+	currentFileName = "audit.log"
+	rotatedPrefix   = "audit-"
+	rotatedSuffix   = ".log"
+
+	// DefaultMaxFileSize is the size (in bytes) above which the current log file is rotated
+	DefaultMaxFileSize = 16 * 1024 * 1024
+
+	// longest accepted record line
+	maxRecordSize = 64 * 1024
+)
+
+// Kind is the kind of signature recorded in the audit log.
+type Kind string
+
+const (
+	ValsetConfirm Kind = "valset_confirm"
+	BatchConfirm  Kind = "batch_confirm"
+	EthTx         Kind = "eth_tx"
+)
+
+// Record is an entry of the audit log. Every record includes the hash of the previous one, so that removing,
+// reordering or altering records breaks the chain.
+type Record struct {
+	Seq           uint64             `json:"seq"`
+	Kind          Kind               `json:"kind"`
+	Digest        gethcommon.Hash    `json:"digest"`
+	Nonce         uint64             `json:"nonce"`
+	TokenContract string             `json:"token_contract,omitempty"`
+	Signer        gethcommon.Address `json:"signer"`
+	TxHash        string             `json:"tx_hash,omitempty"`
+	Error         string             `json:"error,omitempty"`
+	Timestamp     time.Time          `json:"timestamp"`
+	PrevHash      gethcommon.Hash    `json:"prev_hash"`
+	Hash          gethcommon.Hash    `json:"hash"`
+}
+
+// hash returns the hash of the record, computed over all its fields except the hash itself
+func (r Record) hash() (gethcommon.Hash, error) {
+	r.Hash = gethcommon.Hash{}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+
+	return sha256.Sum256(data), nil
+}
+
+// Log is an append-only, hash-chained log of the signatures produced by the orchestrator. Records are written
+// as JSON lines to audit.log in the log directory, which is rotated to audit-<first seq>.log once it grows
+// above the max file size. Rotated files are never removed. A nil Log discards all records.
+type Log struct {
+	mux sync.Mutex
+
+	dir         string
+	maxFileSize int64
+	file        *os.File
+	size        int64
+	lastSeq     uint64
+	lastHash    gethcommon.Hash
+
+	svcTags metrics.Tags
+}
+
+// Open opens (or creates) the audit log in dir and restores the head of the hash chain.
+func Open(dir string, maxFileSize int64) (*Log, error) {
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxFileSize
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create audit log dir %s", dir)
+	}
+
+	l := &Log{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		svcTags:     metrics.Tags{"svc": "audit_log"},
+	}
+
+	files, err := logFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// the head of the chain is the last record of the newest non-empty file
+	for i := len(files) - 1; i >= 0; i-- {
+		last, ok, err := lastRecord(files[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			l.lastSeq, l.lastHash = last.Seq, last.Hash
+			break
+		}
+	}
+
+	if err := l.openCurrent(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Append adds the record to the log, filling in its sequence number, timestamp and hashes. Errors are
+// logged and reported, the signature being recorded was already produced.
+func (l *Log) Append(r Record) {
+	if l == nil {
+		return
+	}
+
+	if err := l.append(r); err != nil {
+		log.WithError(err).WithFields(log.Fields{"kind": r.Kind, "nonce": r.Nonce}).Errorln("failed to write signing audit record")
+		metrics.ReportFuncError(l.svcTags)
+	}
+}
+
+func (l *Log) append(r Record) error {
+	metrics.ReportFuncCall(l.svcTags)
+	doneFn := metrics.ReportFuncTiming(l.svcTags)
+	defer doneFn()
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.size >= l.maxFileSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	r.Seq = l.lastSeq + 1
+	r.PrevHash = l.lastHash
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC().Round(0)
+	}
+
+	hash, err := r.hash()
+	if err != nil {
+		return errors.Wrap(err, "failed to hash audit record")
+	}
+
+	r.Hash = hash
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode audit record")
+	}
+
+	n, err := l.file.Write(append(data, '\n'))
+	l.size += int64(n)
+	if err != nil {
+		return errors.Wrap(err, "failed to write audit record")
+	}
+
+	if err := l.file.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync audit log")
+	}
+
+	l.lastSeq, l.lastHash = r.Seq, r.Hash
+
+	return nil
+}
+
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return l.file.Close()
+}
+
+func (l *Log) openCurrent() error {
+	f, err := os.OpenFile(filepath.Join(l.dir, currentFileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open audit log")
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to stat audit log")
+	}
+
+	l.file, l.size = f, info.Size()
+
+	return nil
+}
+
+// rotate renames the current file after the sequence number of its first record and starts a new one
+func (l *Log) rotate() error {
+	current := filepath.Join(l.dir, currentFileName)
+
+	first, ok, err := firstRecord(current)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	if err := l.file.Close(); err != nil {
+		return errors.Wrap(err, "failed to close audit log")
+	}
+
+	if err := os.Rename(current, filepath.Join(l.dir, rotatedFileName(first.Seq))); err != nil {
+		return errors.Wrap(err, "failed to rotate audit log")
+	}
+
+	return l.openCurrent()
+}
+
+// Verify checks the hash chain of the audit log in dir. It returns the number of records and the last one.
+func Verify(dir string) (int, *Record, error) {
+	files, err := logFiles(dir)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		count int
+		prev  *Record
+	)
+
+	for _, file := range files {
+		err := readRecords(file, func(r Record, line int) error {
+			where := fmt.Sprintf("%s:%d", filepath.Base(file), line)
+
+			hash, err := r.hash()
+			if err != nil {
+				return errors.Wrapf(err, "%s: failed to hash record", where)
+			}
+
+			if hash != r.Hash {
+				return errors.Errorf("%s: record %d was altered, hash %s does not match its content (%s)", where, r.Seq, r.Hash.Hex(), hash.Hex())
+			}
+
+			switch {
+			case prev == nil && r.PrevHash != (gethcommon.Hash{}):
+				return errors.Errorf("%s: first record %d does not start the chain, records before it are missing", where, r.Seq)
+			case prev != nil && r.Seq != prev.Seq+1:
+				return errors.Errorf("%s: record %d follows record %d", where, r.Seq, prev.Seq)
+			case prev != nil && r.PrevHash != prev.Hash:
+				return errors.Errorf("%s: record %d does not chain to record %d", where, r.Seq, prev.Seq)
+			}
+
+			record := r
+			prev = &record
+			count++
+
+			return nil
+		})
+
+		if err != nil {
+			return count, prev, err
+		}
+	}
+
+	return count, prev, nil
+}
+
+// logFiles returns the rotated files in order, followed by the current one
+func logFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read audit log dir %s", dir)
+	}
+
+	var (
+		files   []string
+		current bool
+	)
+
+	for _, e := range entries {
+		switch name := e.Name(); {
+		case e.IsDir():
+		case name == currentFileName:
+			current = true
+		case strings.HasPrefix(name, rotatedPrefix) && strings.HasSuffix(name, rotatedSuffix):
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	// zero padded sequence numbers sort lexically
+	sort.Strings(files)
+
+	if current {
+		files = append(files, filepath.Join(dir, currentFileName))
+	}
+
+	return files, nil
+}
+
+func rotatedFileName(firstSeq uint64) string {
+	return fmt.Sprintf("%s%020d%s", rotatedPrefix, firstSeq, rotatedSuffix)
+}
+
+func readRecords(file string, fn func(r Record, line int) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", file)
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), maxRecordSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var r Record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return errors.Wrapf(err, "%s:%d: failed to decode record", filepath.Base(file), line)
+		}
+
+		if err := fn(r, line); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed to read %s", file)
+	}
+
+	return nil
+}
+
+func firstRecord(file string) (Record, bool, error) {
+	var (
+		first Record
+		found bool
+		stop  = errors.New("stop")
+	)
+
+	err := readRecords(file, func(r Record, _ int) error {
+		first, found = r, true
+		return stop
+	})
+
+	if err != nil && !errors.Is(err, stop) {
+		return Record{}, false, err
+	}
+
+	return first, found, nil
+}
+
+func lastRecord(file string) (Record, bool, error) {
+	var (
+		last  Record
+		found bool
+	)
+
+	err := readRecords(file, func(r Record, _ int) error {
+		last, found = r, true
+		return nil
+	})
+
+	return last, found, err
+}
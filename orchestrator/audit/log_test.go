@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendRecords(t *testing.T, dir string, maxFileSize int64, nonces ...uint64) {
+	t.Helper()
+
+	l, err := Open(dir, maxFileSize)
+	require.NoError(t, err)
+	defer l.Close()
+
+	for _, nonce := range nonces {
+		l.Append(Record{
+			Kind:          BatchConfirm,
+			Digest:        gethcommon.BigToHash(gethcommon.Big1),
+			Nonce:         nonce,
+			TokenContract: "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			Signer:        gethcommon.HexToAddress("0x76D2dDbb89C36FA39FAa5c5e7C61ee95AC4D76C4"),
+			TxHash:        "DEADBEEF",
+		})
+	}
+}
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+
+	// small files rotate after every record
+	appendRecords(t, dir, 1, 1, 2, 3)
+
+	// the chain continues after reopening the log
+	appendRecords(t, dir, 1, 4, 5)
+
+	files, err := logFiles(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 5)
+
+	n, last, err := Verify(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, uint64(5), last.Seq)
+	assert.Equal(t, uint64(5), last.Nonce)
+}
+
+func TestVerify(t *testing.T) {
+	testCases := []struct {
+		name   string
+		tamper func(t *testing.T, lines []string) []string
+		err    string
+	}{
+		{
+			name:   "untouched",
+			tamper: func(_ *testing.T, lines []string) []string { return lines },
+		},
+		{
+			name: "altered record",
+			tamper: func(_ *testing.T, lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"nonce":2`, `"nonce":7`, 1)
+				return lines
+			},
+			err: "record 2 was altered",
+		},
+		{
+			name: "removed record",
+			tamper: func(_ *testing.T, lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			err: "record 3 follows record 1",
+		},
+		{
+			name: "removed first record",
+			tamper: func(_ *testing.T, lines []string) []string {
+				return lines[1:]
+			},
+			err: "first record 2 does not start the chain",
+		},
+		{
+			name: "reordered records",
+			tamper: func(_ *testing.T, lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			err: "record 3 follows record 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			appendRecords(t, dir, DefaultMaxFileSize, 1, 2, 3)
+
+			file := filepath.Join(dir, currentFileName)
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			lines := tc.tamper(t, strings.Split(strings.TrimSpace(string(data)), "\n"))
+			require.NoError(t, os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+
+			_, _, err = Verify(dir)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
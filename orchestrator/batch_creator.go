@@ -2,16 +2,25 @@ package orchestrator
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"time"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/shopspring/decimal"
 	log "github.com/xlab/suplog"
 
 	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/batchpolicy"
 	"github.com/InjectiveLabs/peggo/orchestrator/loops"
 	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
 )
 
+// unbatchedTransfersTTL is how long the withdrawals in the pool are cached, querying them reads the whole Peggy
+// module state
+const unbatchedTransfersTTL = 5 * time.Minute
+
 func (s *Orchestrator) runBatchCreator(ctx context.Context) (err error) {
 	bc := batchCreator{Orchestrator: s}
 	s.logger.WithField("loop_duration", defaultLoopDur.String()).Debugln("starting BatchCreator...")
@@ -23,6 +32,47 @@ func (s *Orchestrator) runBatchCreator(ctx context.Context) (err error) {
 
 type batchCreator struct {
 	*Orchestrator
+
+	// firstSeen is when each withdrawal in the pool was first seen by the loop. The chain does not record when a
+	// withdrawal was made, so its age is counted from there.
+	firstSeen map[uint64]time.Time
+
+	// forced are the times batches were forced because of the withdrawal age, by token
+	forced map[gethcommon.Address][]time.Time
+
+	// wantedSince is when each token was first found worth a batch by an instance not elected to request it
+	wantedSince map[gethcommon.Address]time.Time
+
+	// unbatched are the withdrawals in the pool as of unbatchedAt. They are read from the whole module state, so
+	// they are queried at most once per unbatchedTransfersTTL, or again once a batch is requested or the pending
+	// batches change.
+	unbatched   []*peggytypes.OutgoingTransferTx
+	unbatchedAt time.Time
+
+	// pendingBatches are the pending batches seen by the previous loop
+	pendingBatches map[gethcommon.Address]uint64
+
+	progressLoaded bool
+}
+
+// pendingWithdrawals are the withdrawals of a token waiting in the pool
+type pendingWithdrawals struct {
+	count     int
+	firstSeen time.Time
+
+	// fees of the withdrawals, only the highest ones make it into the next batch
+	fees []*big.Int
+}
+
+// batchRound is what a loop knows about every token. Each field is nil if not needed or not known.
+type batchRound struct {
+	withdrawals    map[gethcommon.Address]pendingWithdrawals
+	pendingBatches map[gethcommon.Address]uint64
+	valset         *peggytypes.Valset
+	gasCost        *batchGasCost
+
+	// wanted are the tokens waiting for their elected requester
+	wanted map[gethcommon.Address]bool
 }
 
 func (l *batchCreator) Log() log.Logger {
@@ -34,6 +84,8 @@ func (l *batchCreator) requestTokenBatches(ctx context.Context) error {
 	doneFn := metrics.ReportFuncTiming(l.svcTags)
 	defer doneFn()
 
+	l.loadProgress()
+
 	fees, err := l.getUnbatchedTokenFees(ctx)
 	if err != nil {
 		l.Log().WithError(err).Warningln("failed to get withdrawal fees")
@@ -45,8 +97,41 @@ func (l *batchCreator) requestTokenBatches(ctx context.Context) error {
 		return nil
 	}
 
+	// the pending batches are queried first, withdrawals leave the pool (or return to it) when they change
+	pendingBatches := l.getPendingBatches(ctx)
+	if pendingBatches != nil {
+		if !maps.Equal(pendingBatches, l.pendingBatches) {
+			l.unbatchedAt = time.Time{}
+		}
+
+		l.pendingBatches = pendingBatches
+	}
+
+	round := &batchRound{
+		withdrawals:    l.getPendingWithdrawals(ctx, fees),
+		pendingBatches: pendingBatches,
+		wanted:         make(map[gethcommon.Address]bool),
+	}
+
+	if l.cfg.BatchGasMargin > 0 || l.cfg.BatchRequestGrace > 0 {
+		round.valset = l.getCurrentValset(ctx)
+	}
+
+	if l.cfg.BatchGasMargin > 0 {
+		if round.gasCost, err = l.getBatchGasCost(ctx, round.valset); err != nil {
+			l.Log().WithError(err).Warningln("failed to estimate batch gas cost")
+		}
+	}
+
 	for _, fee := range fees {
-		l.requestTokenBatch(ctx, fee)
+		l.requestTokenBatch(ctx, fee, round)
+	}
+
+	// tokens no longer worth a batch start waiting over
+	for token := range l.wantedSince {
+		if !round.wanted[token] {
+			delete(l.wantedSince, token)
+		}
 	}
 
 	return nil
@@ -66,9 +151,105 @@ func (l *batchCreator) getUnbatchedTokenFees(ctx context.Context) ([]*peggytypes
 	return fees, nil
 }
 
-func (l *batchCreator) requestTokenBatch(ctx context.Context, fee *peggytypes.BatchFees) {
+// getPendingWithdrawals returns the withdrawals in the pool by token, if the policy of any token needs them. It
+// returns nil if they could not be queried.
+func (l *batchCreator) getPendingWithdrawals(ctx context.Context, fees []*peggytypes.BatchFees) map[gethcommon.Address]pendingWithdrawals {
+	needed := false
+	for _, fee := range fees {
+		if l.needsWithdrawals(l.tokenPolicy(gethcommon.HexToAddress(fee.Token))) {
+			needed = true
+			break
+		}
+	}
+
+	if !needed {
+		return nil
+	}
+
+	txs, err := l.getUnbatchedTransfers(ctx)
+	if err != nil {
+		l.Log().WithError(err).Warningln("failed to get unbatched withdrawals")
+		return nil
+	}
+
+	var (
+		now         = time.Now()
+		firstSeen   = make(map[uint64]time.Time, len(txs))
+		withdrawals = make(map[gethcommon.Address]pendingWithdrawals)
+	)
+
+	for _, tx := range txs {
+		if tx.Erc20Token == nil {
+			continue
+		}
+
+		seen, ok := l.firstSeen[tx.Id]
+		if !ok {
+			seen = now
+		}
+
+		firstSeen[tx.Id] = seen
+
+		token := gethcommon.HexToAddress(tx.Erc20Token.Contract)
+		w := withdrawals[token]
+		w.count++
+
+		if w.firstSeen.IsZero() || seen.Before(w.firstSeen) {
+			w.firstSeen = seen
+		}
+
+		if tx.Erc20Fee != nil {
+			w.fees = append(w.fees, tx.Erc20Fee.Amount.BigInt())
+		}
+
+		withdrawals[token] = w
+	}
+
+	// withdrawals that left the pool are forgotten
+	l.firstSeen = firstSeen
+	l.saveProgress()
+
+	return withdrawals
+}
+
+// getUnbatchedTransfers returns the withdrawals in the pool, queried again once the cached ones are older than
+// unbatchedTransfersTTL. New withdrawals are seen that much later at most.
+func (l *batchCreator) getUnbatchedTransfers(ctx context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+	if !l.unbatchedAt.IsZero() && time.Since(l.unbatchedAt) < unbatchedTransfersTTL {
+		return l.unbatched, nil
+	}
+
+	var txs []*peggytypes.OutgoingTransferTx
+	fn := func() (err error) {
+		txs, err = l.injective.UnbatchedTransfers(ctx)
+		return
+	}
+
+	if err := l.retry(ctx, fn); err != nil {
+		return nil, err
+	}
+
+	l.unbatched, l.unbatchedAt = txs, time.Now()
+
+	return txs, nil
+}
+
+func (l *batchCreator) requestTokenBatch(ctx context.Context, fee *peggytypes.BatchFees, round *batchRound) {
 	tokenAddress := gethcommon.HexToAddress(fee.Token)
 	tokenDenom := l.getTokenDenom(tokenAddress)
+	policy := l.tokenPolicy(tokenAddress)
+
+	logger := l.Log().WithFields(log.Fields{"token_denom": tokenDenom, "token_addr": tokenAddress.String()})
+
+	if nonce, ok := round.pendingBatches[tokenAddress]; ok {
+		logger.WithField("reason", fmt.Sprintf("batch nonce %d of the token is pending", nonce)).Infoln("not requesting token batch")
+		return
+	}
+
+	if l.needsWithdrawals(policy) && round.withdrawals == nil {
+		logger.WithField("reason", "unbatched withdrawals are unknown").Infoln("not requesting token batch")
+		return
+	}
 
 	tokenDecimals, err := l.ethereum.TokenDecimals(ctx, tokenAddress)
 	if err != nil {
@@ -76,13 +257,79 @@ func (l *batchCreator) requestTokenBatch(ctx context.Context, fee *peggytypes.Ba
 		return
 	}
 
-	if !l.checkMinBatchFee(fee, tokenAddress, tokenDecimals) {
+	pending := batchpolicy.Pending{
+		Fee: decimal.NewFromBigInt(fee.TotalFees.BigInt(), -1*int32(tokenDecimals)),
+	}
+
+	var tokenPriceUSD *decimal.Decimal
+	if policy.UsesUSD() || l.checksGasCost(policy) {
+		if tokenPriceUSD = l.getTokenPriceUSD(tokenAddress); tokenPriceUSD != nil {
+			feeUSD := pending.Fee.Mul(*tokenPriceUSD)
+			pending.FeeUSD = &feeUSD
+		}
+	}
+
+	// only the fees of the withdrawals in the next batch pay for relaying it
+	var batchFeeUSD *decimal.Decimal
+	if w, ok := round.withdrawals[tokenAddress]; ok {
+		pending.Withdrawals = w.count
+		pending.OldestAge = time.Since(w.firstSeen)
+
+		if tokenPriceUSD != nil {
+			feeUSD := decimal.NewFromBigInt(batchFee(w.fees), -1*int32(tokenDecimals)).Mul(*tokenPriceUSD)
+			batchFeeUSD = &feeUSD
+		}
+	}
+
+	if policy.MaxForcedBatches > 0 {
+		pending.ForcedBatches = l.forcedBatches(tokenAddress, policy.Window())
+	}
+
+	logger.WithFields(log.Fields{
+		"total_fee":   pending.Fee.String(),
+		"withdrawals": pending.Withdrawals,
+		"oldest_age":  pending.OldestAge.Truncate(time.Second).String(),
+	}).Debugln("checking batch policy")
+
+	decision := policy.Decide(pending)
+	if !decision.Request {
+		logger.WithField("reason", decision.Reason).Infoln("not requesting token batch")
 		return
 	}
 
-	l.Log().WithFields(log.Fields{"token_denom": tokenDenom, "token_addr": tokenAddress.String()}).Infoln("requesting token batch on Injective")
+	// a batch forced by the withdrawal age is requested even if it costs more to relay than it pays
+	if l.checksGasCost(policy) && !decision.Forced {
+		ok, reason := l.coversGasCost(batchFeeUSD, pending.Withdrawals, round.gasCost)
+		if !ok {
+			logger.WithField("reason", reason).Infoln("not requesting token batch")
+			return
+		}
 
-	_ = l.injective.SendRequestBatch(ctx, tokenDenom)
+		decision.Reason = reason
+	}
+
+	if l.cfg.BatchRequestGrace > 0 {
+		turn, reason := l.isRequesterTurn(tokenAddress, round)
+		if !turn {
+			logger.WithField("reason", reason).Infoln("not requesting token batch")
+			return
+		}
+
+		decision.Reason = fmt.Sprintf("%s, %s", decision.Reason, reason)
+	}
+
+	logger.WithField("reason", decision.Reason).Infoln("requesting token batch on Injective")
+
+	if err := l.injective.SendRequestBatch(ctx, tokenDenom); err != nil {
+		return
+	}
+
+	// the batched withdrawals leave the pool
+	l.unbatchedAt = time.Time{}
+
+	if decision.Forced {
+		l.recordForcedBatch(tokenAddress)
+	}
 }
 
 func (l *batchCreator) getTokenDenom(tokenAddr gethcommon.Address) string {
@@ -93,32 +340,44 @@ func (l *batchCreator) getTokenDenom(tokenAddr gethcommon.Address) string {
 	return peggytypes.PeggyDenomString(tokenAddr)
 }
 
-func (l *batchCreator) checkMinBatchFee(fee *peggytypes.BatchFees, tokenAddress gethcommon.Address, tokenDecimals uint8) bool {
-	if l.cfg.MinBatchFeeUSD == 0 {
-		return true
+// tokenPolicy returns the batch policy of the token. Tokens without one only need the fees to be worth
+// MinBatchFeeUSD. ForceBatchAge and MaxForcedBatches apply unless the policy sets its own.
+func (l *batchCreator) tokenPolicy(tokenAddr gethcommon.Address) batchpolicy.Policy {
+	policy, ok := l.cfg.BatchPolicies.For(tokenAddr)
+	if !ok {
+		policy = batchpolicy.Policy{MinFeeUSD: decimal.NewFromFloat(l.cfg.MinBatchFeeUSD)}
 	}
 
-	tokenPriceUSDFloat, err := l.priceFeed.QueryUSDPrice(tokenAddress)
-	if err != nil {
-		l.Log().WithError(err).Warningln("failed to query price feed", "token_addr", tokenAddress.String())
-		return false
+	if policy.MaxWithdrawalAge == 0 {
+		policy.MaxWithdrawalAge = l.cfg.ForceBatchAge
 	}
 
-	var (
-		minFeeUSD     = decimal.NewFromFloat(l.cfg.MinBatchFeeUSD)
-		tokenPriceUSD = decimal.NewFromFloat(tokenPriceUSDFloat)
-		totalFeeUSD   = decimal.NewFromBigInt(fee.TotalFees.BigInt(), -1*int32(tokenDecimals)).Mul(tokenPriceUSD)
-	)
+	if policy.MaxForcedBatches == 0 {
+		policy.MaxForcedBatches = l.cfg.MaxForcedBatches
+	}
 
-	l.Log().WithFields(log.Fields{
-		"token_addr": fee.Token,
-		"total_fee":  totalFeeUSD.String() + "USD",
-		"min_fee":    minFeeUSD.String() + "USD",
-	}).Debugln("checking total batch fees")
+	return policy
+}
 
-	if totalFeeUSD.LessThan(minFeeUSD) {
-		return false
+// needsWithdrawals reports whether the withdrawals in the pool are needed to decide on a batch of the token
+func (l *batchCreator) needsWithdrawals(policy batchpolicy.Policy) bool {
+	return policy.UsesWithdrawals() || l.checksGasCost(policy)
+}
+
+// checksGasCost reports whether the batch fees of the token must cover the gas cost of relaying the batch
+func (l *batchCreator) checksGasCost(policy batchpolicy.Policy) bool {
+	return l.cfg.BatchGasMargin > 0 && !policy.Deny
+}
+
+// getTokenPriceUSD returns the USD price of the token, nil if it is unknown
+func (l *batchCreator) getTokenPriceUSD(tokenAddress gethcommon.Address) *decimal.Decimal {
+	tokenPriceUSDFloat, err := l.priceFeed.QueryUSDPrice(tokenAddress)
+	if err != nil {
+		l.Log().WithError(err).Warningln("failed to query price feed", "token_addr", tokenAddress.String())
+		return nil
 	}
 
-	return true
+	tokenPriceUSD := decimal.NewFromFloat(tokenPriceUSDFloat)
+
+	return &tokenPriceUSD
 }
@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+)
+
+// loadProgress restores the age of the withdrawals and the forced batches saved before a restart, once
+func (l *batchCreator) loadProgress() {
+	if l.progressLoaded {
+		return
+	}
+
+	l.progressLoaded = true
+
+	if l.store == nil {
+		return
+	}
+
+	p, err := l.store.BatchCreatorProgress()
+	if err != nil {
+		if !errors.Is(err, state.ErrNotFound) {
+			l.Log().WithError(err).Warningln("failed to load batch creator progress")
+		}
+
+		return
+	}
+
+	if l.firstSeen == nil {
+		l.firstSeen = p.FirstSeen
+	}
+
+	if l.forced == nil {
+		l.forced = make(map[gethcommon.Address][]time.Time, len(p.ForcedBatches))
+		for token, times := range p.ForcedBatches {
+			l.forced[gethcommon.HexToAddress(token)] = times
+		}
+	}
+
+	l.Log().WithField("withdrawals", len(p.FirstSeen)).Debugln("loaded batch creator progress")
+}
+
+func (l *batchCreator) saveProgress() {
+	if l.store == nil {
+		return
+	}
+
+	p := &state.BatchCreatorProgress{
+		FirstSeen:     l.firstSeen,
+		ForcedBatches: make(map[string][]time.Time, len(l.forced)),
+		UpdatedAt:     time.Now(),
+	}
+
+	for token, times := range l.forced {
+		p.ForcedBatches[token.Hex()] = times
+	}
+
+	if err := l.store.SetBatchCreatorProgress(p); err != nil {
+		l.Log().WithError(err).Warningln("failed to persist batch creator progress")
+	}
+}
+
+// forcedBatches returns the number of batches of the token forced within the window, older ones are forgotten
+func (l *batchCreator) forcedBatches(token gethcommon.Address, window time.Duration) int {
+	since := time.Now().Add(-window)
+
+	var recent []time.Time
+	for _, t := range l.forced[token] {
+		if t.After(since) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) == 0 {
+		delete(l.forced, token)
+	} else {
+		l.forced[token] = recent
+	}
+
+	return len(recent)
+}
+
+func (l *batchCreator) recordForcedBatch(token gethcommon.Address) {
+	if l.forced == nil {
+		l.forced = make(map[gethcommon.Address][]time.Time)
+	}
+
+	l.forced[token] = append(l.forced[token], time.Now())
+	l.saveProgress()
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("batch_creator.forced_batches", tagSpec, 1)
+	}, metrics.Tags{"svc": l.svcTags["svc"], "token_contract": token.Hex()})
+}
@@ -0,0 +1,139 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// Default gas used by Peggy.submitBatch. Every member of the valset is sent along with its signature and the
+// signatures are checked until the power threshold is reached, then each withdrawal is an ERC20 transfer. The
+// estimate is on the high side, cold storage writes included.
+const (
+	submitBatchBaseGas         = 120_000
+	submitBatchGasPerValidator = 7_000
+	submitBatchGasPerTx        = 35_000
+
+	// maxBatchSize is the maximum number of withdrawals the Peggy module puts in a batch
+	maxBatchSize = 100
+)
+
+// wethAddress is the WETH contract on Ethereum mainnet, the price feed values ETH with it
+var wethAddress = gethcommon.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+
+// BatchGas is the gas used by Peggy.submitBatch, zero fields use the defaults
+type BatchGas struct {
+	Base         uint64
+	PerValidator uint64
+	PerTx        uint64
+}
+
+// estimate returns the gas needed to relay a batch of txs withdrawals with the given valset size
+func (g BatchGas) estimate(txs, validators int) uint64 {
+	if g.Base == 0 {
+		g.Base = submitBatchBaseGas
+	}
+
+	if g.PerValidator == 0 {
+		g.PerValidator = submitBatchGasPerValidator
+	}
+
+	if g.PerTx == 0 {
+		g.PerTx = submitBatchGasPerTx
+	}
+
+	if txs > maxBatchSize {
+		txs = maxBatchSize
+	}
+
+	return g.Base + uint64(validators)*g.PerValidator + uint64(txs)*g.PerTx
+}
+
+// batchGasCost values the gas of submitBatch in USD, for the current valset
+type batchGasCost struct {
+	gas         BatchGas
+	validators  int
+	gasPriceUSD decimal.Decimal
+}
+
+// costUSD returns the USD cost of relaying a batch of txs withdrawals
+func (c *batchGasCost) costUSD(txs int) decimal.Decimal {
+	gas := c.gas.estimate(txs, c.validators)
+	return decimal.NewFromBigInt(new(big.Int).SetUint64(gas), 0).Mul(c.gasPriceUSD)
+}
+
+// batchFee returns the fees of the withdrawals the Peggy module puts in the next batch, the maxBatchSize ones with
+// the highest fees
+func batchFee(fees []*big.Int) *big.Int {
+	sort.Slice(fees, func(i, j int) bool { return fees[i].Cmp(fees[j]) > 0 })
+
+	if len(fees) > maxBatchSize {
+		fees = fees[:maxBatchSize]
+	}
+
+	total := new(big.Int)
+	for _, fee := range fees {
+		total.Add(total, fee)
+	}
+
+	return total
+}
+
+// getBatchGasCost returns the USD price of the gas for the current valset, using the current Ethereum gas price
+// and the ETH price of the price feed
+func (l *batchCreator) getBatchGasCost(ctx context.Context, valset *peggytypes.Valset) (*batchGasCost, error) {
+	if valset == nil {
+		return nil, errors.New("current valset is unknown")
+	}
+
+	var gasPrice *big.Int
+	getGasPriceFn := func() (err error) {
+		gasPrice, err = l.ethereum.SuggestGasPrice(ctx)
+		return
+	}
+
+	if err := l.retry(ctx, getGasPriceFn); err != nil {
+		return nil, errors.Wrap(err, "failed to get Ethereum gas price")
+	}
+
+	ethPriceUSD, err := l.priceFeed.QueryUSDPrice(wethAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query ETH price")
+	}
+
+	return &batchGasCost{
+		gas:         l.cfg.BatchGas,
+		validators:  len(valset.Members),
+		gasPriceUSD: decimal.NewFromBigInt(gasPrice, -18).Mul(decimal.NewFromFloat(ethPriceUSD)),
+	}, nil
+}
+
+// coversGasCost reports whether the fees of the next batch exceed the gas cost of relaying it times BatchGasMargin,
+// and why
+func (l *batchCreator) coversGasCost(batchFeeUSD *decimal.Decimal, withdrawals int, gasCost *batchGasCost) (bool, string) {
+	if gasCost == nil {
+		return false, "batch gas cost is unknown"
+	}
+
+	if batchFeeUSD == nil {
+		return false, "USD value of the fees is unknown"
+	}
+
+	var (
+		margin  = decimal.NewFromFloat(l.cfg.BatchGasMargin)
+		costUSD = gasCost.costUSD(withdrawals)
+	)
+
+	if !batchFeeUSD.GreaterThan(costUSD.Mul(margin)) {
+		return false, fmt.Sprintf("batch fee %s USD does not exceed the gas cost of %s USD times %s", batchFeeUSD.StringFixed(2), costUSD.StringFixed(2), margin)
+	}
+
+	return true, fmt.Sprintf("batch fee %s USD exceeds the gas cost of %s USD times %s", batchFeeUSD.StringFixed(2), costUSD.StringFixed(2), margin)
+}
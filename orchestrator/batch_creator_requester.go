@@ -0,0 +1,124 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// batchRequestSlot is how long a valset member stays the elected requester of a token
+const batchRequestSlot = 10 * time.Minute
+
+// getPendingBatches returns the latest batch nonce of every token with a batch waiting to be relayed, nil if
+// unknown
+func (l *batchCreator) getPendingBatches(ctx context.Context) map[gethcommon.Address]uint64 {
+	var batches []*peggytypes.OutgoingTxBatch
+	fn := func() (err error) {
+		batches, err = l.injective.LatestTransactionBatches(ctx)
+		return
+	}
+
+	if err := l.retry(ctx, fn); err != nil {
+		l.Log().WithError(err).Warningln("failed to get latest batches")
+		return nil
+	}
+
+	pending := make(map[gethcommon.Address]uint64, len(batches))
+	for _, batch := range batches {
+		token := gethcommon.HexToAddress(batch.TokenContract)
+		if batch.BatchNonce > pending[token] {
+			pending[token] = batch.BatchNonce
+		}
+	}
+
+	return pending
+}
+
+func (l *batchCreator) getCurrentValset(ctx context.Context) *peggytypes.Valset {
+	var valset *peggytypes.Valset
+	fn := func() (err error) {
+		valset, err = l.injective.CurrentValset(ctx)
+		return
+	}
+
+	if err := l.retry(ctx, fn); err != nil {
+		l.Log().WithError(err).Warningln("failed to get current valset")
+		return nil
+	}
+
+	return valset
+}
+
+// electedRequester returns the valset member elected to request batches of the token during the slot of t. Every
+// instance computes the same member from the token address and the slot number, with a chance proportional to the
+// member's power.
+func electedRequester(valset *peggytypes.Valset, token gethcommon.Address, t time.Time) (gethcommon.Address, bool) {
+	totalPower := new(big.Int)
+	for _, m := range valset.Members {
+		totalPower.Add(totalPower, new(big.Int).SetUint64(m.Power))
+	}
+
+	if totalPower.Sign() == 0 {
+		return gethcommon.Address{}, false
+	}
+
+	var slot [8]byte
+	binary.BigEndian.PutUint64(slot[:], uint64(t.Unix()/int64(batchRequestSlot.Seconds())))
+
+	pick := new(big.Int).SetBytes(crypto.Keccak256(token.Bytes(), slot[:]))
+	pick.Mod(pick, totalPower)
+
+	cumulative := new(big.Int)
+	for _, m := range valset.Members {
+		cumulative.Add(cumulative, new(big.Int).SetUint64(m.Power))
+		if pick.Cmp(cumulative) < 0 {
+			return gethcommon.HexToAddress(m.EthereumAddress), true
+		}
+	}
+
+	return gethcommon.Address{}, false
+}
+
+// isRequesterTurn reports whether this instance should request the batch of the token, and why. The elected
+// requester requests right away, the others once the token has been worth a batch for BatchRequestGrace.
+func (l *batchCreator) isRequesterTurn(token gethcommon.Address, round *batchRound) (bool, string) {
+	if round.valset == nil {
+		return true, "not coordinated with other requesters, valset is unknown"
+	}
+
+	now := time.Now()
+
+	elected, ok := electedRequester(round.valset, token, now)
+	if !ok {
+		return true, "not coordinated with other requesters, valset has no power"
+	}
+
+	if elected == l.cfg.EthereumAddr {
+		return true, "elected requester of the token"
+	}
+
+	round.wanted[token] = true
+
+	if l.wantedSince == nil {
+		l.wantedSince = make(map[gethcommon.Address]time.Time)
+	}
+
+	since, ok := l.wantedSince[token]
+	if !ok {
+		since = now
+		l.wantedSince[token] = since
+	}
+
+	if waited := now.Sub(since); waited < l.cfg.BatchRequestGrace {
+		return false, fmt.Sprintf("waiting %s for elected requester %s", (l.cfg.BatchRequestGrace - waited).Truncate(time.Second), elected.Hex())
+	}
+
+	return true, fmt.Sprintf("no batch from elected requester %s within %s", elected.Hex(), l.cfg.BatchRequestGrace)
+}
@@ -0,0 +1,266 @@
+package batchpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Policy decides when the batch creator requests a batch of a token. Fees are in token units or USD, the latter
+// using the price feed. A zero value means no threshold.
+type Policy struct {
+	// Deny disables batch requests of the token
+	Deny bool
+
+	MinFeeUSD      decimal.Decimal
+	MinFee         decimal.Decimal
+	MinWithdrawals int
+
+	// MaxWithdrawalAge forces a batch once the oldest withdrawal has waited that long, whatever the fees
+	MaxWithdrawalAge time.Duration
+
+	// MaxForcedBatches caps the batches forced within ForcedBatchesWindow, so that small withdrawals cannot be
+	// used to get batches requested over and over
+	MaxForcedBatches    int
+	ForcedBatchesWindow time.Duration
+}
+
+// DefaultForcedBatchesWindow is the window of MaxForcedBatches when the policy does not set one
+const DefaultForcedBatchesWindow = 24 * time.Hour
+
+// Policies are the batch policies of every token. Tokens without a policy of their own use Default, if set.
+type Policies struct {
+	Default *Policy
+	Tokens  map[gethcommon.Address]Policy
+}
+
+// Pending describes the withdrawals of a token waiting to be batched, along with the batches of the token forced
+// within the window of the policy. FeeUSD is nil if the token price is unknown.
+type Pending struct {
+	Fee           decimal.Decimal
+	FeeUSD        *decimal.Decimal
+	Withdrawals   int
+	OldestAge     time.Duration
+	ForcedBatches int
+}
+
+// Decision is the outcome of a policy for the pending withdrawals of a token. Forced is set when the batch is
+// requested because of the withdrawal age, whatever the fees.
+type Decision struct {
+	Request bool
+	Forced  bool
+	Reason  string
+}
+
+type policiesFile struct {
+	Default *policyEntry           `toml:"default"`
+	Tokens  map[string]policyEntry `toml:"tokens"`
+}
+
+type policyEntry struct {
+	Deny             bool   `toml:"deny"`
+	MinFeeUSD        string `toml:"min_fee_usd"`
+	MinFee           string `toml:"min_fee"`
+	MinWithdrawals   int    `toml:"min_withdrawals"`
+	MaxWithdrawalAge string `toml:"max_withdrawal_age"`
+
+	MaxForcedBatches    int    `toml:"max_forced_batches"`
+	ForcedBatchesWindow string `toml:"forced_batches_window"`
+}
+
+// LoadPolicies reads the batch policies from a TOML file:
+//
+//	[default]
+//	min_fee_usd = "25"
+//
+//	[tokens.0xdAC17F958D2ee523a2206206994597C13D831ec7]
+//	min_fee_usd = "10"
+//	min_fee = "10"
+//	min_withdrawals = 5
+//	max_withdrawal_age = "6h"
+//	max_forced_batches = 2
+//	forced_batches_window = "24h"
+//
+//	[tokens.0x7Fc66500c84A76Ad7e9c93437bFc5Ac33E2DDaE9]
+//	deny = true
+func LoadPolicies(file string) (*Policies, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read batch policies")
+	}
+
+	var f policiesFile
+	dec := toml.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&f); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode batch policies in %s", file)
+	}
+
+	policies := &Policies{Tokens: make(map[gethcommon.Address]Policy, len(f.Tokens))}
+
+	if f.Default != nil {
+		policy, err := f.Default.parse()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid default batch policy")
+		}
+
+		policies.Default = &policy
+	}
+
+	for token, entry := range f.Tokens {
+		if !gethcommon.IsHexAddress(token) {
+			return nil, errors.Errorf("invalid token contract %q in batch policies", token)
+		}
+
+		policy, err := entry.parse()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid batch policy of token %s", token)
+		}
+
+		policies.Tokens[gethcommon.HexToAddress(token)] = policy
+	}
+
+	return policies, nil
+}
+
+func (e policyEntry) parse() (Policy, error) {
+	var (
+		policy = Policy{Deny: e.Deny, MinWithdrawals: e.MinWithdrawals, MaxForcedBatches: e.MaxForcedBatches}
+		err    error
+	)
+
+	if e.MinWithdrawals < 0 {
+		return Policy{}, errors.New("min_withdrawals cannot be negative")
+	}
+
+	if e.MaxForcedBatches < 0 {
+		return Policy{}, errors.New("max_forced_batches cannot be negative")
+	}
+
+	for _, v := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"max_withdrawal_age", e.MaxWithdrawalAge, &policy.MaxWithdrawalAge},
+		{"forced_batches_window", e.ForcedBatchesWindow, &policy.ForcedBatchesWindow},
+	} {
+		if v.value == "" {
+			continue
+		}
+
+		if *v.dst, err = time.ParseDuration(v.value); err != nil {
+			return Policy{}, errors.Wrapf(err, "invalid %s", v.name)
+		}
+
+		if *v.dst < 0 {
+			return Policy{}, errors.Errorf("%s cannot be negative", v.name)
+		}
+	}
+
+	for _, v := range []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{"min_fee_usd", e.MinFeeUSD, &policy.MinFeeUSD},
+		{"min_fee", e.MinFee, &policy.MinFee},
+	} {
+		if strings.TrimSpace(v.value) == "" {
+			continue
+		}
+
+		if *v.dst, err = decimal.NewFromString(v.value); err != nil {
+			return Policy{}, errors.Wrapf(err, "invalid %s", v.name)
+		}
+
+		if v.dst.IsNegative() {
+			return Policy{}, errors.Errorf("%s cannot be negative", v.name)
+		}
+	}
+
+	return policy, nil
+}
+
+// For returns the policy of the token, if any.
+func (p *Policies) For(token gethcommon.Address) (Policy, bool) {
+	if p == nil {
+		return Policy{}, false
+	}
+
+	if policy, ok := p.Tokens[token]; ok {
+		return policy, true
+	}
+
+	if p.Default != nil {
+		return *p.Default, true
+	}
+
+	return Policy{}, false
+}
+
+// UsesUSD reports whether the policy needs the token price.
+func (p Policy) UsesUSD() bool {
+	return !p.Deny && !p.MinFeeUSD.IsZero()
+}
+
+// UsesWithdrawals reports whether the policy needs the withdrawals waiting in the pool.
+func (p Policy) UsesWithdrawals() bool {
+	return !p.Deny && (p.MinWithdrawals > 0 || p.MaxWithdrawalAge > 0)
+}
+
+// Window returns the window of MaxForcedBatches.
+func (p Policy) Window() time.Duration {
+	if p.ForcedBatchesWindow > 0 {
+		return p.ForcedBatchesWindow
+	}
+
+	return DefaultForcedBatchesWindow
+}
+
+// Decide returns whether a batch of the pending withdrawals should be requested, and why. A withdrawal waiting for
+// longer than MaxWithdrawalAge forces the batch, unless MaxForcedBatches were already forced, otherwise every
+// threshold must be met. A USD threshold cannot be checked without the token price, so no batch is requested then.
+func (p Policy) Decide(pending Pending) Decision {
+	if p.Deny {
+		return Decision{Reason: "token is denied by batch policy"}
+	}
+
+	if p.MaxWithdrawalAge > 0 && pending.Withdrawals > 0 && pending.OldestAge >= p.MaxWithdrawalAge {
+		if p.MaxForcedBatches == 0 || pending.ForcedBatches < p.MaxForcedBatches {
+			return Decision{Request: true, Forced: true, Reason: fmt.Sprintf("oldest withdrawal has waited %s, batch is forced after %s", pending.OldestAge.Truncate(time.Second), p.MaxWithdrawalAge)}
+		}
+
+		decision := p.checkThresholds(pending)
+		if !decision.Request {
+			decision.Reason = fmt.Sprintf("%s, and %d batches were already forced in the last %s", decision.Reason, pending.ForcedBatches, p.Window())
+		}
+
+		return decision
+	}
+
+	return p.checkThresholds(pending)
+}
+
+func (p Policy) checkThresholds(pending Pending) Decision {
+	switch {
+	case p.MinWithdrawals > 0 && pending.Withdrawals < p.MinWithdrawals:
+		return Decision{Reason: fmt.Sprintf("%d withdrawals are below the minimum of %d", pending.Withdrawals, p.MinWithdrawals)}
+	case !p.MinFee.IsZero() && pending.Fee.LessThan(p.MinFee):
+		return Decision{Reason: fmt.Sprintf("total fee %s is below the minimum of %s", pending.Fee, p.MinFee)}
+	case !p.MinFeeUSD.IsZero() && pending.FeeUSD == nil:
+		return Decision{Reason: "USD value of the fees is unknown"}
+	case !p.MinFeeUSD.IsZero() && pending.FeeUSD.LessThan(p.MinFeeUSD):
+		return Decision{Reason: fmt.Sprintf("total fee %s USD is below the minimum of %s USD", pending.FeeUSD.StringFixed(2), p.MinFeeUSD)}
+	}
+
+	return Decision{Request: true, Reason: "batch policy thresholds are met"}
+}
@@ -0,0 +1,180 @@
+package batchpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	usdt = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	aave = "0x7Fc66500c84A76Ad7e9c93437bFc5Ac33E2DDaE9"
+	weth = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func TestLoadPolicies(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.toml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+[default]
+min_fee_usd = "25"
+
+[tokens.`+usdt+`]
+min_fee_usd = "10.5"
+min_fee = "10"
+min_withdrawals = 5
+max_withdrawal_age = "6h"
+max_forced_batches = 2
+forced_batches_window = "12h"
+
+[tokens.`+aave+`]
+deny = true
+`), 0o600))
+
+	policies, err := LoadPolicies(file)
+	require.NoError(t, err)
+
+	policy, ok := policies.For(gethcommon.HexToAddress(usdt))
+	require.True(t, ok)
+	assert.True(t, policy.MinFeeUSD.Equal(dec("10.5")))
+	assert.True(t, policy.MinFee.Equal(dec("10")))
+	assert.Equal(t, 5, policy.MinWithdrawals)
+	assert.Equal(t, 6*time.Hour, policy.MaxWithdrawalAge)
+	assert.Equal(t, 2, policy.MaxForcedBatches)
+	assert.Equal(t, 12*time.Hour, policy.Window())
+	assert.True(t, policy.UsesUSD())
+	assert.True(t, policy.UsesWithdrawals())
+
+	policy, ok = policies.For(gethcommon.HexToAddress(aave))
+	require.True(t, ok)
+	assert.True(t, policy.Deny)
+	assert.False(t, policy.UsesUSD())
+
+	policy, ok = policies.For(gethcommon.HexToAddress(weth))
+	require.True(t, ok)
+	assert.True(t, policy.MinFeeUSD.Equal(dec("25")))
+	assert.False(t, policy.UsesWithdrawals())
+	assert.Equal(t, DefaultForcedBatchesWindow, policy.Window())
+
+	_, ok = (&Policies{}).For(gethcommon.HexToAddress(weth))
+	assert.False(t, ok)
+
+	for name, content := range map[string]string{
+		"invalid token":       "[tokens.usdt]\nmin_fee = \"1\"",
+		"negative fee":        "[tokens." + usdt + "]\nmin_fee = \"-1\"",
+		"invalid fee":         "[default]\nmin_fee_usd = \"lots\"",
+		"numeric fee":         "[default]\nmin_fee_usd = 25",
+		"negative count":      "[default]\nmin_withdrawals = -1",
+		"invalid age":         "[default]\nmax_withdrawal_age = \"1 day\"",
+		"negative cap":        "[default]\nmax_forced_batches = -1",
+		"negative window":     "[default]\nforced_batches_window = \"-1h\"",
+		"unknown field":       "[default]\nmin_fees_usd = \"1\"",
+		"not a policies file": "default = 1",
+	} {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+		_, err := LoadPolicies(file)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPolicyDecide(t *testing.T) {
+	policy := Policy{
+		MinFeeUSD:        dec("10"),
+		MinFee:           dec("5"),
+		MinWithdrawals:   3,
+		MaxWithdrawalAge: time.Hour,
+		MaxForcedBatches: 2,
+	}
+
+	testTable := []struct {
+		name     string
+		policy   Policy
+		pending  Pending
+		expected bool
+		forced   bool
+	}{
+		{
+			name:     "thresholds met",
+			policy:   policy,
+			pending:  Pending{Fee: dec("5"), FeeUSD: decPtr("10"), Withdrawals: 3},
+			expected: true,
+		},
+		{
+			name:     "not enough withdrawals",
+			policy:   policy,
+			pending:  Pending{Fee: dec("50"), FeeUSD: decPtr("100"), Withdrawals: 2},
+			expected: false,
+		},
+		{
+			name:     "fee below minimum",
+			policy:   policy,
+			pending:  Pending{Fee: dec("4.9"), FeeUSD: decPtr("100"), Withdrawals: 3},
+			expected: false,
+		},
+		{
+			name:     "fee value below minimum",
+			policy:   policy,
+			pending:  Pending{Fee: dec("50"), FeeUSD: decPtr("9.99"), Withdrawals: 3},
+			expected: false,
+		},
+		{
+			name:     "unknown fee value",
+			policy:   policy,
+			pending:  Pending{Fee: dec("50"), Withdrawals: 3},
+			expected: false,
+		},
+		{
+			name:     "old withdrawal forces batch",
+			policy:   policy,
+			pending:  Pending{Fee: dec("0.1"), Withdrawals: 1, OldestAge: 2 * time.Hour},
+			expected: true,
+			forced:   true,
+		},
+		{
+			name:     "forced batches capped",
+			policy:   policy,
+			pending:  Pending{Fee: dec("0.1"), Withdrawals: 1, OldestAge: 2 * time.Hour, ForcedBatches: 2},
+			expected: false,
+		},
+		{
+			name:     "forced batches capped, thresholds met",
+			policy:   policy,
+			pending:  Pending{Fee: dec("50"), FeeUSD: decPtr("100"), Withdrawals: 3, OldestAge: 2 * time.Hour, ForcedBatches: 2},
+			expected: true,
+		},
+		{
+			name:     "denied",
+			policy:   Policy{Deny: true, MaxWithdrawalAge: time.Hour},
+			pending:  Pending{Fee: dec("50"), FeeUSD: decPtr("100"), Withdrawals: 3, OldestAge: 2 * time.Hour},
+			expected: false,
+		},
+		{
+			name:     "no thresholds",
+			policy:   Policy{},
+			pending:  Pending{Fee: dec("0")},
+			expected: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		decision := tt.policy.Decide(tt.pending)
+		assert.Equal(t, tt.expected, decision.Request, tt.name)
+		assert.Equal(t, tt.forced, decision.Forced, tt.name)
+		assert.NotEmpty(t, decision.Reason, tt.name)
+	}
+}
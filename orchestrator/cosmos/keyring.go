@@ -31,7 +31,9 @@ type KeyringConfig struct {
 	KeyringBackend,
 	KeyFrom,
 	KeyPassphrase,
-	PrivateKey string
+	PrivateKey,
+	// SignerURI points to a key held by a signing backend (vault:// or pkcs11:)
+	SignerURI string
 	UseLedger bool
 }
 
@@ -46,6 +48,10 @@ type Keyring struct {
 }
 
 func NewKeyring(cfg KeyringConfig) (Keyring, error) {
+	if len(cfg.SignerURI) > 0 {
+		return newRemoteKeyring(cfg)
+	}
+
 	if cfg.withPrivateKey() {
 		return newInMemoryKeyring(cfg)
 	}
@@ -0,0 +1,125 @@
+package cosmos
+
+import (
+	"bytes"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/remotekey"
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/hd"
+)
+
+// TxSigner signs Injective transactions with a key held out of the process.
+type TxSigner interface {
+	PubKey() cryptotypes.PubKey
+	// SignTx signs the SIGN_MODE_DIRECT sign doc bytes
+	SignTx(signDoc []byte) ([]byte, error)
+}
+
+// remoteKeyring signs with a key held by a signing backend or a signer daemon. The public key is kept in an
+// in-memory keyring, so that the Cosmos client can look it up like any other key.
+type remoteKeyring struct {
+	keyring.Keyring
+
+	signer  TxSigner
+	keyName string
+}
+
+func newRemoteKeyring(cfg KeyringConfig) (Keyring, error) {
+	if cfg.UseLedger || cfg.withPrivateKey() {
+		return Keyring{}, errors.New("cannot use a signer URI along with a private key or Ledger")
+	}
+
+	key, err := remotekey.Open(cfg.SignerURI)
+	if err != nil {
+		return Keyring{}, errors.Wrap(err, "failed to open Cosmos signer")
+	}
+
+	signer := digestTxSigner{
+		key:    key,
+		pubKey: &ethsecp256k1.PubKey{Key: ethcrypto.CompressPubkey(key.PublicKey())},
+	}
+
+	return NewTxSignerKeyring(signer, cfg.KeyFrom)
+}
+
+// NewTxSignerKeyring returns a keyring signing with the tx signer. If set, keyFrom is either the key name or the
+// expected account address.
+func NewTxSignerKeyring(signer TxSigner, keyFrom string) (Keyring, error) {
+	var (
+		pubKey     = signer.PubKey()
+		cosmosAddr = cosmostypes.AccAddress(pubKey.Address())
+		keyName    = DefaultKeyName
+	)
+
+	if len(keyFrom) > 0 {
+		from, err := cosmostypes.AccAddressFromBech32(keyFrom)
+		if err != nil {
+			keyName = keyFrom // use it as key name
+		} else if !bytes.Equal(from.Bytes(), cosmosAddr.Bytes()) {
+			return Keyring{}, errors.Errorf("expected account address %s but got %s from the signer", from.String(), cosmosAddr.String())
+		}
+	}
+
+	kr := keyring.NewInMemory(Codec(), hd.EthSecp256k1Option())
+	if _, err := kr.SaveOfflineKey(keyName, pubKey); err != nil {
+		return Keyring{}, errors.Wrap(err, "failed to save signer public key")
+	}
+
+	k := Keyring{
+		Keyring: &remoteKeyring{
+			Keyring: kr,
+			signer:  signer,
+			keyName: keyName,
+		},
+		Addr: cosmosAddr,
+	}
+
+	return k, nil
+}
+
+func (k *remoteKeyring) Sign(uid string, msg []byte, _ signing.SignMode) ([]byte, cryptotypes.PubKey, error) {
+	if uid != k.keyName {
+		return nil, nil, errors.Errorf("key %s not found", uid)
+	}
+
+	return k.sign(msg)
+}
+
+func (k *remoteKeyring) SignByAddress(address cosmostypes.Address, msg []byte, _ signing.SignMode) ([]byte, cryptotypes.PubKey, error) {
+	if !bytes.Equal(address.Bytes(), k.signer.PubKey().Address()) {
+		return nil, nil, errors.Errorf("key with address %s not found", address.String())
+	}
+
+	return k.sign(msg)
+}
+
+func (k *remoteKeyring) sign(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	sig, err := k.signer.SignTx(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sig, k.signer.PubKey(), nil
+}
+
+// digestTxSigner signs with a key of a signing backend
+type digestTxSigner struct {
+	key    remotekey.Key
+	pubKey cryptotypes.PubKey
+}
+
+func (s digestTxSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+// SignTx signs the message the way ethsecp256k1 keys do: the Keccak256 hash of the message, in [R || S || V] format
+func (s digestTxSigner) SignTx(signDoc []byte) ([]byte, error) {
+	return s.key.SignDigest(ethcrypto.Keccak256(signDoc))
+}
@@ -0,0 +1,88 @@
+package cosmos
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+)
+
+// localKey stands for a key held by a signing backend
+type localKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k localKey) PublicKey() *ecdsa.PublicKey {
+	return &k.key.PublicKey
+}
+
+func (k localKey) SignDigest(digest []byte) ([]byte, error) {
+	return ethcrypto.Sign(digest, k.key)
+}
+
+func TestRemoteKeyring(t *testing.T) {
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+
+	privKey := &ethsecp256k1.PrivKey{Key: ethcrypto.FromECDSA(key)}
+	addr := cosmostypes.AccAddress(privKey.PubKey().Address())
+
+	k, err := NewTxSignerKeyring(digestTxSigner{
+		key:    localKey{key: key},
+		pubKey: &ethsecp256k1.PubKey{Key: ethcrypto.CompressPubkey(&key.PublicKey)},
+	}, addr.String())
+	require.NoError(t, err)
+	assert.Equal(t, addr, k.Addr)
+
+	kr := k.Keyring
+
+	msg := []byte("sign doc bytes")
+
+	sig, pubKey, err := kr.Sign(DefaultKeyName, msg, signing.SignMode_SIGN_MODE_DIRECT)
+	require.NoError(t, err)
+	assert.True(t, pubKey.Equals(privKey.PubKey()))
+	assert.True(t, pubKey.VerifySignature(msg, sig))
+
+	// signatures match the ones of a local ethsecp256k1 key
+	expected, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	assert.Equal(t, expected, sig)
+
+	_, _, err = kr.SignByAddress(addr, msg, signing.SignMode_SIGN_MODE_DIRECT)
+	assert.NoError(t, err)
+
+	_, _, err = kr.Sign("other", msg, signing.SignMode_SIGN_MODE_DIRECT)
+	assert.Error(t, err)
+
+	_, _, err = kr.SignByAddress(cosmostypes.AccAddress(make([]byte, 20)), msg, signing.SignMode_SIGN_MODE_DIRECT)
+	assert.Error(t, err)
+}
+
+func TestTxSignerKeyringAddress(t *testing.T) {
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer := digestTxSigner{
+		key:    localKey{key: key},
+		pubKey: &ethsecp256k1.PubKey{Key: ethcrypto.CompressPubkey(&key.PublicKey)},
+	}
+
+	_, err = NewTxSignerKeyring(signer, cosmostypes.AccAddress(make([]byte, 20)).String())
+	assert.ErrorContains(t, err, "expected account address")
+
+	k, err := NewTxSignerKeyring(signer, "orchestrator")
+	require.NoError(t, err)
+
+	record, err := k.Key("orchestrator")
+	require.NoError(t, err)
+
+	pubKey, err := record.GetPubKey()
+	require.NoError(t, err)
+	assert.True(t, pubKey.Equals(signer.pubKey))
+}
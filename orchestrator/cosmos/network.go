@@ -8,14 +8,16 @@ import (
 	comethttp "github.com/cometbft/cometbft/rpc/client/http"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	log "github.com/xlab/suplog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
 	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/staking"
 	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/tendermint"
-	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
 	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
 	"github.com/InjectiveLabs/sdk-go/client/chain"
 	clientcommon "github.com/InjectiveLabs/sdk-go/client/common"
@@ -27,15 +29,26 @@ type NetworkConfig struct {
 	CosmosGRPC,
 	TendermintRPC,
 	GasPrice string
+
+	// MaxClaimsTxSize limits the total size (in bytes) of claims bundled into one tx. Zero means the default limit.
+	MaxClaimsTxSize int
+
+	// MaxConfirmsTxSize limits the total size (in bytes) of batch confirmations bundled into one tx. Zero means the
+	// default limit.
+	MaxConfirmsTxSize int
+
+	// AuditLog records the valset and batch confirmations signed with the Ethereum key. Nil disables it.
+	AuditLog *audit.Log
 }
 
 type Network interface {
 	peggy.QueryClient
 	peggy.BroadcastClient
+	staking.ValidatorsClient
 	tendermint.Client
 }
 
-func NewNetwork(k keyring.Keyring, ethSignFn keystore.PersonalSignFn, cfg NetworkConfig) (Network, error) {
+func NewNetwork(k keyring.Keyring, confirmSigner peggy.ConfirmSigner, cfg NetworkConfig) (Network, error) {
 	clientCfg := cfg.loadClientConfig()
 
 	clientCtx, err := chain.NewClientContext(clientCfg.ChainId, cfg.ValidatorAddress, k)
@@ -64,10 +77,12 @@ func NewNetwork(k keyring.Keyring, ethSignFn keystore.PersonalSignFn, cfg Networ
 	net := struct {
 		peggy.QueryClient
 		peggy.BroadcastClient
+		staking.ValidatorsClient
 		tendermint.Client
 	}{
 		peggy.NewQueryClient(peggytypes.NewQueryClient(conn)),
-		peggy.NewBroadcastClient(chainClient, ethSignFn),
+		peggy.NewBroadcastClient(chainClient, confirmSigner, cfg.MaxClaimsTxSize, cfg.MaxConfirmsTxSize, cfg.AuditLog),
+		staking.NewValidatorsClient(stakingtypes.NewQueryClient(conn), clientCtx.InterfaceRegistry),
 		tendermint.NewRPCClient(clientCfg.TmEndpoint),
 	}
 
@@ -5,7 +5,6 @@ import (
 	"sync"
 	"time"
 
-	sdkmath "cosmossdk.io/math"
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
 	cosmostx "github.com/cosmos/cosmos-sdk/types/tx"
 	gethcommon "github.com/ethereum/go-ethereum/common"
@@ -13,6 +12,7 @@ import (
 	log "github.com/xlab/suplog"
 
 	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
 	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
@@ -24,6 +24,7 @@ type BroadcastClient interface {
 	UpdatePeggyOrchestratorAddresses(ctx context.Context, ethFrom gethcommon.Address, orchAddr cosmostypes.AccAddress) error
 	SendValsetConfirm(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) error
 	SendBatchConfirm(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) error
+	SendBatchConfirms(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error)
 	SendRequestBatch(ctx context.Context, denom string) error
 	SendToEth(ctx context.Context, destination gethcommon.Address, amount, fee cosmostypes.Coin) error
 	SendOldDepositClaim(ctx context.Context, deposit *peggyevents.PeggySendToCosmosEvent) error
@@ -31,6 +32,31 @@ type BroadcastClient interface {
 	SendWithdrawalClaim(ctx context.Context, withdrawal *peggyevents.PeggyTransactionBatchExecutedEvent) error
 	SendValsetClaim(ctx context.Context, vs *peggyevents.PeggyValsetUpdatedEvent) error
 	SendERC20DeployedClaim(ctx context.Context, erc20 *peggyevents.PeggyERC20DeployedEvent) error
+	SendEthereumClaims(ctx context.Context, claims []peggytypes.EthereumClaim) error
+}
+
+// ConfirmSigner signs valset and batch checkpoints with the Ethereum delegate key. It returns the signed
+// checkpoint along with the signature.
+type ConfirmSigner interface {
+	SignValsetConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) (gethcommon.Hash, []byte, error)
+	SignBatchConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) (gethcommon.Hash, []byte, error)
+}
+
+// PersonalSignConfirmSigner signs the checkpoints with a personal sign function of the Ethereum key.
+type PersonalSignConfirmSigner keystore.PersonalSignFn
+
+func (fn PersonalSignConfirmSigner) SignValsetConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) (gethcommon.Hash, []byte, error) {
+	confirmHash := peggy.EncodeValsetConfirm(peggyID, valset)
+	signature, err := fn(ethFrom, confirmHash.Bytes())
+
+	return confirmHash, signature, err
+}
+
+func (fn PersonalSignConfirmSigner) SignBatchConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) (gethcommon.Hash, []byte, error) {
+	confirmHash := peggy.EncodeTxBatchConfirm(peggyID, batch)
+	signature, err := fn(ethFrom, confirmHash.Bytes())
+
+	return confirmHash, signature, err
 }
 
 const broadcastMsgSleepDuration = 1200 * time.Millisecond // 1.2s
@@ -44,15 +70,29 @@ type broadcastClient struct {
 	// successful broadcast we intentionally call time.Sleep() to ensure smooth msg sending.
 	mux sync.Mutex
 
-	ethSignFn keystore.PersonalSignFn
-	svcTags   metrics.Tags
+	confirmSigner     ConfirmSigner
+	maxClaimsTxSize   int
+	maxConfirmsTxSize int
+	auditLog          *audit.Log
+	svcTags           metrics.Tags
 }
 
-func NewBroadcastClient(client chain.ChainClient, signFn keystore.PersonalSignFn) BroadcastClient {
+// NewBroadcastClient returns a client broadcasting Peggy messages to Injective. Valset and batch confirmations
+// signed with the Ethereum key are recorded in the audit log, if not nil.
+func NewBroadcastClient(
+	client chain.ChainClient,
+	confirmSigner ConfirmSigner,
+	maxClaimsTxSize int,
+	maxConfirmsTxSize int,
+	auditLog *audit.Log,
+) BroadcastClient {
 	return &broadcastClient{
-		ChainClient: client,
-		ethSignFn:   signFn,
-		svcTags:     metrics.Tags{"svc": "peggy_broadcast"},
+		ChainClient:       client,
+		confirmSigner:     confirmSigner,
+		maxClaimsTxSize:   maxClaimsTxSize,
+		maxConfirmsTxSize: maxConfirmsTxSize,
+		auditLog:          auditLog,
+		svcTags:           metrics.Tags{"svc": "peggy_broadcast"},
 	}
 }
 
@@ -99,11 +139,10 @@ func (c *broadcastClient) SendValsetConfirm(_ context.Context, ethFrom gethcommo
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	confirmHash := peggy.EncodeValsetConfirm(peggyID, valset)
-	signature, err := c.ethSignFn(ethFrom, confirmHash.Bytes())
+	confirmHash, signature, err := c.confirmSigner.SignValsetConfirm(ethFrom, peggyID, valset)
 	if err != nil {
 		metrics.ReportFuncError(c.svcTags)
-		return errors.New("failed to sign validator address")
+		return errors.Wrap(err, "failed to sign validator address")
 	}
 
 	// MsgValsetConfirm
@@ -134,29 +173,38 @@ func (c *broadcastClient) SendValsetConfirm(_ context.Context, ethFrom gethcommo
 	defer time.Sleep(broadcastMsgSleepDuration)
 
 	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
-	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgValsetConfirm")
-	}
-
-	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgValsetConfirm: %s", resp.TxResponse.RawLog)
-	}
+	switch {
+	case err != nil:
+		err = errors.Wrap(err, "failed to broadcast MsgValsetConfirm")
+	case resp.TxResponse.Code != 0:
+		err = errors.Errorf("failed to broadcast MsgValsetConfirm: %s", resp.TxResponse.RawLog)
+	}
+
+	c.auditLog.Append(audit.Record{
+		Kind:   audit.ValsetConfirm,
+		Digest: confirmHash,
+		Nonce:  valset.Nonce,
+		Signer: ethFrom,
+		TxHash: broadcastTxHash(resp),
+		Error:  auditError(err),
+	})
+
+	return err
+}
 
-	return nil
+func (c *broadcastClient) SendBatchConfirm(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) error {
+	_, err := c.SendBatchConfirms(ctx, ethFrom, peggyID, []*peggytypes.OutgoingTxBatch{batch})
+	return err
 }
 
-func (c *broadcastClient) SendBatchConfirm(_ context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) error {
+// SendBatchConfirms signs the batches and broadcasts the confirmations bundled into as few transactions as the
+// confirms tx size limit allows. A failing batch does not hold back the others: if a bundle fails, its
+// confirmations are broadcast one by one. Returns the confirmed batches along with the last error, if any.
+func (c *broadcastClient) SendBatchConfirms(_ context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error) {
 	metrics.ReportFuncCall(c.svcTags)
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	confirmHash := peggy.EncodeTxBatchConfirm(peggyID, batch)
-	signature, err := c.ethSignFn(ethFrom, confirmHash.Bytes())
-	if err != nil {
-		metrics.ReportFuncError(c.svcTags)
-		return errors.New("failed to sign validator address")
-	}
-
 	// MsgConfirmBatch
 	// When validators observe a MsgRequestBatch they form a batch by ordering
 	// transactions currently in the txqueue in order of highest to lowest fee,
@@ -165,12 +213,70 @@ func (c *broadcastClient) SendBatchConfirm(_ context.Context, ethFrom gethcommon
 	// (TODO determine this without nondeterminism) This message includes the batch
 	// as well as an Ethereum signature over this batch by the validator
 	// -------------
-	msg := &peggytypes.MsgConfirmBatch{
-		Orchestrator:  c.FromAddress().String(),
-		Nonce:         batch.BatchNonce,
-		Signature:     gethcommon.Bytes2Hex(signature),
-		EthSigner:     ethFrom.Hex(),
-		TokenContract: batch.TokenContract,
+	var (
+		msgs      = make([]*peggytypes.MsgConfirmBatch, 0, len(batches))
+		digests   = make(map[*peggytypes.MsgConfirmBatch]gethcommon.Hash, len(batches))
+		msgBatch  = make(map[*peggytypes.MsgConfirmBatch]*peggytypes.OutgoingTxBatch, len(batches))
+		confirmed = make([]*peggytypes.OutgoingTxBatch, 0, len(batches))
+		lastErr   error
+	)
+
+	for _, batch := range batches {
+		confirmHash, signature, err := c.confirmSigner.SignBatchConfirm(ethFrom, peggyID, batch)
+		if err != nil {
+			metrics.ReportFuncError(c.svcTags)
+			lastErr = errors.Wrapf(err, "failed to sign batch %d of token %s", batch.BatchNonce, batch.TokenContract)
+			continue
+		}
+
+		msg := &peggytypes.MsgConfirmBatch{
+			Orchestrator:  c.FromAddress().String(),
+			Nonce:         batch.BatchNonce,
+			Signature:     gethcommon.Bytes2Hex(signature),
+			EthSigner:     ethFrom.Hex(),
+			TokenContract: batch.TokenContract,
+		}
+
+		msgs = append(msgs, msg)
+		digests[msg] = confirmHash
+		msgBatch[msg] = batch
+	}
+
+	for _, bundle := range BundleBatchConfirms(msgs, c.maxConfirmsTxSize) {
+		err := c.broadcastBatchConfirms(bundle, digests)
+		if err == nil {
+			for _, msg := range bundle {
+				confirmed = append(confirmed, msgBatch[msg])
+			}
+
+			continue
+		}
+
+		metrics.ReportFuncError(c.svcTags)
+		lastErr = err
+
+		if len(bundle) == 1 {
+			continue
+		}
+
+		// find out which confirmation fails the bundle and send the rest
+		for _, msg := range bundle {
+			if err := c.broadcastBatchConfirms([]*peggytypes.MsgConfirmBatch{msg}, digests); err != nil {
+				lastErr = err
+				continue
+			}
+
+			confirmed = append(confirmed, msgBatch[msg])
+		}
+	}
+
+	return confirmed, lastErr
+}
+
+func (c *broadcastClient) broadcastBatchConfirms(confirms []*peggytypes.MsgConfirmBatch, digests map[*peggytypes.MsgConfirmBatch]gethcommon.Hash) error {
+	msgs := make([]cosmostypes.Msg, len(confirms))
+	for i, confirm := range confirms {
+		msgs[i] = confirm
 	}
 
 	c.mux.Lock()
@@ -178,13 +284,36 @@ func (c *broadcastClient) SendBatchConfirm(_ context.Context, ethFrom gethcommon
 
 	defer time.Sleep(broadcastMsgSleepDuration)
 
-	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
+	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msgs...)
+	switch {
+	case err != nil:
+		err = errors.Wrapf(err, "failed to broadcast %s", batchConfirmsDescription(confirms))
+	case resp.TxResponse.Code != 0:
+		err = errors.Errorf("failed to broadcast %s: %s", batchConfirmsDescription(confirms), resp.TxResponse.RawLog)
+	}
+
+	for _, confirm := range confirms {
+		c.auditLog.Append(audit.Record{
+			Kind:          audit.BatchConfirm,
+			Digest:        digests[confirm],
+			Nonce:         confirm.Nonce,
+			TokenContract: confirm.TokenContract,
+			Signer:        gethcommon.HexToAddress(confirm.EthSigner),
+			TxHash:        broadcastTxHash(resp),
+			Error:         auditError(err),
+		})
+	}
+
 	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgConfirmBatch")
+		return err
 	}
 
-	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgConfirmBatch: %s", resp.TxResponse.RawLog)
+	for _, confirm := range confirms {
+		log.WithFields(log.Fields{
+			"token_contract": confirm.TokenContract,
+			"batch_nonce":    confirm.Nonce,
+			"tx_hash":        resp.TxResponse.TxHash,
+		}).Debugln("sent MsgConfirmBatch")
 	}
 
 	return nil
@@ -274,45 +403,9 @@ func (c *broadcastClient) SendOldDepositClaim(_ context.Context, deposit *peggye
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	log.WithFields(log.Fields{
-		"sender":      deposit.Sender.Hex(),
-		"destination": cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
-		"amount":      deposit.Amount.String(),
-		"event_nonce": deposit.EventNonce.String(),
-	}).Debugln("observed SendToCosmosEvent")
-
-	msg := &peggytypes.MsgDepositClaim{
-		EventNonce:     deposit.EventNonce.Uint64(),
-		BlockHeight:    deposit.Raw.BlockNumber,
-		TokenContract:  deposit.TokenContract.Hex(),
-		Amount:         sdkmath.NewIntFromBigInt(deposit.Amount),
-		EthereumSender: deposit.Sender.Hex(),
-		CosmosReceiver: cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
-		Orchestrator:   c.ChainClient.FromAddress().String(),
-		Data:           "",
-	}
+	msg := NewOldDepositClaimMsg(deposit, c.ChainClient.FromAddress())
 
-	c.mux.Lock()
-	defer c.mux.Unlock()
-
-	defer time.Sleep(broadcastMsgSleepDuration)
-
-	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
-	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgDepositClaim")
-	}
-
-	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgDepositClaim: %s", resp.TxResponse.RawLog)
-	}
-
-	log.WithFields(log.Fields{
-		"event_height": msg.BlockHeight,
-		"event_nonce":  msg.EventNonce,
-		"tx_hash":      resp.TxResponse.TxHash,
-	}).Infoln("Oracle sent MsgDepositClaim")
-
-	return nil
+	return c.broadcastClaims(msg)
 }
 
 func (c *broadcastClient) SendDepositClaim(_ context.Context, deposit *peggyevents.PeggySendToInjectiveEvent) error {
@@ -325,46 +418,9 @@ func (c *broadcastClient) SendDepositClaim(_ context.Context, deposit *peggyeven
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	log.WithFields(log.Fields{
-		"sender":         deposit.Sender.Hex(),
-		"destination":    cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
-		"amount":         deposit.Amount.String(),
-		"data":           deposit.Data,
-		"token_contract": deposit.TokenContract.Hex(),
-	}).Debugln("observed SendToInjectiveEvent")
-
-	msg := &peggytypes.MsgDepositClaim{
-		EventNonce:     deposit.EventNonce.Uint64(),
-		BlockHeight:    deposit.Raw.BlockNumber,
-		TokenContract:  deposit.TokenContract.Hex(),
-		Amount:         sdkmath.NewIntFromBigInt(deposit.Amount),
-		EthereumSender: deposit.Sender.Hex(),
-		CosmosReceiver: cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
-		Orchestrator:   c.ChainClient.FromAddress().String(),
-		Data:           deposit.Data,
-	}
+	msg := NewDepositClaimMsg(deposit, c.ChainClient.FromAddress())
 
-	c.mux.Lock()
-	defer c.mux.Unlock()
-
-	defer time.Sleep(broadcastMsgSleepDuration)
-
-	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
-	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgDepositClaim")
-	}
-
-	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgDepositClaim: %s", resp.TxResponse.RawLog)
-	}
-
-	log.WithFields(log.Fields{
-		"event_nonce":  msg.EventNonce,
-		"event_height": msg.BlockHeight,
-		"tx_hash":      resp.TxResponse.TxHash,
-	}).Infoln("EthOracle sent MsgDepositClaim")
-
-	return nil
+	return c.broadcastClaims(msg)
 }
 
 func (c *broadcastClient) SendWithdrawalClaim(_ context.Context, withdrawal *peggyevents.PeggyTransactionBatchExecutedEvent) error {
@@ -372,141 +428,104 @@ func (c *broadcastClient) SendWithdrawalClaim(_ context.Context, withdrawal *peg
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	log.WithFields(log.Fields{
-		"batch_nonce":    withdrawal.BatchNonce.String(),
-		"token_contract": withdrawal.Token.Hex(),
-	}).Debugln("observed TransactionBatchExecutedEvent")
-
 	// WithdrawClaim claims that a batch of withdrawal
 	// operations on the bridge contract was executed.
-	msg := &peggytypes.MsgWithdrawClaim{
-		EventNonce:    withdrawal.EventNonce.Uint64(),
-		BatchNonce:    withdrawal.BatchNonce.Uint64(),
-		BlockHeight:   withdrawal.Raw.BlockNumber,
-		TokenContract: withdrawal.Token.Hex(),
-		Orchestrator:  c.FromAddress().String(),
-	}
+	msg := NewWithdrawClaimMsg(withdrawal, c.ChainClient.FromAddress())
 
-	c.mux.Lock()
-	defer c.mux.Unlock()
+	return c.broadcastClaims(msg)
+}
 
-	defer time.Sleep(broadcastMsgSleepDuration)
+func (c *broadcastClient) SendValsetClaim(_ context.Context, vs *peggyevents.PeggyValsetUpdatedEvent) error {
+	metrics.ReportFuncCall(c.svcTags)
+	doneFn := metrics.ReportFuncTiming(c.svcTags)
+	defer doneFn()
 
-	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
-	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgWithdrawClaim")
-	}
+	msg := NewValsetUpdatedClaimMsg(vs, c.ChainClient.FromAddress())
 
-	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgWithdrawClaim: %s", resp.TxResponse.RawLog)
-	}
+	return c.broadcastClaims(msg)
+}
 
-	log.WithFields(log.Fields{
-		"event_height": msg.BlockHeight,
-		"event_nonce":  msg.EventNonce,
-		"tx_hash":      resp.TxResponse.TxHash,
-	}).Infoln("EthOracle sent MsgWithdrawClaim")
+func (c *broadcastClient) SendERC20DeployedClaim(_ context.Context, erc20 *peggyevents.PeggyERC20DeployedEvent) error {
+	metrics.ReportFuncCall(c.svcTags)
+	doneFn := metrics.ReportFuncTiming(c.svcTags)
+	defer doneFn()
 
-	return nil
+	msg := NewERC20DeployedClaimMsg(erc20, c.ChainClient.FromAddress())
+
+	return c.broadcastClaims(msg)
 }
 
-func (c *broadcastClient) SendValsetClaim(_ context.Context, vs *peggyevents.PeggyValsetUpdatedEvent) error {
+// SendEthereumClaims broadcasts the claims, which must be sorted by event nonce, bundled into as few
+// transactions as the claims tx size limit allows. Bundles are sent one after another, so if one of them fails
+// the claims in it and all the following bundles are not sent and the caller should resume from the last
+// event nonce observed on Injective.
+func (c *broadcastClient) SendEthereumClaims(_ context.Context, claims []peggytypes.EthereumClaim) error {
 	metrics.ReportFuncCall(c.svcTags)
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	log.WithFields(log.Fields{
-		"valset_nonce":  vs.NewValsetNonce.Uint64(),
-		"validators":    vs.Validators,
-		"powers":        vs.Powers,
-		"reward_amount": vs.RewardAmount,
-		"reward_token":  vs.RewardToken.Hex(),
-	}).Debugln("observed ValsetUpdatedEvent")
-
-	members := make([]*peggytypes.BridgeValidator, len(vs.Validators))
-	for i, val := range vs.Validators {
-		members[i] = &peggytypes.BridgeValidator{
-			EthereumAddress: val.Hex(),
-			Power:           vs.Powers[i].Uint64(),
+	bundles, err := BundleClaims(claims, c.maxClaimsTxSize)
+	if err != nil {
+		metrics.ReportFuncError(c.svcTags)
+		return err
+	}
+
+	for _, bundle := range bundles {
+		if err := c.broadcastClaims(bundle...); err != nil {
+			metrics.ReportFuncError(c.svcTags)
+			return err
 		}
 	}
 
-	msg := &peggytypes.MsgValsetUpdatedClaim{
-		EventNonce:   vs.EventNonce.Uint64(),
-		ValsetNonce:  vs.NewValsetNonce.Uint64(),
-		BlockHeight:  vs.Raw.BlockNumber,
-		RewardAmount: sdkmath.NewIntFromBigInt(vs.RewardAmount),
-		RewardToken:  vs.RewardToken.Hex(),
-		Members:      members,
-		Orchestrator: c.FromAddress().String(),
+	return nil
+}
+
+// broadcastClaims sends the claims in a single transaction. Since the peggy module rejects claims with a
+// non-contiguous event nonce in CheckTx, we wait for the transaction to be included before sending the next one.
+// Failures in CheckTx, in the block or to get the tx included in time are returned.
+func (c *broadcastClient) broadcastClaims(claims ...peggytypes.EthereumClaim) error {
+	msgs := make([]cosmostypes.Msg, len(claims))
+	for i, claim := range claims {
+		msgs[i] = claim.(cosmostypes.Msg)
 	}
 
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
-	defer time.Sleep(broadcastMsgSleepDuration)
-
-	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
+	// polls the tx by hash until it is included in a block
+	resp, err := c.ChainClient.SyncBroadcastMsg(msgs...)
 	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgValsetUpdatedClaim")
+		return errors.Wrapf(err, "failed to broadcast %s", claimsDescription(claims))
 	}
 
 	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgValsetUpdatedClaim: %s", resp.TxResponse.RawLog)
+		return errors.Errorf("failed to broadcast %s: %s", claimsDescription(claims), resp.TxResponse.RawLog)
 	}
 
-	log.WithFields(log.Fields{
-		"event_nonce":  msg.EventNonce,
-		"event_height": msg.BlockHeight,
-		"tx_hash":      resp.TxResponse.TxHash,
-	}).Infoln("Oracle sent MsgValsetUpdatedClaim")
+	for _, claim := range claims {
+		log.WithFields(log.Fields{
+			"event_nonce":  claim.GetEventNonce(),
+			"event_height": claim.GetBlockHeight(),
+			"tx_hash":      resp.TxResponse.TxHash,
+		}).Infof("Oracle sent %s", claimName(claim))
+	}
 
 	return nil
 }
 
-func (c *broadcastClient) SendERC20DeployedClaim(_ context.Context, erc20 *peggyevents.PeggyERC20DeployedEvent) error {
-	metrics.ReportFuncCall(c.svcTags)
-	doneFn := metrics.ReportFuncTiming(c.svcTags)
-	defer doneFn()
-
-	log.WithFields(log.Fields{
-		"cosmos_denom":   erc20.CosmosDenom,
-		"token_contract": erc20.TokenContract.Hex(),
-		"name":           erc20.Name,
-		"symbol":         erc20.Symbol,
-		"decimals":       erc20.Decimals,
-	}).Debugln("observed ERC20DeployedEvent")
-
-	msg := &peggytypes.MsgERC20DeployedClaim{
-		EventNonce:    erc20.EventNonce.Uint64(),
-		BlockHeight:   erc20.Raw.BlockNumber,
-		CosmosDenom:   erc20.CosmosDenom,
-		TokenContract: erc20.TokenContract.Hex(),
-		Name:          erc20.Name,
-		Symbol:        erc20.Symbol,
-		Decimals:      uint64(erc20.Decimals),
-		Orchestrator:  c.FromAddress().String(),
+// broadcastTxHash returns the hash of the broadcast tx, or an empty string if it was not broadcast
+func broadcastTxHash(resp *cosmostx.BroadcastTxResponse) string {
+	if resp == nil || resp.TxResponse == nil {
+		return ""
 	}
 
-	c.mux.Lock()
-	defer c.mux.Unlock()
-
-	defer time.Sleep(broadcastMsgSleepDuration)
-
-	_, resp, err := c.ChainClient.BroadcastMsg(cosmostx.BroadcastMode_BROADCAST_MODE_SYNC, msg)
-	if err != nil {
-		return errors.Wrap(err, "failed to broadcast MsgERC20DeployedClaim")
-	}
+	return resp.TxResponse.TxHash
+}
 
-	if resp.TxResponse.Code != 0 {
-		return errors.Errorf("failed to broadcast MsgERC20DeployedClaim: %s", resp.TxResponse.RawLog)
+func auditError(err error) string {
+	if err == nil {
+		return ""
 	}
 
-	log.WithFields(log.Fields{
-		"event_nonce":  msg.EventNonce,
-		"event_height": msg.BlockHeight,
-		"tx_hash":      resp.TxResponse.TxHash,
-	}).Infoln("Oracle sent MsgERC20DeployedClaim")
-
-	return nil
+	return err.Error()
 }
@@ -0,0 +1,182 @@
+package peggy
+
+import (
+	"fmt"
+	"strings"
+
+	sdkmath "cosmossdk.io/math"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// DefaultMaxClaimsTxSize is the default limit for the total size (in bytes) of claim msgs bundled into one tx
+const DefaultMaxClaimsTxSize = 64 * 1024
+
+func NewOldDepositClaimMsg(deposit *peggyevents.PeggySendToCosmosEvent, orchestrator cosmostypes.AccAddress) *peggytypes.MsgDepositClaim {
+	log.WithFields(log.Fields{
+		"sender":      deposit.Sender.Hex(),
+		"destination": cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
+		"amount":      deposit.Amount.String(),
+		"event_nonce": deposit.EventNonce.String(),
+	}).Debugln("observed SendToCosmosEvent")
+
+	return &peggytypes.MsgDepositClaim{
+		EventNonce:     deposit.EventNonce.Uint64(),
+		BlockHeight:    deposit.Raw.BlockNumber,
+		TokenContract:  deposit.TokenContract.Hex(),
+		Amount:         sdkmath.NewIntFromBigInt(deposit.Amount),
+		EthereumSender: deposit.Sender.Hex(),
+		CosmosReceiver: cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
+		Orchestrator:   orchestrator.String(),
+		Data:           "",
+	}
+}
+
+func NewDepositClaimMsg(deposit *peggyevents.PeggySendToInjectiveEvent, orchestrator cosmostypes.AccAddress) *peggytypes.MsgDepositClaim {
+	log.WithFields(log.Fields{
+		"sender":         deposit.Sender.Hex(),
+		"destination":    cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
+		"amount":         deposit.Amount.String(),
+		"data":           deposit.Data,
+		"token_contract": deposit.TokenContract.Hex(),
+	}).Debugln("observed SendToInjectiveEvent")
+
+	return &peggytypes.MsgDepositClaim{
+		EventNonce:     deposit.EventNonce.Uint64(),
+		BlockHeight:    deposit.Raw.BlockNumber,
+		TokenContract:  deposit.TokenContract.Hex(),
+		Amount:         sdkmath.NewIntFromBigInt(deposit.Amount),
+		EthereumSender: deposit.Sender.Hex(),
+		CosmosReceiver: cosmostypes.AccAddress(deposit.Destination[12:32]).String(),
+		Orchestrator:   orchestrator.String(),
+		Data:           deposit.Data,
+	}
+}
+
+func NewWithdrawClaimMsg(withdrawal *peggyevents.PeggyTransactionBatchExecutedEvent, orchestrator cosmostypes.AccAddress) *peggytypes.MsgWithdrawClaim {
+	log.WithFields(log.Fields{
+		"batch_nonce":    withdrawal.BatchNonce.String(),
+		"token_contract": withdrawal.Token.Hex(),
+	}).Debugln("observed TransactionBatchExecutedEvent")
+
+	return &peggytypes.MsgWithdrawClaim{
+		EventNonce:    withdrawal.EventNonce.Uint64(),
+		BatchNonce:    withdrawal.BatchNonce.Uint64(),
+		BlockHeight:   withdrawal.Raw.BlockNumber,
+		TokenContract: withdrawal.Token.Hex(),
+		Orchestrator:  orchestrator.String(),
+	}
+}
+
+func NewValsetUpdatedClaimMsg(vs *peggyevents.PeggyValsetUpdatedEvent, orchestrator cosmostypes.AccAddress) *peggytypes.MsgValsetUpdatedClaim {
+	log.WithFields(log.Fields{
+		"valset_nonce":  vs.NewValsetNonce.Uint64(),
+		"validators":    vs.Validators,
+		"powers":        vs.Powers,
+		"reward_amount": vs.RewardAmount,
+		"reward_token":  vs.RewardToken.Hex(),
+	}).Debugln("observed ValsetUpdatedEvent")
+
+	members := make([]*peggytypes.BridgeValidator, len(vs.Validators))
+	for i, val := range vs.Validators {
+		members[i] = &peggytypes.BridgeValidator{
+			EthereumAddress: val.Hex(),
+			Power:           vs.Powers[i].Uint64(),
+		}
+	}
+
+	return &peggytypes.MsgValsetUpdatedClaim{
+		EventNonce:   vs.EventNonce.Uint64(),
+		ValsetNonce:  vs.NewValsetNonce.Uint64(),
+		BlockHeight:  vs.Raw.BlockNumber,
+		RewardAmount: sdkmath.NewIntFromBigInt(vs.RewardAmount),
+		RewardToken:  vs.RewardToken.Hex(),
+		Members:      members,
+		Orchestrator: orchestrator.String(),
+	}
+}
+
+func NewERC20DeployedClaimMsg(erc20 *peggyevents.PeggyERC20DeployedEvent, orchestrator cosmostypes.AccAddress) *peggytypes.MsgERC20DeployedClaim {
+	log.WithFields(log.Fields{
+		"cosmos_denom":   erc20.CosmosDenom,
+		"token_contract": erc20.TokenContract.Hex(),
+		"name":           erc20.Name,
+		"symbol":         erc20.Symbol,
+		"decimals":       erc20.Decimals,
+	}).Debugln("observed ERC20DeployedEvent")
+
+	return &peggytypes.MsgERC20DeployedClaim{
+		EventNonce:    erc20.EventNonce.Uint64(),
+		BlockHeight:   erc20.Raw.BlockNumber,
+		CosmosDenom:   erc20.CosmosDenom,
+		TokenContract: erc20.TokenContract.Hex(),
+		Name:          erc20.Name,
+		Symbol:        erc20.Symbol,
+		Decimals:      uint64(erc20.Decimals),
+		Orchestrator:  orchestrator.String(),
+	}
+}
+
+// BundleClaims splits the claims into consecutive bundles so that the total msg size of each bundle does not
+// exceed maxTxSize. A claim larger than maxTxSize is sent in a bundle of its own. The claims must be sorted
+// by event nonce.
+func BundleClaims(claims []peggytypes.EthereumClaim, maxTxSize int) ([][]peggytypes.EthereumClaim, error) {
+	if maxTxSize <= 0 {
+		maxTxSize = DefaultMaxClaimsTxSize
+	}
+
+	var (
+		bundles    [][]peggytypes.EthereumClaim
+		bundle     []peggytypes.EthereumClaim
+		bundleSize int
+	)
+
+	for i, claim := range claims {
+		if _, ok := claim.(cosmostypes.Msg); !ok {
+			return nil, errors.Errorf("claim %T is not a cosmos msg", claim)
+		}
+
+		if i > 0 && claim.GetEventNonce() <= claims[i-1].GetEventNonce() {
+			return nil, errors.Errorf("claims are not sorted by event nonce: %d follows %d", claim.GetEventNonce(), claims[i-1].GetEventNonce())
+		}
+
+		size := claimSize(claim)
+		if len(bundle) > 0 && bundleSize+size > maxTxSize {
+			bundles = append(bundles, bundle)
+			bundle, bundleSize = nil, 0
+		}
+
+		bundle = append(bundle, claim)
+		bundleSize += size
+	}
+
+	if len(bundle) > 0 {
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles, nil
+}
+
+func claimSize(claim peggytypes.EthereumClaim) int {
+	if m, ok := claim.(interface{ Size() int }); ok {
+		return m.Size()
+	}
+
+	return 0
+}
+
+func claimName(claim peggytypes.EthereumClaim) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", claim), "*types.")
+}
+
+func claimsDescription(claims []peggytypes.EthereumClaim) string {
+	if len(claims) == 1 {
+		return claimName(claims[0])
+	}
+
+	return fmt.Sprintf("%d claims (event nonces %d-%d)", len(claims), claims[0].GetEventNonce(), claims[len(claims)-1].GetEventNonce())
+}
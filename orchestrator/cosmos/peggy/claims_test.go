@@ -0,0 +1,85 @@
+package peggy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+func TestBundleClaims(t *testing.T) {
+	t.Parallel()
+
+	claim := func(nonce uint64) peggytypes.EthereumClaim {
+		return &peggytypes.MsgWithdrawClaim{EventNonce: nonce, BatchNonce: 1, BlockHeight: 100}
+	}
+
+	nonces := func(bundles [][]peggytypes.EthereumClaim) (out [][]uint64) {
+		for _, bundle := range bundles {
+			var n []uint64
+			for _, c := range bundle {
+				n = append(n, c.GetEventNonce())
+			}
+			out = append(out, n)
+		}
+		return out
+	}
+
+	claimSize := claim(1).(*peggytypes.MsgWithdrawClaim).Size()
+
+	testTable := []struct {
+		name      string
+		claims    []peggytypes.EthereumClaim
+		maxTxSize int
+		expected  [][]uint64
+		expectErr bool
+	}{
+		{
+			name:     "no claims",
+			expected: nil,
+		},
+
+		{
+			name:     "single bundle",
+			claims:   []peggytypes.EthereumClaim{claim(1), claim(2), claim(3)},
+			expected: [][]uint64{{1, 2, 3}},
+		},
+
+		{
+			name:      "split by size",
+			claims:    []peggytypes.EthereumClaim{claim(1), claim(2), claim(3)},
+			maxTxSize: 2 * claimSize,
+			expected:  [][]uint64{{1, 2}, {3}},
+		},
+
+		{
+			name:      "claim larger than limit",
+			claims:    []peggytypes.EthereumClaim{claim(1), claim(2)},
+			maxTxSize: 1,
+			expected:  [][]uint64{{1}, {2}},
+		},
+
+		{
+			name:      "unordered claims",
+			claims:    []peggytypes.EthereumClaim{claim(2), claim(1)},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bundles, err := BundleClaims(tt.claims, tt.maxTxSize)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, nonces(bundles))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package peggy
+
+import (
+	"fmt"
+
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// DefaultMaxConfirmsTxSize is the default limit for the total size (in bytes) of batch confirm msgs bundled into one tx
+const DefaultMaxConfirmsTxSize = 64 * 1024
+
+// BundleBatchConfirms splits the batch confirmations into consecutive bundles so that the total msg size of each
+// bundle does not exceed maxTxSize. A confirmation larger than maxTxSize is sent in a bundle of its own.
+func BundleBatchConfirms(msgs []*peggytypes.MsgConfirmBatch, maxTxSize int) [][]*peggytypes.MsgConfirmBatch {
+	if maxTxSize <= 0 {
+		maxTxSize = DefaultMaxConfirmsTxSize
+	}
+
+	var (
+		bundles    [][]*peggytypes.MsgConfirmBatch
+		bundle     []*peggytypes.MsgConfirmBatch
+		bundleSize int
+	)
+
+	for _, msg := range msgs {
+		size := msg.Size()
+		if len(bundle) > 0 && bundleSize+size > maxTxSize {
+			bundles = append(bundles, bundle)
+			bundle, bundleSize = nil, 0
+		}
+
+		bundle = append(bundle, msg)
+		bundleSize += size
+	}
+
+	if len(bundle) > 0 {
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles
+}
+
+func batchConfirmsDescription(msgs []*peggytypes.MsgConfirmBatch) string {
+	if len(msgs) == 1 {
+		return "MsgConfirmBatch"
+	}
+
+	return fmt.Sprintf("%d MsgConfirmBatch", len(msgs))
+}
@@ -0,0 +1,71 @@
+package peggy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+func TestBundleBatchConfirms(t *testing.T) {
+	t.Parallel()
+
+	confirm := func(nonce uint64) *peggytypes.MsgConfirmBatch {
+		return &peggytypes.MsgConfirmBatch{Nonce: nonce, TokenContract: "0xB8c77482e45F1F44dE1745F52C74426C631bDD52", Signature: "abcd"}
+	}
+
+	nonces := func(bundles [][]*peggytypes.MsgConfirmBatch) (out [][]uint64) {
+		for _, bundle := range bundles {
+			var n []uint64
+			for _, c := range bundle {
+				n = append(n, c.Nonce)
+			}
+			out = append(out, n)
+		}
+		return out
+	}
+
+	confirmSize := confirm(1).Size()
+
+	testTable := []struct {
+		name      string
+		confirms  []*peggytypes.MsgConfirmBatch
+		maxTxSize int
+		expected  [][]uint64
+	}{
+		{
+			name:     "no confirms",
+			expected: nil,
+		},
+
+		{
+			name:     "single bundle",
+			confirms: []*peggytypes.MsgConfirmBatch{confirm(1), confirm(2), confirm(3)},
+			expected: [][]uint64{{1, 2, 3}},
+		},
+
+		{
+			name:      "split by size",
+			confirms:  []*peggytypes.MsgConfirmBatch{confirm(1), confirm(2), confirm(3)},
+			maxTxSize: 2 * confirmSize,
+			expected:  [][]uint64{{1, 2}, {3}},
+		},
+
+		{
+			name:      "confirm larger than limit",
+			confirms:  []*peggytypes.MsgConfirmBatch{confirm(1), confirm(2)},
+			maxTxSize: 1,
+			expected:  [][]uint64{{1}, {2}},
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, nonces(BundleBatchConfirms(tt.confirms, tt.maxTxSize)))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package peggy
 
 import (
 	"context"
+	"strings"
 
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
 	gethcommon "github.com/ethereum/go-ethereum/common"
@@ -17,6 +18,7 @@ type QueryClient interface {
 	PeggyParams(ctx context.Context) (*peggytypes.Params, error)
 	LastClaimEventByAddr(ctx context.Context, validatorAccountAddress cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error)
 	GetValidatorAddress(ctx context.Context, addr gethcommon.Address) (cosmostypes.AccAddress, error)
+	GetDelegateEthereumAddress(ctx context.Context, validator cosmostypes.AccAddress) (gethcommon.Address, error)
 
 	ValsetAt(ctx context.Context, nonce uint64) (*peggytypes.Valset, error)
 	CurrentValset(ctx context.Context) (*peggytypes.Valset, error)
@@ -27,6 +29,7 @@ type QueryClient interface {
 	OldestUnsignedTransactionBatch(ctx context.Context, valAccountAddress cosmostypes.AccAddress) (*peggytypes.OutgoingTxBatch, error)
 	LatestTransactionBatches(ctx context.Context) ([]*peggytypes.OutgoingTxBatch, error)
 	UnbatchedTokensWithFees(ctx context.Context) ([]*peggytypes.BatchFees, error)
+	UnbatchedTransfers(ctx context.Context) ([]*peggytypes.OutgoingTransferTx, error)
 	TransactionBatchSignatures(ctx context.Context, nonce uint64, tokenContract gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error)
 }
 
@@ -205,6 +208,27 @@ func (c queryClient) UnbatchedTokensWithFees(ctx context.Context) ([]*peggytypes
 	return resp.BatchFees, nil
 }
 
+// UnbatchedTransfers returns the withdrawals waiting in the pool. There is no dedicated query for them, so they are
+// taken from the module state, which is expensive for the node: callers cache them.
+func (c queryClient) UnbatchedTransfers(ctx context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+	metrics.ReportFuncCall(c.svcTags)
+	doneFn := metrics.ReportFuncTiming(c.svcTags)
+	defer doneFn()
+
+	resp, err := c.QueryClient.PeggyModuleState(ctx, &peggytypes.QueryModuleStateRequest{})
+	if err != nil {
+		metrics.ReportFuncError(c.svcTags)
+		return nil, errors.Wrap(err, "failed to query PeggyModuleState from daemon")
+	}
+
+	if resp == nil || resp.State == nil {
+		metrics.ReportFuncError(c.svcTags)
+		return nil, ErrNotFound
+	}
+
+	return resp.State.UnbatchedTransfers, nil
+}
+
 func (c queryClient) TransactionBatchSignatures(ctx context.Context, nonce uint64, tokenContract gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
 	metrics.ReportFuncCall(c.svcTags)
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
@@ -298,3 +322,32 @@ func (c queryClient) GetValidatorAddress(ctx context.Context, addr gethcommon.Ad
 
 	return valAddr, nil
 }
+
+// GetDelegateEthereumAddress returns the Ethereum key registered by the validator (identified by its account
+// address). ErrNotFound is returned if the validator did not register delegate keys.
+func (c queryClient) GetDelegateEthereumAddress(ctx context.Context, validator cosmostypes.AccAddress) (gethcommon.Address, error) {
+	metrics.ReportFuncCall(c.svcTags)
+	doneFn := metrics.ReportFuncTiming(c.svcTags)
+	defer doneFn()
+
+	req := &peggytypes.QueryDelegateKeysByValidatorAddress{
+		ValidatorAddress: validator.String(),
+	}
+
+	resp, err := c.QueryClient.GetDelegateKeyByValidator(ctx, req)
+	if err != nil {
+		// the module does not distinguish unregistered validators by error code
+		if strings.Contains(err.Error(), "No validator") {
+			return gethcommon.Address{}, ErrNotFound
+		}
+
+		metrics.ReportFuncError(c.svcTags)
+		return gethcommon.Address{}, errors.Wrap(err, "failed to query GetDelegateKeyByValidator from client")
+	}
+
+	if resp == nil || !gethcommon.IsHexAddress(resp.EthAddress) {
+		return gethcommon.Address{}, ErrNotFound
+	}
+
+	return gethcommon.HexToAddress(resp.EthAddress), nil
+}
@@ -0,0 +1,79 @@
+package staking
+
+import (
+	"context"
+	"strconv"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+type ValidatorsClient interface {
+	// Validators returns all the validators known to the staking module at the given height (the latest one if
+	// zero), regardless of their bonding status
+	Validators(ctx context.Context, height int64) ([]stakingtypes.Validator, error)
+}
+
+type queryClient struct {
+	stakingtypes.QueryClient
+
+	// unpacks the consensus public keys of validators
+	unpacker codectypes.AnyUnpacker
+	svcTags  metrics.Tags
+}
+
+func NewValidatorsClient(client stakingtypes.QueryClient, unpacker codectypes.AnyUnpacker) ValidatorsClient {
+	return queryClient{
+		QueryClient: client,
+		unpacker:    unpacker,
+		svcTags:     metrics.Tags{"svc": "staking_query"},
+	}
+}
+
+func (c queryClient) Validators(ctx context.Context, height int64) ([]stakingtypes.Validator, error) {
+	metrics.ReportFuncCall(c.svcTags)
+	doneFn := metrics.ReportFuncTiming(c.svcTags)
+	defer doneFn()
+
+	if height > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+	}
+
+	var (
+		validators []stakingtypes.Validator
+		nextKey    []byte
+	)
+
+	for {
+		req := &stakingtypes.QueryValidatorsRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+
+		resp, err := c.QueryClient.Validators(ctx, req)
+		if err != nil {
+			metrics.ReportFuncError(c.svcTags)
+			return nil, errors.Wrap(err, "failed to query Validators from client")
+		}
+
+		for _, v := range resp.Validators {
+			if err := v.UnpackInterfaces(c.unpacker); err != nil {
+				metrics.ReportFuncError(c.svcTags)
+				return nil, errors.Wrapf(err, "failed to unpack consensus pubkey of validator %s", v.OperatorAddress)
+			}
+
+			validators = append(validators, v)
+		}
+
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return validators, nil
+		}
+
+		nextKey = resp.Pagination.NextKey
+	}
+}
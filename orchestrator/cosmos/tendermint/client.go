@@ -11,6 +11,9 @@ import (
 	log "github.com/xlab/suplog"
 )
 
+// Maximum page size of the Tendermint validators endpoint
+const validatorsPerPage = 100
+
 type Client interface {
 	GetBlock(ctx context.Context, height int64) (*comettypes.ResultBlock, error)
 	GetLatestBlockHeight(ctx context.Context) (int64, error)
@@ -92,5 +95,28 @@ func (c *tmClient) GetValidatorSet(ctx context.Context, height int64) (*comettyp
 	doneFn := metrics.ReportFuncTiming(c.svcTags)
 	defer doneFn()
 
-	return c.rpcClient.Validators(ctx, &height, nil, nil)
+	perPage := validatorsPerPage
+
+	var validators *comettypes.ResultValidators
+	for page := 1; ; page++ {
+		resp, err := c.rpcClient.Validators(ctx, &height, &page, &perPage)
+		if err != nil {
+			metrics.ReportFuncError(c.svcTags)
+			return nil, err
+		}
+
+		if validators == nil {
+			validators = resp
+		} else {
+			validators.Validators = append(validators.Validators, resp.Validators...)
+		}
+
+		if len(resp.Validators) == 0 || len(validators.Validators) >= resp.Total {
+			break
+		}
+	}
+
+	validators.Count = len(validators.Validators)
+
+	return validators, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/provider"
 )
 
@@ -31,6 +32,7 @@ type options struct {
 	GasPrice   decimal.Decimal
 	GasLimit   uint64
 	RPCTimeout time.Duration
+	AuditLog   *audit.Log
 }
 
 func defaultOptions() *options {
@@ -66,6 +68,14 @@ func OptionGasPriceFromString(str string) EVMCommitterOption {
 	}
 }
 
+// OptionAuditLog records every transaction signed by the committer in the audit log.
+func OptionAuditLog(auditLog *audit.Log) EVMCommitterOption {
+	return func(o *options) error {
+		o.AuditLog = auditLog
+		return nil
+	}
+}
+
 func ParseMaxGasPrice(maxGasPriceStr string) int64 {
 	maxGasPriceStr = strings.TrimSpace(maxGasPriceStr)
 	maxGasPriceStr = strings.ToLower(maxGasPriceStr)
@@ -15,6 +15,7 @@ import (
 
 	"github.com/InjectiveLabs/metrics"
 
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/provider"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/util"
 )
@@ -150,7 +151,6 @@ func (e *ethCommitter) SendTx(
 
 		for {
 			opts.Nonce = big.NewInt(nonce)
-			opts.Context, _ = context.WithTimeout(ctx, e.committerOpts.RPCTimeout)
 
 			tx := types.NewTransaction(opts.Nonce.Uint64(), recipient, nil, opts.GasLimit, opts.GasPrice, txData)
 			signedTx, err := opts.Signer(opts.From, tx)
@@ -161,7 +161,13 @@ func (e *ethCommitter) SendTx(
 
 			txHash = signedTx.Hash()
 
+			var cancelFn context.CancelFunc
+			opts.Context, cancelFn = context.WithTimeout(ctx, e.committerOpts.RPCTimeout)
 			txHashRet, err := e.evmProvider.SendTransactionWithRet(opts.Context, signedTx)
+			cancelFn()
+
+			e.auditTx(signedTx, txHashRet, err)
+
 			if err == nil {
 				// override with a real hash from node resp
 				txHash = txHashRet
@@ -224,3 +230,26 @@ func (e *ethCommitter) SendTx(
 
 	return txHash, nil
 }
+
+// auditTx records the signed transaction in the audit log, along with the hash returned by the node
+func (e *ethCommitter) auditTx(signedTx *types.Transaction, txHash common.Hash, sendErr error) {
+	if e.committerOpts.AuditLog == nil {
+		return
+	}
+
+	record := audit.Record{
+		Kind:   audit.EthTx,
+		Digest: types.LatestSignerForChainID(signedTx.ChainId()).Hash(signedTx),
+		Nonce:  signedTx.Nonce(),
+		Signer: e.fromAddress,
+		TxHash: signedTx.Hash().Hex(),
+	}
+
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	} else if txHash != (common.Hash{}) {
+		record.TxHash = txHash.Hex()
+	}
+
+	e.committerOpts.AuditLog.Append(record)
+}
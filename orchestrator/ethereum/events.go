@@ -0,0 +1,140 @@
+package ethereum
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
+)
+
+var peggyABI, _ = abi.JSON(strings.NewReader(peggyevents.PeggyABI))
+
+// Names of the Peggy contract events that are claimed on Injective
+const (
+	sendToCosmosEvent             = "SendToCosmosEvent"
+	sendToInjectiveEvent          = "SendToInjectiveEvent"
+	transactionBatchExecutedEvent = "TransactionBatchExecutedEvent"
+	erc20DeployedEvent            = "ERC20DeployedEvent"
+	valsetUpdatedEvent            = "ValsetUpdatedEvent"
+)
+
+// PeggyEvent is a Peggy contract event decoded into one of its wrapper types:
+// *PeggySendToCosmosEvent, *PeggySendToInjectiveEvent, *PeggyTransactionBatchExecutedEvent,
+// *PeggyERC20DeployedEvent or *PeggyValsetUpdatedEvent
+type PeggyEvent struct {
+	Nonce uint64
+	Event interface{}
+}
+
+// LogsWindow returns the number of blocks the provider currently accepts in one eth_getLogs request. Scanning
+// loops use it as their block range.
+func (n *network) LogsWindow() uint64 {
+	return n.logsWindow.Size()
+}
+
+// GetPeggyEvents fetches all claimable Peggy events within the block range with a single eth_getLogs request
+// (or a few, if the provider limits the block range). The events are ordered by event nonce.
+func (n *network) GetPeggyEvents(ctx context.Context, startBlock, endBlock uint64) ([]PeggyEvent, error) {
+	peggyFilterer, err := peggyevents.NewPeggyFilterer(n.Address(), n.Provider())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
+	}
+
+	logs, err := n.logsWindow.filterLogs(ctx, n.Provider().FilterLogs, peggyEventsQuery(n.Address()), startBlock, endBlock)
+	if err != nil {
+		return nil, wrapLogsErr(err, "Peggy", startBlock, endBlock)
+	}
+
+	return decodePeggyEvents(peggyFilterer, logs)
+}
+
+// decodePeggyEvents decodes the logs and orders them by event nonce. Logs removed by a reorg are skipped.
+func decodePeggyEvents(f *peggyevents.PeggyFilterer, logs []gethtypes.Log) ([]PeggyEvent, error) {
+	events := make([]PeggyEvent, 0, len(logs))
+	for _, l := range logs {
+		if l.Removed {
+			continue
+		}
+
+		ev, err := decodePeggyEvent(f, l)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode Peggy event (tx %s, log %d)", l.TxHash.Hex(), l.Index)
+		}
+
+		events = append(events, ev)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Nonce < events[j].Nonce
+	})
+
+	return events, nil
+}
+
+func peggyEventsQuery(peggyAddr gethcommon.Address) ethereum.FilterQuery {
+	topics := []gethcommon.Hash{
+		peggyABI.Events[sendToCosmosEvent].ID,
+		peggyABI.Events[sendToInjectiveEvent].ID,
+		peggyABI.Events[transactionBatchExecutedEvent].ID,
+		peggyABI.Events[erc20DeployedEvent].ID,
+		peggyABI.Events[valsetUpdatedEvent].ID,
+	}
+
+	return ethereum.FilterQuery{
+		Addresses: []gethcommon.Address{peggyAddr},
+		Topics:    [][]gethcommon.Hash{topics},
+	}
+}
+
+func decodePeggyEvent(f *peggyevents.PeggyFilterer, l gethtypes.Log) (PeggyEvent, error) {
+	if len(l.Topics) == 0 {
+		return PeggyEvent{}, errors.New("log has no topics")
+	}
+
+	switch l.Topics[0] {
+	case peggyABI.Events[sendToCosmosEvent].ID:
+		ev, err := f.ParseSendToCosmosEvent(l)
+		if err != nil {
+			return PeggyEvent{}, err
+		}
+
+		return PeggyEvent{Nonce: ev.EventNonce.Uint64(), Event: ev}, nil
+	case peggyABI.Events[sendToInjectiveEvent].ID:
+		ev, err := f.ParseSendToInjectiveEvent(l)
+		if err != nil {
+			return PeggyEvent{}, err
+		}
+
+		return PeggyEvent{Nonce: ev.EventNonce.Uint64(), Event: ev}, nil
+	case peggyABI.Events[transactionBatchExecutedEvent].ID:
+		ev, err := f.ParseTransactionBatchExecutedEvent(l)
+		if err != nil {
+			return PeggyEvent{}, err
+		}
+
+		return PeggyEvent{Nonce: ev.EventNonce.Uint64(), Event: ev}, nil
+	case peggyABI.Events[erc20DeployedEvent].ID:
+		ev, err := f.ParseERC20DeployedEvent(l)
+		if err != nil {
+			return PeggyEvent{}, err
+		}
+
+		return PeggyEvent{Nonce: ev.EventNonce.Uint64(), Event: ev}, nil
+	case peggyABI.Events[valsetUpdatedEvent].ID:
+		ev, err := f.ParseValsetUpdatedEvent(l)
+		if err != nil {
+			return PeggyEvent{}, err
+		}
+
+		return PeggyEvent{Nonce: ev.EventNonce.Uint64(), Event: ev}, nil
+	default:
+		return PeggyEvent{}, errors.Errorf("unexpected event topic %s", l.Topics[0].Hex())
+	}
+}
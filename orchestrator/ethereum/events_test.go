@@ -0,0 +1,95 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
+)
+
+func TestDecodePeggyEvents(t *testing.T) {
+	t.Parallel()
+
+	peggyAddr := gethcommon.HexToAddress("0x5048019d259217e6b7BC8e1E6aEfa9976B1ADFfe")
+	tokenAddr := gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	filterer, err := peggyevents.NewPeggyFilterer(peggyAddr, nil)
+	assert.NoError(t, err)
+
+	withdrawalLog := func(eventNonce, batchNonce int64) gethtypes.Log {
+		data, err := peggyABI.Events[transactionBatchExecutedEvent].Inputs.NonIndexed().Pack(big.NewInt(eventNonce))
+		assert.NoError(t, err)
+
+		return gethtypes.Log{
+			Address: peggyAddr,
+			Topics: []gethcommon.Hash{
+				peggyABI.Events[transactionBatchExecutedEvent].ID,
+				gethcommon.BigToHash(big.NewInt(batchNonce)),
+				gethcommon.BytesToHash(tokenAddr.Bytes()),
+			},
+			Data: data,
+		}
+	}
+
+	erc20DeployedLog := func(eventNonce int64) gethtypes.Log {
+		data, err := peggyABI.Events[erc20DeployedEvent].Inputs.NonIndexed().Pack("inj", "Injective", "INJ", uint8(18), big.NewInt(eventNonce))
+		assert.NoError(t, err)
+
+		return gethtypes.Log{
+			Address: peggyAddr,
+			Topics: []gethcommon.Hash{
+				peggyABI.Events[erc20DeployedEvent].ID,
+				gethcommon.BytesToHash(tokenAddr.Bytes()),
+			},
+			Data: data,
+		}
+	}
+
+	removedLog := withdrawalLog(9, 1)
+	removedLog.Removed = true
+
+	events, err := decodePeggyEvents(filterer, []gethtypes.Log{
+		withdrawalLog(12, 3),
+		removedLog,
+		erc20DeployedLog(10),
+		withdrawalLog(11, 2),
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, []uint64{10, 11, 12}, []uint64{events[0].Nonce, events[1].Nonce, events[2].Nonce})
+
+		erc20, ok := events[0].Event.(*peggyevents.PeggyERC20DeployedEvent)
+		if assert.True(t, ok) {
+			assert.Equal(t, "INJ", erc20.Symbol)
+			assert.Equal(t, tokenAddr, erc20.TokenContract)
+		}
+
+		withdrawal, ok := events[1].Event.(*peggyevents.PeggyTransactionBatchExecutedEvent)
+		if assert.True(t, ok) {
+			assert.Equal(t, int64(2), withdrawal.BatchNonce.Int64())
+			assert.Equal(t, tokenAddr, withdrawal.Token)
+		}
+	}
+
+	_, err = decodePeggyEvents(filterer, []gethtypes.Log{{Topics: []gethcommon.Hash{gethcommon.HexToHash("0x01")}}})
+	assert.Error(t, err)
+}
+
+func TestPeggyEventsQuery(t *testing.T) {
+	t.Parallel()
+
+	peggyAddr := gethcommon.HexToAddress("0x5048019d259217e6b7BC8e1E6aEfa9976B1ADFfe")
+	q := peggyEventsQuery(peggyAddr)
+
+	assert.Equal(t, []gethcommon.Address{peggyAddr}, q.Addresses)
+
+	// all event topics are ORed in the first topic position
+	if assert.Len(t, q.Topics, 1) {
+		assert.Len(t, q.Topics[0], 5)
+	}
+}
@@ -0,0 +1,193 @@
+package keystore
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// clefSigner signs with the external API of Clef (account_signData and account_signTransaction), served over
+// HTTP or IPC.
+type clefSigner struct {
+	client  *rpc.Client
+	timeout time.Duration
+}
+
+// NewClefSigner returns a RemoteSigner using the Clef external API at the given HTTP URL or IPC path.
+func NewClefSigner(endpoint string, timeout time.Duration) (RemoteSigner, error) {
+	if timeout == 0 {
+		timeout = defaultRemoteSignerTimeout
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), timeout)
+	defer cancelFn()
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to Clef at %s", endpoint)
+	}
+
+	return &clefSigner{
+		client:  client,
+		timeout: timeout,
+	}, nil
+}
+
+// clefTxArgs are the transaction fields of account_signTransaction
+type clefTxArgs struct {
+	From                 common.Address    `json:"from"`
+	To                   *common.Address   `json:"to"`
+	Gas                  hexutil.Uint64    `json:"gas"`
+	GasPrice             *hexutil.Big      `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big       `json:"value"`
+	Nonce                hexutil.Uint64    `json:"nonce"`
+	Data                 hexutil.Bytes     `json:"data"`
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big      `json:"chainId"`
+}
+
+type clefSignTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+func (s *clefSigner) Accounts() ([]common.Address, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), s.timeout)
+	defer cancelFn()
+
+	var addrs []common.Address
+	if err := s.client.CallContext(ctx, &addrs, "account_list"); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clef accounts")
+	}
+
+	return addrs, nil
+}
+
+func (s *clefSigner) SignerFn(chainID uint64, account common.Address) (SignerFn, error) {
+	if err := s.checkAccount(account); err != nil {
+		return nil, err
+	}
+
+	signer := types.LatestSignerForChainID(new(big.Int).SetUint64(chainID))
+
+	signerFn := func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != account {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		args := clefTxArgs{
+			From:    from,
+			To:      tx.To(),
+			Gas:     hexutil.Uint64(tx.Gas()),
+			Value:   hexutil.Big(*tx.Value()),
+			Nonce:   hexutil.Uint64(tx.Nonce()),
+			Data:    tx.Data(),
+			ChainID: (*hexutil.Big)(new(big.Int).SetUint64(chainID)),
+		}
+
+		switch tx.Type() {
+		case types.LegacyTxType:
+			args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		case types.AccessListTxType:
+			accessList := tx.AccessList()
+			args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+			args.AccessList = &accessList
+		case types.DynamicFeeTxType:
+			accessList := tx.AccessList()
+			args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+			args.AccessList = &accessList
+		default:
+			return nil, errors.Errorf("unsupported transaction type %d", tx.Type())
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), s.timeout)
+		defer cancelFn()
+
+		var result clefSignTxResult
+		if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+			return nil, errors.Wrap(err, "failed to sign transaction with Clef")
+		}
+
+		signedTx := new(types.Transaction)
+		if err := signedTx.UnmarshalBinary(result.Raw); err != nil {
+			return nil, errors.Wrap(err, "failed to decode transaction signed by Clef")
+		}
+
+		// Clef lets the operator edit the transaction before signing it
+		if signer.Hash(signedTx) != signer.Hash(tx) {
+			return nil, errors.New("transaction signed by Clef differs from the requested one")
+		}
+
+		sender, err := types.Sender(signer, signedTx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to recover sender of transaction signed by Clef")
+		}
+
+		if sender != account {
+			return nil, errors.Errorf("remote signer signed with %s instead of %s", sender.Hex(), account.Hex())
+		}
+
+		return signedTx, nil
+	}
+
+	return signerFn, nil
+}
+
+func (s *clefSigner) PersonalSignFn(account common.Address) (PersonalSignFn, error) {
+	if err := s.checkAccount(account); err != nil {
+		return nil, err
+	}
+
+	signFn := func(from common.Address, data []byte) ([]byte, error) {
+		if from != account {
+			return nil, errors.New("from address mismatch")
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), s.timeout)
+		defer cancelFn()
+
+		// text/plain data is signed with the EIP-191 prefix
+		var sig hexutil.Bytes
+		if err := s.client.CallContext(ctx, &sig, "account_signData", accounts.MimetypeTextPlain, from, hexutil.Bytes(data)); err != nil {
+			return nil, errors.Wrap(err, "failed to sign data with Clef")
+		}
+
+		normalized, err := normalizeSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifySignature(account, accounts.TextHash(data), normalized); err != nil {
+			return nil, err
+		}
+
+		return normalized, nil
+	}
+
+	return signFn, nil
+}
+
+func (s *clefSigner) checkAccount(account common.Address) error {
+	addrs, err := s.Accounts()
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if addr == account {
+			return nil
+		}
+	}
+
+	return errors.Errorf("account %s is not available in Clef", account.Hex())
+}
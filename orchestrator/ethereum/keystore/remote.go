@@ -0,0 +1,186 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+)
+
+// RemoteSigner signs with an Ethereum key held by a remote signing service, so that the key material
+// never reaches the orchestrator host.
+type RemoteSigner interface {
+	Accounts() ([]common.Address, error)
+	SignerFn(chainID uint64, account common.Address) (SignerFn, error)
+	PersonalSignFn(account common.Address) (PersonalSignFn, error)
+}
+
+// normalizeSignature returns a copy of the [R || S || V] signature with V in the {0, 1} range used by go-ethereum
+func normalizeSignature(sig []byte) ([]byte, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, errors.Errorf("invalid signature length %d", len(sig))
+	}
+
+	sig = common.CopyBytes(sig)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	if sig[crypto.RecoveryIDOffset] > 1 {
+		return nil, errors.Errorf("invalid signature recovery id %d", sig[crypto.RecoveryIDOffset])
+	}
+
+	return sig, nil
+}
+
+// verifySignature checks that the signature over hash was produced by the account key. A remote signer is
+// not trusted to sign with the expected key.
+func verifySignature(account common.Address, hash, sig []byte) error {
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to recover signer")
+	}
+
+	if signer := crypto.PubkeyToAddress(*pubKey); signer != account {
+		return errors.Errorf("remote signer signed with %s instead of %s", signer.Hex(), account.Hex())
+	}
+
+	return nil
+}
+
+// signerFnFromDigestSigner builds a SignerFn that signs the transaction signing payload with signFn.
+// The payload is hashed with Keccak256 by the remote signer.
+func signerFnFromDigestSigner(chainID uint64, account common.Address, signFn func(payload []byte) ([]byte, error)) SignerFn {
+	signer := types.LatestSignerForChainID(new(big.Int).SetUint64(chainID))
+
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != account {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		payload, err := txSigningPayload(signer, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := signFn(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err = normalizeSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifySignature(account, signer.Hash(tx).Bytes(), sig); err != nil {
+			return nil, err
+		}
+
+		return tx.WithSignature(signer, sig)
+	}
+}
+
+// txSigningPayload returns the bytes whose Keccak256 hash is signed for the transaction
+func txSigningPayload(signer types.Signer, tx *types.Transaction) ([]byte, error) {
+	var (
+		payload []byte
+		err     error
+	)
+
+	chainID := signer.ChainID()
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		payload, err = rlp.EncodeToBytes([]interface{}{
+			tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(),
+			chainID, uint(0), uint(0),
+		})
+	case types.AccessListTxType:
+		payload, err = rlp.EncodeToBytes([]interface{}{
+			chainID, tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), tx.AccessList(),
+		})
+		payload = append([]byte{tx.Type()}, payload...)
+	case types.DynamicFeeTxType:
+		payload, err = rlp.EncodeToBytes([]interface{}{
+			chainID, tx.Nonce(), tx.GasTipCap(), tx.GasFeeCap(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), tx.AccessList(),
+		})
+		payload = append([]byte{tx.Type()}, payload...)
+	default:
+		return nil, errors.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode transaction signing payload")
+	}
+
+	// never send the remote signer anything else than what the chain verifies
+	if common.BytesToHash(crypto.Keccak256(payload)) != signer.Hash(tx) {
+		return nil, errors.New("transaction signing payload does not match the transaction hash")
+	}
+
+	return payload, nil
+}
+
+// DigestSigner signs 32 bytes digests with a key held by a signing backend (see the remotekey package).
+type DigestSigner interface {
+	PublicKey() *ecdsa.PublicKey
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// DigestSignerFn returns a SignerFn signing transactions of the key address with the digest signer.
+func DigestSignerFn(chainID uint64, key DigestSigner) SignerFn {
+	var (
+		account = crypto.PubkeyToAddress(*key.PublicKey())
+		signer  = types.LatestSignerForChainID(new(big.Int).SetUint64(chainID))
+	)
+
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != account {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		hash := signer.Hash(tx)
+
+		sig, err := key.SignDigest(hash.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifySignature(account, hash.Bytes(), sig); err != nil {
+			return nil, err
+		}
+
+		return tx.WithSignature(signer, sig)
+	}
+}
+
+// DigestPersonalSignFn returns a PersonalSignFn signing EIP-191 messages with the digest signer.
+func DigestPersonalSignFn(key DigestSigner) PersonalSignFn {
+	account := crypto.PubkeyToAddress(*key.PublicKey())
+
+	return func(from common.Address, data []byte) ([]byte, error) {
+		if from != account {
+			return nil, errors.New("from address mismatch")
+		}
+
+		hash := accounts.TextHash(data)
+
+		sig, err := key.SignDigest(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifySignature(account, hash, sig); err != nil {
+			return nil, err
+		}
+
+		return sig, nil
+	}
+}
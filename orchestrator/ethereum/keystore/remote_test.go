@@ -0,0 +1,324 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainID = 5
+
+func testTxs() map[string]*types.Transaction {
+	to := common.HexToAddress("0xF955C57f9EA9Dc8781965FEaE0b6A2acE2BAD6f3")
+
+	return map[string]*types.Transaction{
+		"legacy": types.NewTransaction(7, to, big.NewInt(0), 100000, big.NewInt(20e9), []byte{0xde, 0xad}),
+		"dynamic fee": types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(testChainID),
+			Nonce:     8,
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(30e9),
+			Gas:       100000,
+			To:        &to,
+			Value:     big.NewInt(0),
+			Data:      []byte{0xbe, 0xef},
+		}),
+	}
+}
+
+// web3SignerStub serves the eth1 API of Web3Signer, signing with signingKey whatever the requested identifier
+func web3SignerStub(t *testing.T, key, signingKey *ecdsa.PrivateKey) *httptest.Server {
+	identifier := hexutil.Encode(crypto.FromECDSAPub(&key.PublicKey)[1:])
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/eth1/publicKeys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode([]string{identifier})
+	})
+
+	mux.HandleFunc("/api/v1/eth1/sign/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/api/v1/eth1/sign/") != identifier {
+			http.Error(w, "Public Key not found", http.StatusNotFound)
+			return
+		}
+
+		var req struct {
+			Data hexutil.Bytes `json:"data"`
+		}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		sig, err := crypto.Sign(crypto.Keccak256(req.Data), signingKey)
+		require.NoError(t, err)
+
+		// Web3Signer returns V in the {27, 28} range
+		sig[crypto.RecoveryIDOffset] += 27
+		_, _ = w.Write([]byte(hexutil.Encode(sig)))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestWeb3Signer(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(big.NewInt(testChainID))
+
+	t.Run("signs with the account key", func(t *testing.T) {
+		srv := web3SignerStub(t, key, key)
+		defer srv.Close()
+
+		remote, err := NewWeb3Signer(srv.URL, 0)
+		require.NoError(t, err)
+
+		addrs, err := remote.Accounts()
+		require.NoError(t, err)
+		assert.Equal(t, []common.Address{account}, addrs)
+
+		signerFn, err := remote.SignerFn(testChainID, account)
+		require.NoError(t, err)
+
+		for name, tx := range testTxs() {
+			signedTx, err := signerFn(account, tx)
+			require.NoError(t, err, name)
+
+			sender, err := types.Sender(signer, signedTx)
+			require.NoError(t, err, name)
+			assert.Equal(t, account, sender, name)
+			assert.Equal(t, signer.Hash(tx), signer.Hash(signedTx), name)
+		}
+
+		personalSignFn, err := remote.PersonalSignFn(account)
+		require.NoError(t, err)
+
+		data := crypto.Keccak256([]byte("checkpoint"))
+		sig, err := personalSignFn(account, data)
+		require.NoError(t, err)
+
+		expected, err := crypto.Sign(accounts.TextHash(data), key)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sig)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		srv := web3SignerStub(t, key, key)
+		defer srv.Close()
+
+		remote, err := NewWeb3Signer(srv.URL, 0)
+		require.NoError(t, err)
+
+		_, err = remote.PersonalSignFn(crypto.PubkeyToAddress(otherKey.PublicKey))
+		assert.ErrorContains(t, err, "not available in Web3Signer")
+	})
+
+	t.Run("signature of another key is refused", func(t *testing.T) {
+		srv := web3SignerStub(t, key, otherKey)
+		defer srv.Close()
+
+		remote, err := NewWeb3Signer(srv.URL, 0)
+		require.NoError(t, err)
+
+		personalSignFn, err := remote.PersonalSignFn(account)
+		require.NoError(t, err)
+
+		_, err = personalSignFn(account, []byte("checkpoint"))
+		assert.ErrorContains(t, err, "remote signer signed with")
+
+		signerFn, err := remote.SignerFn(testChainID, account)
+		require.NoError(t, err)
+
+		_, err = signerFn(account, testTxs()["legacy"])
+		assert.ErrorContains(t, err, "remote signer signed with")
+	})
+}
+
+// clefStub serves the account namespace of the Clef external API
+type clefStub struct {
+	key    *ecdsa.PrivateKey
+	signer types.Signer
+
+	// modifyGas simulates an operator editing the transaction in the Clef UI
+	modifyGas uint64
+}
+
+func (s *clefStub) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+func (s *clefStub) SignData(contentType string, _ common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != accounts.MimetypeTextPlain {
+		return nil, rpc.ErrNoResult
+	}
+
+	sig, err := crypto.Sign(accounts.TextHash(data), s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return sig, nil
+}
+
+func (s *clefStub) SignTransaction(args clefTxArgs) (*clefSignTxResult, error) {
+	gas := uint64(args.Gas) + s.modifyGas
+
+	var tx *types.Transaction
+	if args.MaxFeePerGas != nil {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       gas,
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		})
+	} else {
+		tx = types.NewTransaction(uint64(args.Nonce), *args.To, args.Value.ToInt(), gas, args.GasPrice.ToInt(), args.Data)
+	}
+
+	signedTx, err := types.SignTx(tx, s.signer, s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &clefSignTxResult{Raw: raw}, nil
+}
+
+func clefServer(t *testing.T, stub *clefStub) *httptest.Server {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", stub))
+
+	return httptest.NewServer(server)
+}
+
+func TestClefSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(big.NewInt(testChainID))
+
+	t.Run("signs with the account key", func(t *testing.T) {
+		srv := clefServer(t, &clefStub{key: key, signer: signer})
+		defer srv.Close()
+
+		remote, err := NewClefSigner(srv.URL, 0)
+		require.NoError(t, err)
+
+		signerFn, err := remote.SignerFn(testChainID, account)
+		require.NoError(t, err)
+
+		for name, tx := range testTxs() {
+			signedTx, err := signerFn(account, tx)
+			require.NoError(t, err, name)
+
+			sender, err := types.Sender(signer, signedTx)
+			require.NoError(t, err, name)
+			assert.Equal(t, account, sender, name)
+			assert.Equal(t, signer.Hash(tx), signer.Hash(signedTx), name)
+		}
+
+		personalSignFn, err := remote.PersonalSignFn(account)
+		require.NoError(t, err)
+
+		data := crypto.Keccak256([]byte("checkpoint"))
+		sig, err := personalSignFn(account, data)
+		require.NoError(t, err)
+
+		expected, err := crypto.Sign(accounts.TextHash(data), key)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sig)
+	})
+
+	t.Run("modified transaction is refused", func(t *testing.T) {
+		srv := clefServer(t, &clefStub{key: key, signer: signer, modifyGas: 1})
+		defer srv.Close()
+
+		remote, err := NewClefSigner(srv.URL, 0)
+		require.NoError(t, err)
+
+		signerFn, err := remote.SignerFn(testChainID, account)
+		require.NoError(t, err)
+
+		_, err = signerFn(account, testTxs()["legacy"])
+		assert.ErrorContains(t, err, "differs from the requested one")
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		srv := clefServer(t, &clefStub{key: key, signer: signer})
+		defer srv.Close()
+
+		remote, err := NewClefSigner(srv.URL, 0)
+		require.NoError(t, err)
+
+		_, err = remote.SignerFn(testChainID, common.HexToAddress("0x01"))
+		assert.ErrorContains(t, err, "not available in Clef")
+	})
+}
+
+// localKey stands for a key held by a signing backend
+type localKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k localKey) PublicKey() *ecdsa.PublicKey {
+	return &k.key.PublicKey
+}
+
+func (k localKey) SignDigest(digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, k.key)
+}
+
+func TestDigestSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(big.NewInt(testChainID))
+	signerFn := DigestSignerFn(testChainID, localKey{key: key})
+
+	for name, tx := range testTxs() {
+		signedTx, err := signerFn(account, tx)
+		require.NoError(t, err, name)
+
+		sender, err := types.Sender(signer, signedTx)
+		require.NoError(t, err, name)
+		assert.Equal(t, account, sender, name)
+	}
+
+	_, err = signerFn(common.HexToAddress("0x01"), testTxs()["legacy"])
+	assert.Error(t, err)
+
+	data := crypto.Keccak256([]byte("checkpoint"))
+	sig, err := DigestPersonalSignFn(localKey{key: key})(account, data)
+	require.NoError(t, err)
+
+	expected, err := crypto.Sign(accounts.TextHash(data), key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, sig)
+}
@@ -0,0 +1,223 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+const defaultRemoteSignerTimeout = 10 * time.Second
+
+// web3Signer signs with the eth1 HTTP API of Web3Signer. The API signs the Keccak256 hash of the given data.
+type web3Signer struct {
+	baseURL *url.URL
+	client  *http.Client
+}
+
+// NewWeb3Signer returns a RemoteSigner using the Web3Signer eth1 API at the given URL.
+func NewWeb3Signer(endpoint string, timeout time.Duration) (RemoteSigner, error) {
+	baseURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Web3Signer URL %s", endpoint)
+	}
+
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, errors.Errorf("unsupported Web3Signer URL scheme %q", baseURL.Scheme)
+	}
+
+	if timeout == 0 {
+		timeout = defaultRemoteSignerTimeout
+	}
+
+	return &web3Signer{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *web3Signer) Accounts() ([]common.Address, error) {
+	keys, err := s.publicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]common.Address, 0, len(keys))
+	for addr := range keys {
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+func (s *web3Signer) SignerFn(chainID uint64, account common.Address) (SignerFn, error) {
+	identifier, err := s.identifier(account)
+	if err != nil {
+		return nil, err
+	}
+
+	return signerFnFromDigestSigner(chainID, account, func(payload []byte) ([]byte, error) {
+		return s.sign(identifier, payload)
+	}), nil
+}
+
+func (s *web3Signer) PersonalSignFn(account common.Address) (PersonalSignFn, error) {
+	identifier, err := s.identifier(account)
+	if err != nil {
+		return nil, err
+	}
+
+	signFn := func(from common.Address, data []byte) ([]byte, error) {
+		if from != account {
+			return nil, errors.New("from address mismatch")
+		}
+
+		// the API hashes the data, so it is sent with the EIP-191 prefix already applied
+		msg := []byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data))
+
+		sig, err := s.sign(identifier, msg)
+		if err != nil {
+			return nil, err
+		}
+
+		if sig, err = normalizeSignature(sig); err != nil {
+			return nil, err
+		}
+
+		if err := verifySignature(account, accounts.TextHash(data), sig); err != nil {
+			return nil, err
+		}
+
+		return sig, nil
+	}
+
+	return signFn, nil
+}
+
+// identifier returns the public key identifying the account in the API
+func (s *web3Signer) identifier(account common.Address) (string, error) {
+	keys, err := s.publicKeys()
+	if err != nil {
+		return "", err
+	}
+
+	identifier, ok := keys[account]
+	if !ok {
+		return "", errors.Errorf("account %s is not available in Web3Signer", account.Hex())
+	}
+
+	return identifier, nil
+}
+
+// publicKeys returns the public keys loaded in Web3Signer, keyed by account address
+func (s *web3Signer) publicKeys() (map[common.Address]string, error) {
+	resp, err := s.client.Get(s.baseURL.JoinPath("api/v1/eth1/publicKeys").String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Web3Signer public keys")
+	}
+
+	defer resp.Body.Close()
+
+	body, err := readWeb3SignerResponse(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Web3Signer public keys")
+	}
+
+	var identifiers []string
+	if err := json.Unmarshal(body, &identifiers); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Web3Signer public keys")
+	}
+
+	keys := make(map[common.Address]string, len(identifiers))
+	for _, identifier := range identifiers {
+		addr, err := publicKeyAddress(identifier)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Web3Signer public key %s", identifier)
+		}
+
+		keys[addr] = identifier
+	}
+
+	return keys, nil
+}
+
+func (s *web3Signer) sign(identifier string, data []byte) ([]byte, error) {
+	reqBody, err := json.Marshal(map[string]string{"data": hexutil.Encode(data)})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Post(
+		s.baseURL.JoinPath("api/v1/eth1/sign", identifier).String(),
+		"application/json",
+		bytes.NewReader(reqBody),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign with Web3Signer")
+	}
+
+	defer resp.Body.Close()
+
+	body, err := readWeb3SignerResponse(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign with Web3Signer")
+	}
+
+	sig, err := hexutil.Decode(strings.TrimSpace(string(body)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode Web3Signer signature")
+	}
+
+	return sig, nil
+}
+
+func readWeb3SignerResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// publicKeyAddress returns the address of a hex encoded secp256k1 public key, either compressed, uncompressed
+// or uncompressed without the 0x04 prefix (as listed by Web3Signer)
+func publicKeyAddress(hexKey string) (common.Address, error) {
+	key, err := hexutil.Decode(hexKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	switch len(key) {
+	case 33:
+		pubKey, err := crypto.DecompressPubkey(key)
+		if err != nil {
+			return common.Address{}, err
+		}
+
+		return crypto.PubkeyToAddress(*pubKey), nil
+	case 64:
+		key = append([]byte{0x04}, key...)
+	}
+
+	pubKey, err := crypto.UnmarshalPubkey(key)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
@@ -0,0 +1,191 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+const (
+	// Initial number of blocks queried with one eth_getLogs request
+	defaultLogsWindow uint64 = 2000
+	// Upper bound for the window when growing it back after successful queries
+	maxLogsWindow uint64 = 10000
+	// Number of consecutive successful queries after which the window is doubled
+	logsWindowGrowAfter = 5
+)
+
+// Substrings of the errors returned by nodes and RPC providers when the block range
+// of an eth_getLogs request is too wide or the response contains too many logs. They are
+// specific to these errors so that unrelated RPC errors do not shrink the window.
+var logsRangeErrors = []string{
+	"query returned more than",               // Infura, Geth: query returned more than 10000 results
+	"log response size exceeded",             // Alchemy
+	"block range is too wide",                // Chainstack, Erigon
+	"block range too large",                  // various
+	"exceed maximum block range",             // Besu, Nethermind
+	"exceeds max block range",                // various
+	"range limit exceeded",                   // various
+	"eth_getlogs is limited to a",            // QuickNode: eth_getLogs is limited to a 10,000 range
+	"ranges over",                            // Ankr: ranges over 3000 blocks are not supported
+	"response size should not greater than",  // BSC
+	"response is too big",                    // Cloudflare
+	"too many logs",                          // various
+	"logs matched by query exceeds limit of", // Moralis
+}
+
+// Alchemy suggests a working range, e.g. "this block range should work: [0x1, 0x7d0]"
+var suggestedRangeRe = regexp.MustCompile(`\[(0x[0-9a-fA-F]+),\s*(0x[0-9a-fA-F]+)\]`)
+
+func isLogsRangeErr(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range logsRangeErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suggestedRange returns the size of the block range suggested in the provider error, if any.
+func suggestedRange(err error) (uint64, bool) {
+	m := suggestedRangeRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+
+	from, errFrom := strconv.ParseUint(strings.TrimPrefix(m[1], "0x"), 16, 64)
+	to, errTo := strconv.ParseUint(strings.TrimPrefix(m[2], "0x"), 16, 64)
+	if errFrom != nil || errTo != nil || to < from {
+		return 0, false
+	}
+
+	return to - from + 1, true
+}
+
+type filterLogsFn func(ctx context.Context, q ethereum.FilterQuery) ([]gethtypes.Log, error)
+
+// logsWindow splits eth_getLogs queries into block ranges accepted by the provider. The window shrinks
+// when the provider rejects a range and grows back after a number of successful queries.
+type logsWindow struct {
+	mux       sync.Mutex
+	size      uint64
+	successes int
+
+	svcTags metrics.Tags
+}
+
+func newLogsWindow() *logsWindow {
+	w := &logsWindow{
+		size:    defaultLogsWindow,
+		svcTags: metrics.Tags{"svc": "eth_logs"},
+	}
+
+	w.report()
+
+	return w
+}
+
+// Size returns the current number of blocks queried with one request.
+func (w *logsWindow) Size() uint64 {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	return w.size
+}
+
+// filterLogs fetches the logs matching q within [startBlock, endBlock], issuing as many requests as needed.
+func (w *logsWindow) filterLogs(ctx context.Context, filterFn filterLogsFn, q ethereum.FilterQuery, startBlock, endBlock uint64) ([]gethtypes.Log, error) {
+	var logs []gethtypes.Log
+	for from := startBlock; from <= endBlock; {
+		to := endBlock
+		if size := w.Size(); to-from+1 > size {
+			to = from + size - 1
+		}
+
+		q.FromBlock = new(big.Int).SetUint64(from)
+		q.ToBlock = new(big.Int).SetUint64(to)
+
+		result, err := filterFn(ctx, q)
+		if err != nil {
+			if !isLogsRangeErr(err) || from == to {
+				return nil, err
+			}
+
+			w.shrink(to-from+1, err)
+			continue
+		}
+
+		w.succeed()
+		logs = append(logs, result...)
+
+		if to == endBlock {
+			break
+		}
+
+		from = to + 1
+	}
+
+	return logs, nil
+}
+
+func (w *logsWindow) shrink(rejected uint64, err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	size := rejected / 2
+	if suggested, ok := suggestedRange(err); ok && suggested < rejected {
+		size = suggested
+	}
+
+	if size == 0 {
+		size = 1
+	}
+
+	log.WithFields(log.Fields{"rejected": rejected, "window": size}).WithError(err).Debugln("provider rejected eth_getLogs block range, shrinking window")
+
+	w.size = size
+	w.successes = 0
+	w.report()
+}
+
+func (w *logsWindow) succeed() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	w.successes++
+	if w.successes < logsWindowGrowAfter || w.size >= maxLogsWindow {
+		return
+	}
+
+	w.size *= 2
+	if w.size > maxLogsWindow {
+		w.size = maxLogsWindow
+	}
+
+	w.successes = 0
+	w.report()
+}
+
+func (w *logsWindow) report() {
+	size := w.size
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Gauge("eth_logs.window", float64(size), tagSpec, 1)
+	}, w.svcTags)
+}
+
+// wrapLogsErr keeps the error message consistent for all scans
+func wrapLogsErr(err error, event string, startBlock, endBlock uint64) error {
+	return errors.Wrapf(err, "failed to scan past %s events from Ethereum (%d - %d)", event, startBlock, endBlock)
+}
@@ -0,0 +1,125 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// rangeLimitedFilter returns one log per block and rejects ranges wider than limit
+func rangeLimitedFilter(limit uint64, rangeErr error, queried *[][2]uint64) filterLogsFn {
+	return func(_ context.Context, q ethereum.FilterQuery) ([]gethtypes.Log, error) {
+		from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+		*queried = append(*queried, [2]uint64{from, to})
+
+		if to-from+1 > limit {
+			return nil, rangeErr
+		}
+
+		var logs []gethtypes.Log
+		for b := from; b <= to; b++ {
+			logs = append(logs, gethtypes.Log{BlockNumber: b})
+		}
+
+		return logs, nil
+	}
+}
+
+func TestLogsWindow(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name         string
+		limit        uint64
+		rangeErr     error
+		expectedSize uint64
+	}{
+		{
+			name:         "too many results",
+			limit:        500,
+			rangeErr:     errors.New("query returned more than 10000 results"),
+			expectedSize: 500,
+		},
+
+		{
+			name:         "block range limit",
+			limit:        300,
+			rangeErr:     errors.New("exceed maximum block range: 300"),
+			expectedSize: 250,
+		},
+
+		{
+			name:         "suggested range",
+			limit:        800,
+			rangeErr:     errors.New("Log response size exceeded. Based on your parameters, this block range should work: [0x64, 0x383]"),
+			expectedSize: 800,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var queried [][2]uint64
+			w := newLogsWindow()
+
+			logs, err := w.filterLogs(context.Background(), rangeLimitedFilter(tt.limit, tt.rangeErr, &queried), ethereum.FilterQuery{}, 100, 2099)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSize, w.Size())
+
+			// every block is scanned exactly once, in order
+			if assert.Len(t, logs, 2000) {
+				for i, l := range logs {
+					assert.Equal(t, uint64(100+i), l.BlockNumber)
+				}
+			}
+		})
+	}
+}
+
+func TestLogsWindow_Grow(t *testing.T) {
+	t.Parallel()
+
+	var queried [][2]uint64
+	w := newLogsWindow()
+	w.size = 100
+
+	filterFn := rangeLimitedFilter(maxLogsWindow, nil, &queried)
+	for i := 0; i < logsWindowGrowAfter; i++ {
+		_, err := w.filterLogs(context.Background(), filterFn, ethereum.FilterQuery{}, 0, 99)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, uint64(200), w.Size())
+}
+
+func TestLogsWindow_Errors(t *testing.T) {
+	t.Parallel()
+
+	var queried [][2]uint64
+	w := newLogsWindow()
+
+	// unrelated errors are returned as is and do not shrink the window
+	_, err := w.filterLogs(context.Background(), rangeLimitedFilter(0, errors.New("connection refused"), &queried), ethereum.FilterQuery{}, 0, 99)
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, defaultLogsWindow, w.Size())
+	assert.Len(t, queried, 1)
+
+	// errors only resembling range errors do not shrink the window either
+	queried = nil
+	_, err = w.filterLogs(context.Background(), rangeLimitedFilter(0, errors.New("max results per account exceeded"), &queried), ethereum.FilterQuery{}, 0, 99)
+	assert.Error(t, err)
+	assert.Equal(t, defaultLogsWindow, w.Size())
+	assert.Len(t, queried, 1)
+
+	// a single block that cannot be queried is an error
+	queried = nil
+	_, err = w.filterLogs(context.Background(), rangeLimitedFilter(0, errors.New("too many logs"), &queried), ethereum.FilterQuery{}, 0, 3)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(1), w.Size())
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/xlab/suplog"
 
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/committer"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/provider"
@@ -27,19 +28,28 @@ type NetworkConfig struct {
 	MaxGasPrice           string
 	PendingTxWaitDuration string
 	EthNodeAlchemyWS      string
+	EthNodeWS             string
+	EthVerifyRPCs         string
+	EthVerifyQuorum       int
+	EthVerifyReceipts     bool
+
+	// AuditLog records the transactions signed with the Ethereum key. Nil disables it.
+	AuditLog *audit.Log
 }
 
 // Network is the orchestrator's reference endpoint to the Ethereum network
 type Network interface {
 	GetHeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	GetBlockHash(ctx context.Context, number *big.Int) (gethcommon.Hash, error)
 	GetPeggyID(ctx context.Context) (gethcommon.Hash, error)
 
-	GetSendToCosmosEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToCosmosEvent, error)
-	GetSendToInjectiveEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error)
-	GetPeggyERC20DeployedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error)
+	LogsWindow() uint64
+	GetPeggyEvents(ctx context.Context, startBlock, endBlock uint64) ([]PeggyEvent, error)
 	GetValsetUpdatedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error)
-	GetTransactionBatchExecutedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error)
+	SubscribePeggyEvents(ctx context.Context, heads chan<- *gethtypes.Header, logs chan<- gethtypes.Log) (ethereum.Subscription, error)
+	VerifyPeggyEvents(ctx context.Context, logs []gethtypes.Log) (int, error)
 
+	GetLastEventNonce(ctx context.Context, blockNumber *big.Int) (*big.Int, error)
 	GetValsetNonce(ctx context.Context) (*big.Int, error)
 	SendEthValsetUpdate(ctx context.Context,
 		oldValset *peggytypes.Valset,
@@ -53,25 +63,38 @@ type Network interface {
 		batch *peggytypes.OutgoingTxBatch,
 		confirms []*peggytypes.MsgConfirmBatch,
 	) (*gethcommon.Hash, error)
+	EstimateTransactionBatchGas(ctx context.Context,
+		currentValset *peggytypes.Valset,
+		batch *peggytypes.OutgoingTxBatch,
+		confirms []*peggytypes.MsgConfirmBatch,
+	) (uint64, error)
 
 	TokenDecimals(ctx context.Context, tokenContract gethcommon.Address) (uint8, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 }
 
 type network struct {
 	peggy.PeggyContract
 
-	FromAddr gethcommon.Address
+	FromAddr   gethcommon.Address
+	logsWindow *logsWindow
+	wsURL      string
+	quorum     *eventQuorum
+	prover     *receiptsProver
 }
 
+// NewNetwork connects to the Ethereum network. Background work, such as the health checks of the RPC endpoints,
+// runs until ctx is done.
 func NewNetwork(
+	ctx context.Context,
 	peggyContractAddr,
 	fromAddr gethcommon.Address,
 	signerFn bind.SignerFn,
 	cfg NetworkConfig,
 ) (Network, error) {
-	evmRPC, err := rpc.Dial(cfg.EthNodeRPC)
+	evmProvider, err := newEVMProvider(ctx, cfg.EthNodeRPC)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to connect to ethereum RPC: %s", cfg.EthNodeRPC)
+		return nil, err
 	}
 
 	ethCommitter, err := committer.NewEthCommitter(
@@ -79,7 +102,8 @@ func NewNetwork(
 		cfg.GasPriceAdjustment,
 		cfg.MaxGasPrice,
 		signerFn,
-		provider.NewEVMProvider(evmRPC),
+		evmProvider,
+		committer.OptionAuditLog(cfg.AuditLog),
 	)
 	if err != nil {
 		return nil, err
@@ -103,14 +127,66 @@ func NewNetwork(
 		go peggyContract.SubscribeToPendingTxs(cfg.EthNodeAlchemyWS)
 	}
 
+	verifiers, err := dialVerifiers(peggyContractAddr, splitEndpoints(cfg.EthVerifyRPCs))
+	if err != nil {
+		return nil, err
+	}
+
+	quorum, err := newEventQuorum(peggyContractAddr, verifiers, cfg.EthVerifyQuorum)
+	if err != nil {
+		return nil, err
+	}
+
+	var prover *receiptsProver
+	if cfg.EthVerifyReceipts {
+		if prover, err = newReceiptsProver(evmProvider, verifiers, cfg.EthVerifyQuorum); err != nil {
+			return nil, err
+		}
+	}
+
 	n := &network{
 		PeggyContract: peggyContract,
 		FromAddr:      fromAddr,
+		logsWindow:    newLogsWindow(),
+		wsURL:         cfg.EthNodeWS,
+		quorum:        quorum,
+		prover:        prover,
 	}
 
 	return n, nil
 }
 
+// newEVMProvider connects to the Ethereum RPC endpoint. If several comma-separated endpoints are given,
+// requests fail over between them based on their health, which is checked until ctx is done.
+func newEVMProvider(ctx context.Context, endpoints string) (provider.EVMProviderWithRet, error) {
+	urls := splitEndpoints(endpoints)
+	if len(urls) > 1 {
+		return provider.NewMultiEVMProvider(ctx, urls, provider.DefaultHealthConfig)
+	}
+
+	if len(urls) == 0 {
+		return nil, errors.New("no Ethereum RPC endpoint provided")
+	}
+
+	evmRPC, err := rpc.Dial(urls[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to ethereum RPC: %s", urls[0])
+	}
+
+	return provider.NewEVMProvider(evmRPC), nil
+}
+
+func splitEndpoints(endpoints string) []string {
+	var urls []string
+	for _, u := range strings.Split(endpoints, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	return urls
+}
+
 func (n *network) TokenDecimals(ctx context.Context, tokenContract gethcommon.Address) (uint8, error) {
 	msg := ethereum.CallMsg{
 		To:   &tokenContract,
@@ -129,14 +205,26 @@ func (n *network) TokenDecimals(ctx context.Context, tokenContract gethcommon.Ad
 	return uint8(big.NewInt(0).SetBytes(res).Uint64()), nil
 }
 
+func (n *network) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return n.Provider().SuggestGasPrice(ctx)
+}
+
 func (n *network) GetHeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
 	return n.Provider().HeaderByNumber(ctx, number)
 }
 
+func (n *network) GetBlockHash(ctx context.Context, number *big.Int) (gethcommon.Hash, error) {
+	return n.Provider().BlockHashByNumber(ctx, number)
+}
+
 func (n *network) GetPeggyID(ctx context.Context) (gethcommon.Hash, error) {
 	return n.PeggyContract.GetPeggyID(ctx, n.FromAddr)
 }
 
+func (n *network) GetLastEventNonce(ctx context.Context, blockNumber *big.Int) (*big.Int, error) {
+	return n.PeggyContract.GetLastEventNonce(ctx, blockNumber, n.FromAddr)
+}
+
 func (n *network) GetValsetNonce(ctx context.Context) (*big.Int, error) {
 	return n.PeggyContract.GetValsetNonce(ctx, n.FromAddr)
 }
@@ -145,156 +233,35 @@ func (n *network) GetTxBatchNonce(ctx context.Context, erc20ContractAddress geth
 	return n.PeggyContract.GetTxBatchNonce(ctx, erc20ContractAddress, n.FromAddr)
 }
 
-func (n *network) GetSendToCosmosEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-	peggyFilterer, err := peggyevents.NewPeggyFilterer(n.Address(), n.Provider())
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
-	}
-
-	iter, err := peggyFilterer.FilterSendToCosmosEvent(&bind.FilterOpts{
-		Start: startBlock,
-		End:   &endBlock,
-	}, nil, nil, nil)
-	if err != nil {
-		if !isUnknownBlockErr(err) {
-			return nil, errors.Wrapf(err, "failed to scan past SendToCosmos events from Ethereum (%d - %d)", startBlock, endBlock)
-		} else if iter == nil {
-			return nil, errors.New("no iterator returned")
-		}
-	}
-
-	defer iter.Close()
-
-	var sendToCosmosEvents []*peggyevents.PeggySendToCosmosEvent
-	for iter.Next() {
-		sendToCosmosEvents = append(sendToCosmosEvents, iter.Event)
-	}
-
-	return sendToCosmosEvents, nil
-}
-
-func (n *network) GetSendToInjectiveEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
+func (n *network) GetValsetUpdatedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
 	peggyFilterer, err := peggyevents.NewPeggyFilterer(n.Address(), n.Provider())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
 	}
 
-	iter, err := peggyFilterer.FilterSendToInjectiveEvent(&bind.FilterOpts{
-		Start: startBlock,
-		End:   &endBlock,
-	}, nil, nil, nil)
-	if err != nil {
-		if !isUnknownBlockErr(err) {
-			return nil, errors.Wrapf(err, "failed to scan past SendToInjectiveEvent events from Ethereum (%d - %d)", startBlock, endBlock)
-		} else if iter == nil {
-			return nil, errors.New("no iterator returned")
-		}
-	}
-
-	defer iter.Close()
-
-	var sendToInjectiveEvents []*peggyevents.PeggySendToInjectiveEvent
-	for iter.Next() {
-		sendToInjectiveEvents = append(sendToInjectiveEvents, iter.Event)
+	q := ethereum.FilterQuery{
+		Addresses: []gethcommon.Address{n.Address()},
+		Topics:    [][]gethcommon.Hash{{peggyABI.Events[valsetUpdatedEvent].ID}},
 	}
 
-	return sendToInjectiveEvents, nil
-}
-
-func (n *network) GetPeggyERC20DeployedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-	peggyFilterer, err := peggyevents.NewPeggyFilterer(n.Address(), n.Provider())
+	logs, err := n.logsWindow.filterLogs(context.Background(), n.Provider().FilterLogs, q, startBlock, endBlock)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
+		return nil, wrapLogsErr(err, valsetUpdatedEvent, startBlock, endBlock)
 	}
 
-	iter, err := peggyFilterer.FilterERC20DeployedEvent(&bind.FilterOpts{
-		Start: startBlock,
-		End:   &endBlock,
-	}, nil)
-	if err != nil {
-		if !isUnknownBlockErr(err) {
-			return nil, errors.Wrapf(err, "failed to scan past ERC20DeployedEvent events from Ethereum (%d - %d)", startBlock, endBlock)
-		} else if iter == nil {
-			return nil, errors.New("no iterator returned")
+	var valsetUpdatedEvents []*peggyevents.PeggyValsetUpdatedEvent
+	for _, l := range logs {
+		if l.Removed {
+			continue
 		}
-	}
-
-	defer iter.Close()
-
-	var transactionBatchExecutedEvents []*peggyevents.PeggyERC20DeployedEvent
-	for iter.Next() {
-		transactionBatchExecutedEvents = append(transactionBatchExecutedEvents, iter.Event)
-	}
-
-	return transactionBatchExecutedEvents, nil
-}
-
-func (n *network) GetValsetUpdatedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
-	peggyFilterer, err := peggyevents.NewPeggyFilterer(n.Address(), n.Provider())
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
-	}
 
-	iter, err := peggyFilterer.FilterValsetUpdatedEvent(&bind.FilterOpts{
-		Start: startBlock,
-		End:   &endBlock,
-	}, nil)
-	if err != nil {
-		if !isUnknownBlockErr(err) {
-			return nil, errors.Wrapf(err, "failed to scan past ValsetUpdatedEvent events from Ethereum (%d - %d)", startBlock, endBlock)
-		} else if iter == nil {
-			return nil, errors.New("no iterator returned")
+		ev, err := peggyFilterer.ParseValsetUpdatedEvent(l)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode ValsetUpdatedEvent (tx %s, log %d)", l.TxHash.Hex(), l.Index)
 		}
-	}
-
-	defer iter.Close()
 
-	var valsetUpdatedEvents []*peggyevents.PeggyValsetUpdatedEvent
-	for iter.Next() {
-		valsetUpdatedEvents = append(valsetUpdatedEvents, iter.Event)
+		valsetUpdatedEvents = append(valsetUpdatedEvents, ev)
 	}
 
 	return valsetUpdatedEvents, nil
 }
-
-func (n *network) GetTransactionBatchExecutedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-	peggyFilterer, err := peggyevents.NewPeggyFilterer(n.Address(), n.Provider())
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
-	}
-
-	iter, err := peggyFilterer.FilterTransactionBatchExecutedEvent(&bind.FilterOpts{
-		Start: startBlock,
-		End:   &endBlock,
-	}, nil, nil)
-	if err != nil {
-		if !isUnknownBlockErr(err) {
-			return nil, errors.Wrapf(err, "failed to scan past TransactionBatchExecuted events from Ethereum (%d - %d)", startBlock, endBlock)
-		} else if iter == nil {
-			return nil, errors.New("no iterator returned")
-		}
-	}
-
-	defer iter.Close()
-
-	var transactionBatchExecutedEvents []*peggyevents.PeggyTransactionBatchExecutedEvent
-	for iter.Next() {
-		transactionBatchExecutedEvents = append(transactionBatchExecutedEvents, iter.Event)
-	}
-
-	return transactionBatchExecutedEvents, nil
-}
-
-func isUnknownBlockErr(err error) bool {
-	// Geth error
-	if strings.Contains(err.Error(), "unknown block") {
-		return true
-	}
-
-	// Parity error
-	if strings.Contains(err.Error(), "One of the blocks specified in filter") {
-		return true
-	}
-
-	return false
-}
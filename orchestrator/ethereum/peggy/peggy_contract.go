@@ -41,6 +41,13 @@ type PeggyContract interface {
 		confirms []*types.MsgConfirmBatch,
 	) (*common.Hash, error)
 
+	EstimateTransactionBatchGas(
+		ctx context.Context,
+		currentValset *types.Valset,
+		batch *types.OutgoingTxBatch,
+		confirms []*types.MsgConfirmBatch,
+	) (uint64, error)
+
 	SendEthValsetUpdate(
 		ctx context.Context,
 		oldValset *types.Valset,
@@ -59,6 +66,12 @@ type PeggyContract interface {
 		callerAddress common.Address,
 	) (*big.Int, error)
 
+	GetLastEventNonce(
+		ctx context.Context,
+		blockNumber *big.Int,
+		callerAddress common.Address,
+	) (*big.Int, error)
+
 	GetPeggyID(
 		ctx context.Context,
 		callerAddress common.Address,
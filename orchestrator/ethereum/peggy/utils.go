@@ -49,6 +49,28 @@ func (s *peggyContract) GetValsetNonce(
 	return nonce, nil
 }
 
+// Gets the nonce of the last event emitted by the Peggy contract as of blockNumber (nil for the latest block).
+// Historical blocks require an archive node.
+func (s *peggyContract) GetLastEventNonce(
+	ctx context.Context,
+	blockNumber *big.Int,
+	callerAddress common.Address,
+) (*big.Int, error) {
+
+	nonce, err := s.ethPeggy.StateLastEventNonce(&bind.CallOpts{
+		From:        callerAddress,
+		Context:     ctx,
+		BlockNumber: blockNumber,
+	})
+
+	if err != nil {
+		err = errors.Wrap(err, "StateLastEventNonce call failed")
+		return nil, err
+	}
+
+	return nonce, nil
+}
+
 // Gets the peggyID
 func (s *peggyContract) GetPeggyID(
 	ctx context.Context,
@@ -0,0 +1,518 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+type HealthConfig struct {
+	// How often the head of every endpoint is polled
+	CheckInterval time.Duration
+	// Max number of blocks an endpoint's head may lag behind the best endpoint
+	MaxHeadLag uint64
+	// Max age of an endpoint's head block
+	MaxHeadAge time.Duration
+	// Max share of failed requests (exponentially weighted)
+	MaxErrorRate float64
+	// Max request latency (exponentially weighted)
+	MaxLatency time.Duration
+}
+
+var DefaultHealthConfig = HealthConfig{
+	CheckInterval: 15 * time.Second,
+	MaxHeadLag:    5,
+	MaxHeadAge:    3 * time.Minute,
+	MaxErrorRate:  0.5,
+	MaxLatency:    5 * time.Second,
+}
+
+// Weight of the latest sample in the error rate and latency averages
+const ewmaWeight = 0.2
+
+type endpoint struct {
+	name string
+	EVMProviderWithRet
+
+	mux       sync.RWMutex
+	head      uint64
+	headTime  time.Time
+	errorRate float64
+	latency   time.Duration
+	healthy   bool
+}
+
+func (e *endpoint) record(latency time.Duration, failed bool) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	sample := 0.0
+	if failed {
+		sample = 1
+	}
+
+	e.errorRate = (1-ewmaWeight)*e.errorRate + ewmaWeight*sample
+	if !failed {
+		e.latency = time.Duration((1-ewmaWeight)*float64(e.latency) + ewmaWeight*float64(latency))
+	}
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	return e.healthy
+}
+
+func (e *endpoint) currentLatency() time.Duration {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	return e.latency
+}
+
+// multiProvider spreads requests over several Ethereum endpoints. Requests go to the healthiest endpoint
+// and fail over to the next one if the endpoint cannot be reached. Nonce-sensitive calls stick to
+// a single endpoint for as long as it stays healthy, so that a sent tx is always reflected in the next nonce.
+type multiProvider struct {
+	endpoints []*endpoint
+	cfg       HealthConfig
+
+	stickyMux sync.Mutex
+	sticky    *endpoint
+
+	svcTags metrics.Tags
+}
+
+// NewMultiEVMProvider dials all endpoints and starts checking their health until ctx is done.
+func NewMultiEVMProvider(ctx context.Context, urls []string, cfg HealthConfig) (EVMProviderWithRet, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no Ethereum RPC endpoints provided")
+	}
+
+	p := &multiProvider{
+		cfg: cfg,
+		svcTags: metrics.Tags{
+			"svc": "eth_multi_provider",
+		},
+	}
+
+	for _, u := range urls {
+		rc, err := rpc.DialContext(ctx, u)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to ethereum RPC: %s", EndpointName(u))
+		}
+
+		p.endpoints = append(p.endpoints, &endpoint{
+			name:               EndpointName(u),
+			EVMProviderWithRet: NewEVMProvider(rc),
+			healthy:            true,
+		})
+	}
+
+	p.checkHealth(ctx)
+	go p.runHealthChecks(ctx)
+
+	return p, nil
+}
+
+// EndpointName strips the path and credentials, which often contain API keys, from the endpoint URL
+func EndpointName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+
+	return u.Host
+}
+
+func (p *multiProvider) runHealthChecks(ctx context.Context) {
+	t := time.NewTicker(p.cfg.CheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.checkHealth(ctx)
+		}
+	}
+}
+
+func (p *multiProvider) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			checkCtx, cancelFn := context.WithTimeout(ctx, p.cfg.CheckInterval)
+			defer cancelFn()
+
+			start := time.Now()
+			h, err := e.HeaderByNumber(checkCtx, nil)
+			e.record(time.Since(start), err != nil)
+
+			if err != nil {
+				log.WithError(err).WithField("endpoint", e.name).Debugln("Ethereum endpoint health check failed")
+				return
+			}
+
+			e.mux.Lock()
+			e.head = h.Number.Uint64()
+			e.headTime = time.Unix(int64(h.Time), 0)
+			e.mux.Unlock()
+		}(e)
+	}
+
+	wg.Wait()
+
+	p.updateHealth(time.Now())
+}
+
+func (p *multiProvider) updateHealth(now time.Time) {
+	var bestHead uint64
+	for _, e := range p.endpoints {
+		e.mux.RLock()
+		if e.head > bestHead {
+			bestHead = e.head
+		}
+		e.mux.RUnlock()
+	}
+
+	for _, e := range p.endpoints {
+		e.mux.Lock()
+
+		headLag := bestHead - e.head
+		healthy := headLag <= p.cfg.MaxHeadLag &&
+			now.Sub(e.headTime) <= p.cfg.MaxHeadAge &&
+			e.errorRate <= p.cfg.MaxErrorRate &&
+			e.latency <= p.cfg.MaxLatency
+
+		if healthy != e.healthy {
+			log.WithFields(log.Fields{
+				"endpoint":   e.name,
+				"healthy":    healthy,
+				"head_lag":   headLag,
+				"error_rate": e.errorRate,
+				"latency":    e.latency.String(),
+			}).Warningln("Ethereum endpoint health changed")
+		}
+
+		e.healthy = healthy
+		p.reportHealth(e, headLag)
+
+		e.mux.Unlock()
+	}
+}
+
+func (p *multiProvider) reportHealth(e *endpoint, headLag uint64) {
+	var (
+		healthy   = 0.0
+		errorRate = e.errorRate
+		latency   = e.latency
+	)
+
+	if e.healthy {
+		healthy = 1
+	}
+
+	tags := metrics.Tags{"svc": p.svcTags["svc"], "endpoint": e.name}
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Gauge("eth_provider.healthy", healthy, tagSpec, 1)
+		_ = s.Gauge("eth_provider.head_lag", float64(headLag), tagSpec, 1)
+		_ = s.Gauge("eth_provider.error_rate", errorRate, tagSpec, 1)
+		_ = s.Timing("eth_provider.latency", latency, tagSpec, 1)
+	}, tags)
+}
+
+// ranked returns the endpoints ordered by preference: healthy endpoints first, faster endpoints first
+func (p *multiProvider) ranked() []*endpoint {
+	ranked := make([]*endpoint, len(p.endpoints))
+	copy(ranked, p.endpoints)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		hi, hj := ranked[i].isHealthy(), ranked[j].isHealthy()
+		if hi != hj {
+			return hi
+		}
+
+		return ranked[i].currentLatency() < ranked[j].currentLatency()
+	})
+
+	return ranked
+}
+
+// stickyEndpoint returns the endpoint used for nonce-sensitive calls, switching to the best endpoint
+// only if the current one became unhealthy.
+func (p *multiProvider) stickyEndpoint() *endpoint {
+	p.stickyMux.Lock()
+	defer p.stickyMux.Unlock()
+
+	if p.sticky == nil || !p.sticky.isHealthy() {
+		best := p.ranked()[0]
+		if p.sticky != nil && p.sticky != best {
+			log.WithFields(log.Fields{"from": p.sticky.name, "to": best.name}).Warningln("switching Ethereum endpoint for nonce-sensitive calls")
+		}
+
+		p.sticky = best
+	}
+
+	return p.sticky
+}
+
+func (p *multiProvider) unstick(e *endpoint) {
+	p.stickyMux.Lock()
+	defer p.stickyMux.Unlock()
+
+	if p.sticky == e {
+		p.sticky = nil
+	}
+}
+
+// do runs fn against the endpoints in order of preference until one of them responds
+func (p *multiProvider) do(ctx context.Context, fn func(e *endpoint) error) error {
+	var lastErr error
+	for _, e := range p.ranked() {
+		if err := p.call(ctx, e, fn); err == nil || !(isEndpointErr(ctx, err) || isNotFoundErr(ctx, err)) {
+			return err
+		} else {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// doSticky runs fn against the sticky endpoint, failing over (and moving the sticky endpoint) only
+// if the endpoint cannot be reached
+func (p *multiProvider) doSticky(ctx context.Context, fn func(e *endpoint) error) error {
+	var lastErr error
+	for range p.endpoints {
+		e := p.stickyEndpoint()
+
+		err := p.call(ctx, e, fn)
+		if err == nil || !isEndpointErr(ctx, err) {
+			return err
+		}
+
+		lastErr = err
+
+		e.mux.Lock()
+		e.healthy = false
+		e.mux.Unlock()
+
+		p.unstick(e)
+	}
+
+	return lastErr
+}
+
+func (p *multiProvider) call(ctx context.Context, e *endpoint, fn func(e *endpoint) error) error {
+	start := time.Now()
+	err := fn(e)
+
+	failed := err != nil && isEndpointErr(ctx, err)
+	e.record(time.Since(start), failed)
+
+	if failed {
+		log.WithError(err).WithField("endpoint", e.name).Debugln("Ethereum endpoint request failed")
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			_ = s.Incr("eth_provider.failover", tagSpec, 1)
+		}, metrics.Tags{"svc": p.svcTags["svc"], "endpoint": e.name})
+	}
+
+	return err
+}
+
+// isEndpointErr tells whether the error is caused by the endpoint itself (unreachable, timed out, rate limited)
+// rather than by the request. Errors returned by a responsive node, such as a reverted call, are not retried
+// on other endpoints.
+func isEndpointErr(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		// cancelled by the caller
+		return false
+	}
+
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// isNotFoundErr tells whether the endpoint does not know the requested block, tx or receipt. A lagging endpoint
+// answers not found for what other endpoints already have, so the request is tried on the next endpoint. It does
+// not count as a failure of the endpoint, since a pending tx is not found anywhere.
+func isNotFoundErr(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && errors.Is(err, ethereum.NotFound)
+}
+
+func (p *multiProvider) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		code, err = e.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+
+	return code, err
+}
+
+func (p *multiProvider) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (res []byte, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		res, err = e.CallContract(ctx, call, blockNumber)
+		return err
+	})
+
+	return res, err
+}
+
+func (p *multiProvider) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		logs, err = e.FilterLogs(ctx, q)
+		return err
+	})
+
+	return logs, err
+}
+
+func (p *multiProvider) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		sub, err = e.SubscribeFilterLogs(ctx, q, ch)
+		return err
+	})
+
+	return sub, err
+}
+
+func (p *multiProvider) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = p.doSticky(ctx, func(e *endpoint) error {
+		nonce, err = e.PendingNonceAt(ctx, account)
+		return err
+	})
+
+	return nonce, err
+}
+
+func (p *multiProvider) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		code, err = e.PendingCodeAt(ctx, account)
+		return err
+	})
+
+	return code, err
+}
+
+func (p *multiProvider) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		gas, err = e.EstimateGas(ctx, msg)
+		return err
+	})
+
+	return gas, err
+}
+
+func (p *multiProvider) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		tip, err = e.SuggestGasTipCap(ctx)
+		return err
+	})
+
+	return tip, err
+}
+
+func (p *multiProvider) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		price, err = e.SuggestGasPrice(ctx)
+		return err
+	})
+
+	return price, err
+}
+
+func (p *multiProvider) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = p.doSticky(ctx, func(e *endpoint) error {
+		tx, isPending, err = e.TransactionByHash(ctx, hash)
+		return err
+	})
+
+	return tx, isPending, err
+}
+
+func (p *multiProvider) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		receipt, err = e.TransactionReceipt(ctx, txHash)
+		return err
+	})
+
+	return receipt, err
+}
+
+func (p *multiProvider) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return p.doSticky(ctx, func(e *endpoint) error {
+		return e.SendTransaction(ctx, tx)
+	})
+}
+
+func (p *multiProvider) SendTransactionWithRet(ctx context.Context, tx *types.Transaction) (txHash common.Hash, err error) {
+	err = p.doSticky(ctx, func(e *endpoint) error {
+		txHash, err = e.SendTransactionWithRet(ctx, tx)
+		return err
+	})
+
+	return txHash, err
+}
+
+func (p *multiProvider) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		header, err = e.HeaderByNumber(ctx, number)
+		return err
+	})
+
+	return header, err
+}
+
+func (p *multiProvider) BlockHashByNumber(ctx context.Context, number *big.Int) (hash common.Hash, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		hash, err = e.BlockHashByNumber(ctx, number)
+		return err
+	})
+
+	return hash, err
+}
+
+func (p *multiProvider) ReceiptsRootByHash(ctx context.Context, blockHash common.Hash) (root common.Hash, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		root, err = e.ReceiptsRootByHash(ctx, blockHash)
+		return err
+	})
+
+	return root, err
+}
+
+func (p *multiProvider) BlockReceipts(ctx context.Context, blockHash common.Hash) (receipts types.Receipts, err error) {
+	err = p.do(ctx, func(e *endpoint) error {
+		receipts, err = e.BlockReceipts(ctx, blockHash)
+		return err
+	})
+
+	return receipts, err
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type revertErr struct{}
+
+func (revertErr) Error() string  { return "execution reverted" }
+func (revertErr) ErrorCode() int { return 3 }
+
+type fakeEndpoint struct {
+	EVMProviderWithRet
+
+	head   uint64
+	err    error
+	nonce  uint64
+	called int
+}
+
+func (f *fakeEndpoint) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	f.called++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &types.Header{Number: new(big.Int).SetUint64(f.head), Time: uint64(time.Now().Unix())}, nil
+}
+
+func (f *fakeEndpoint) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.called++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return []byte{byte(f.head)}, nil
+}
+
+func (f *fakeEndpoint) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	f.called++
+	return f.nonce, f.err
+}
+
+func newTestMultiProvider(fakes ...*fakeEndpoint) *multiProvider {
+	p := &multiProvider{cfg: DefaultHealthConfig}
+	for i, f := range fakes {
+		p.endpoints = append(p.endpoints, &endpoint{
+			name:               string(rune('a' + i)),
+			EVMProviderWithRet: f,
+			healthy:            true,
+		})
+	}
+
+	return p
+}
+
+func TestMultiProvider_Failover(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name        string
+		firstErr    error
+		expectedRes []byte
+		expectedErr error
+	}{
+		{
+			name:        "endpoint unreachable",
+			firstErr:    errors.New("dial tcp: connection refused"),
+			expectedRes: []byte{2},
+		},
+
+		{
+			name:        "lagging endpoint",
+			firstErr:    ethereum.NotFound,
+			expectedRes: []byte{2},
+		},
+
+		{
+			name:        "call reverted",
+			firstErr:    revertErr{},
+			expectedErr: revertErr{},
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			first, second := &fakeEndpoint{head: 1, err: tt.firstErr}, &fakeEndpoint{head: 2}
+			p := newTestMultiProvider(first, second)
+
+			res, err := p.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedRes, res)
+			assert.Equal(t, 1, first.called)
+		})
+	}
+}
+
+func TestMultiProvider_Health(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fresh   = &fakeEndpoint{head: 100}
+		lagging = &fakeEndpoint{head: 90}
+		down    = &fakeEndpoint{err: errors.New("connection reset by peer")}
+	)
+
+	p := newTestMultiProvider(lagging, down, fresh)
+	for i := 0; i < 5; i++ {
+		p.checkHealth(context.Background())
+	}
+
+	assert.False(t, p.endpoints[0].isHealthy())
+	assert.False(t, p.endpoints[1].isHealthy())
+	assert.True(t, p.endpoints[2].isHealthy())
+
+	ranked := p.ranked()
+	assert.Equal(t, p.endpoints[2], ranked[0])
+}
+
+func TestMultiProvider_StickyNonce(t *testing.T) {
+	t.Parallel()
+
+	var (
+		first  = &fakeEndpoint{nonce: 7}
+		second = &fakeEndpoint{nonce: 5}
+	)
+
+	p := newTestMultiProvider(first, second)
+
+	nonce, err := p.PendingNonceAt(context.Background(), common.Address{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), nonce)
+
+	// a faster endpoint does not move nonce-sensitive calls
+	p.endpoints[0].latency = time.Second
+	nonce, err = p.PendingNonceAt(context.Background(), common.Address{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), nonce)
+
+	// an unreachable one does
+	first.err = errors.New("i/o timeout")
+	nonce, err = p.PendingNonceAt(context.Background(), common.Address{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+	assert.Equal(t, p.endpoints[1], p.sticky)
+}
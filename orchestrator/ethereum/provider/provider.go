@@ -31,6 +31,9 @@ type EVMProvider interface {
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 	SendTransaction(ctx context.Context, tx *types.Transaction) error
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error)
+	ReceiptsRootByHash(ctx context.Context, blockHash common.Hash) (common.Hash, error)
+	BlockReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error)
 }
 
 type EVMProviderWithRet interface {
@@ -74,6 +77,112 @@ func (p *evmProviderWithRet) SendTransactionWithRet(ctx context.Context, tx *typ
 	return txHash, nil
 }
 
+// BlockHashByNumber returns the block hash reported by the node. Unlike types.Header.Hash(), it does not depend on
+// the header fields known to this go-ethereum version.
+func (p *evmProviderWithRet) BlockHashByNumber(ctx context.Context, number *big.Int) (common.Hash, error) {
+	var block struct {
+		Hash *common.Hash `json:"hash"`
+	}
+
+	if err := p.rc.CallContext(ctx, &block, "eth_getBlockByNumber", blockNumberArg(number), false); err != nil {
+		return common.Hash{}, err
+	}
+
+	if block.Hash == nil {
+		return common.Hash{}, ethereum.NotFound
+	}
+
+	return *block.Hash, nil
+}
+
+// ReceiptsRootByHash returns the receiptsRoot of the block header as reported by the node.
+func (p *evmProviderWithRet) ReceiptsRootByHash(ctx context.Context, blockHash common.Hash) (common.Hash, error) {
+	var block struct {
+		Hash         *common.Hash `json:"hash"`
+		ReceiptsRoot common.Hash  `json:"receiptsRoot"`
+	}
+
+	if err := p.rc.CallContext(ctx, &block, "eth_getBlockByHash", blockHash, false); err != nil {
+		return common.Hash{}, err
+	}
+
+	if block.Hash == nil {
+		return common.Hash{}, ethereum.NotFound
+	}
+
+	if *block.Hash != blockHash {
+		return common.Hash{}, errors.Errorf("node returned block %s instead of %s", block.Hash.Hex(), blockHash.Hex())
+	}
+
+	return block.ReceiptsRoot, nil
+}
+
+// BlockReceipts returns the receipts of all transactions in the block, in the order of the transactions.
+// Nodes that do not support eth_getBlockReceipts are queried for every receipt in a batch.
+func (p *evmProviderWithRet) BlockReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error) {
+	var receipts types.Receipts
+	err := p.rc.CallContext(ctx, &receipts, "eth_getBlockReceipts", blockHash)
+	if err == nil {
+		return receipts, nil
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return nil, err
+	}
+
+	var block struct {
+		Hash         *common.Hash  `json:"hash"`
+		Transactions []common.Hash `json:"transactions"`
+	}
+
+	if err := p.rc.CallContext(ctx, &block, "eth_getBlockByHash", blockHash, false); err != nil {
+		return nil, err
+	}
+
+	if block.Hash == nil {
+		return nil, ethereum.NotFound
+	}
+
+	receipts = make(types.Receipts, len(block.Transactions))
+	reqs := make([]rpc.BatchElem, len(block.Transactions))
+	for i, txHash := range block.Transactions {
+		reqs[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{txHash},
+			Result: &receipts[i],
+		}
+	}
+
+	if err := p.rc.BatchCallContext(ctx, reqs); err != nil {
+		return nil, err
+	}
+
+	for i, req := range reqs {
+		if req.Error != nil {
+			return nil, req.Error
+		}
+
+		if receipts[i] == nil {
+			return nil, errors.Errorf("missing receipt of tx %s", block.Transactions[i].Hex())
+		}
+	}
+
+	return receipts, nil
+}
+
+func blockNumberArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+
+	if number.Sign() < 0 && number.IsInt64() {
+		return rpc.BlockNumber(number.Int64()).String()
+	}
+
+	return hexutil.EncodeBig(number)
+}
+
 type TransactFunc func(opts *bind.TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error)
 
 func TransactFn(p EVMProviderWithRet, contractAddress common.Address, txHashOut *common.Hash) TransactFunc {
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEVMProvider_BlockHashByNumber(t *testing.T) {
+	t.Parallel()
+
+	hash := common.HexToHash("0x4c1a9b3e2c7f1d0e5b8a6f4d3c2b1a0918273645546372819a0b1c2d3e4f5061")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/missing" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+			return
+		}
+
+		// the node reports the hash of the full header, including fields unknown to this go-ethereum version
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x64","hash":"` + hash.Hex() + `","blobGasUsed":"0x0"}}`))
+	}))
+	defer srv.Close()
+
+	rc, err := rpc.Dial(srv.URL)
+	assert.NoError(t, err)
+
+	actual, err := NewEVMProvider(rc).BlockHashByNumber(context.Background(), big.NewInt(100))
+	assert.NoError(t, err)
+	assert.Equal(t, hash, actual)
+
+	rc, err = rpc.Dial(srv.URL + "/missing")
+	assert.NoError(t, err)
+
+	_, err = NewEVMProvider(rc).BlockHashByNumber(context.Background(), big.NewInt(100))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+}
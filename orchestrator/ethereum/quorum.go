@@ -0,0 +1,278 @@
+package ethereum
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/provider"
+	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
+)
+
+type (
+	logsByBlockFn  func(ctx context.Context, blockHash gethcommon.Hash) ([]gethtypes.Log, error)
+	receiptsRootFn func(ctx context.Context, blockHash gethcommon.Hash) (gethcommon.Hash, error)
+)
+
+// quorumVerifier is an independent Ethereum provider used to cross-check the primary one
+type quorumVerifier struct {
+	name         string
+	logsByBlock  logsByBlockFn
+	receiptsRoot receiptsRootFn
+}
+
+func dialVerifiers(peggyAddr gethcommon.Address, endpoints []string) ([]quorumVerifier, error) {
+	verifiers := make([]quorumVerifier, 0, len(endpoints))
+	for _, u := range endpoints {
+		rc, err := rpc.Dial(u)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to verifying ethereum RPC: %s", provider.EndpointName(u))
+		}
+
+		var (
+			p     = provider.NewEVMProvider(rc)
+			query = peggyEventsQuery(peggyAddr)
+		)
+
+		verifiers = append(verifiers, quorumVerifier{
+			name: provider.EndpointName(u),
+			logsByBlock: func(ctx context.Context, blockHash gethcommon.Hash) ([]gethtypes.Log, error) {
+				blockQuery := query
+				blockQuery.BlockHash = &blockHash
+				return p.FilterLogs(ctx, blockQuery)
+			},
+			receiptsRoot: p.ReceiptsRootByHash,
+		})
+	}
+
+	return verifiers, nil
+}
+
+// eventQuorum re-fetches Peggy events from independent providers before they are claimed. An event
+// is verified once enough providers return a log with the same tx hash, log index, block hash and decoded fields.
+type eventQuorum struct {
+	verifiers []quorumVerifier
+	quorum    int
+	filterer  *peggyevents.PeggyFilterer
+
+	svcTags metrics.Tags
+}
+
+// quorumSize returns the number of verifying providers that must agree, a majority of them by default
+func quorumSize(quorum, verifiers int) (int, error) {
+	if quorum <= 0 {
+		quorum = verifiers/2 + 1
+	}
+
+	if quorum > verifiers {
+		return 0, errors.Errorf("event quorum %d exceeds the number of verifying endpoints (%d)", quorum, verifiers)
+	}
+
+	return quorum, nil
+}
+
+func newEventQuorum(peggyAddr gethcommon.Address, verifiers []quorumVerifier, quorum int) (*eventQuorum, error) {
+	if len(verifiers) == 0 {
+		return nil, nil
+	}
+
+	quorum, err := quorumSize(quorum, len(verifiers))
+	if err != nil {
+		return nil, err
+	}
+
+	filterer, err := peggyevents.NewPeggyFilterer(peggyAddr, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init Peggy events filterer")
+	}
+
+	q := &eventQuorum{
+		verifiers: verifiers,
+		quorum:    quorum,
+		filterer:  filterer,
+		svcTags:   metrics.Tags{"svc": "eth_quorum"},
+	}
+
+	return q, nil
+}
+
+// VerifyPeggyEvents returns the number of leading logs (in the given order) confirmed by the quorum of
+// verifying providers and, if enabled, proven to be included in their blocks' receipts. All logs are
+// considered verified if neither is configured.
+func (n *network) VerifyPeggyEvents(ctx context.Context, logs []gethtypes.Log) (int, error) {
+	verified := len(logs)
+	if n.quorum != nil {
+		var err error
+		if verified, err = n.quorum.verify(ctx, logs); err != nil {
+			return 0, err
+		}
+	}
+
+	if n.prover == nil || verified == 0 {
+		return verified, nil
+	}
+
+	return n.prover.prove(ctx, logs[:verified])
+}
+
+func (q *eventQuorum) verify(ctx context.Context, logs []gethtypes.Log) (int, error) {
+	metrics.ReportFuncCall(q.svcTags)
+	doneFn := metrics.ReportFuncTiming(q.svcTags)
+	defer doneFn()
+
+	expected := make([]PeggyEvent, len(logs))
+	for i, l := range logs {
+		ev, err := decodePeggyEvent(q.filterer, l)
+		if err != nil {
+			metrics.ReportFuncError(q.svcTags)
+			return 0, errors.Wrapf(err, "failed to decode Peggy event (tx %s, log %d)", l.TxHash.Hex(), l.Index)
+		}
+
+		expected[i] = ev
+	}
+
+	// votes[i][j] tells whether verifier j agrees on logs[i]
+	votes := make([][]bool, len(logs))
+	for i := range votes {
+		votes[i] = make([]bool, len(q.verifiers))
+	}
+
+	var wg sync.WaitGroup
+	for j, v := range q.verifiers {
+		wg.Add(1)
+		go func(j int, v quorumVerifier) {
+			defer wg.Done()
+
+			for i, agrees := range q.vote(ctx, v, logs, expected) {
+				votes[i][j] = agrees
+			}
+		}(j, v)
+	}
+
+	wg.Wait()
+
+	for i, l := range logs {
+		agreed := 0
+		for _, agrees := range votes[i] {
+			if agrees {
+				agreed++
+			}
+		}
+
+		if agreed < q.quorum {
+			log.WithFields(log.Fields{
+				"event_nonce": expected[i].Nonce,
+				"tx_hash":     l.TxHash.Hex(),
+				"log_index":   l.Index,
+				"agreed":      agreed,
+				"quorum":      q.quorum,
+			}).Warningln("Peggy event not confirmed by quorum of Ethereum providers")
+
+			metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+				_ = s.Incr("eth_quorum.unverified_events", tagSpec, 1)
+			}, q.svcTags)
+
+			return i, nil
+		}
+	}
+
+	return len(logs), nil
+}
+
+// vote compares the logs against the ones returned by the verifier. A verifier that cannot be reached
+// abstains, a verifier returning a different (or no) log disagrees.
+func (q *eventQuorum) vote(ctx context.Context, v quorumVerifier, logs []gethtypes.Log, expected []PeggyEvent) []bool {
+	var (
+		votes  = make([]bool, len(logs))
+		blocks = make(map[gethcommon.Hash]map[uint]gethtypes.Log)
+	)
+
+	for i, l := range logs {
+		logFields := log.Fields{
+			"endpoint":    v.name,
+			"event_nonce": expected[i].Nonce,
+			"tx_hash":     l.TxHash.Hex(),
+			"log_index":   l.Index,
+			"block_hash":  l.BlockHash.Hex(),
+		}
+
+		blockLogs, ok := blocks[l.BlockHash]
+		if !ok {
+			fetched, err := v.logsByBlock(ctx, l.BlockHash)
+			if err != nil {
+				log.WithFields(logFields).WithError(err).Warningln("failed to fetch Peggy events from verifying Ethereum provider")
+				q.report("eth_quorum.failures", v.name)
+				continue
+			}
+
+			blockLogs = make(map[uint]gethtypes.Log, len(fetched))
+			for _, fl := range fetched {
+				if !fl.Removed {
+					blockLogs[fl.Index] = fl
+				}
+			}
+
+			blocks[l.BlockHash] = blockLogs
+		}
+
+		if reason := q.disagreement(blockLogs, l, expected[i]); reason != "" {
+			log.WithFields(logFields).WithField("reason", reason).Errorln("verifying Ethereum provider disagrees on Peggy event")
+			q.report("eth_quorum.disagreements", v.name)
+			continue
+		}
+
+		votes[i] = true
+	}
+
+	return votes
+}
+
+// disagreement returns why the verifier's logs do not match the expected event, or an empty string if they do
+func (q *eventQuorum) disagreement(blockLogs map[uint]gethtypes.Log, l gethtypes.Log, expected PeggyEvent) string {
+	actual, ok := blockLogs[l.Index]
+	switch {
+	case !ok:
+		return "event not found"
+	case actual.TxHash != l.TxHash:
+		return "tx hash mismatch"
+	case actual.BlockHash != l.BlockHash:
+		return "block hash mismatch"
+	}
+
+	ev, err := decodePeggyEvent(q.filterer, actual)
+	if err != nil {
+		return "failed to decode event: " + err.Error()
+	}
+
+	if ev.Nonce != expected.Nonce || !reflect.DeepEqual(eventFields(ev.Event), eventFields(expected.Event)) {
+		return "event fields mismatch"
+	}
+
+	return ""
+}
+
+// eventFields strips the raw log from a decoded Peggy event, leaving only the decoded fields
+func eventFields(ev interface{}) interface{} {
+	v := reflect.ValueOf(ev).Elem()
+
+	fields := reflect.New(v.Type()).Elem()
+	fields.Set(v)
+
+	raw := fields.FieldByName("Raw")
+	raw.Set(reflect.Zero(raw.Type()))
+
+	return fields.Interface()
+}
+
+func (q *eventQuorum) report(metric, endpoint string) {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr(metric, tagSpec, 1)
+	}, metrics.Tags{"svc": q.svcTags["svc"], "endpoint": endpoint})
+}
@@ -0,0 +1,127 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/metrics"
+	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
+)
+
+func TestEventQuorum(t *testing.T) {
+	t.Parallel()
+
+	peggyAddr := gethcommon.HexToAddress("0x5048019d259217e6b7BC8e1E6aEfa9976B1ADFfe")
+	tokenAddr := gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	blockHash := gethcommon.HexToHash("0xb10c")
+
+	filterer, err := peggyevents.NewPeggyFilterer(peggyAddr, nil)
+	assert.NoError(t, err)
+
+	withdrawalLog := func(eventNonce, batchNonce int64, index uint) gethtypes.Log {
+		data, err := peggyABI.Events[transactionBatchExecutedEvent].Inputs.NonIndexed().Pack(big.NewInt(eventNonce))
+		assert.NoError(t, err)
+
+		return gethtypes.Log{
+			Address: peggyAddr,
+			Topics: []gethcommon.Hash{
+				peggyABI.Events[transactionBatchExecutedEvent].ID,
+				gethcommon.BigToHash(big.NewInt(batchNonce)),
+				gethcommon.BytesToHash(tokenAddr.Bytes()),
+			},
+			Data:      data,
+			BlockHash: blockHash,
+			TxHash:    gethcommon.BigToHash(big.NewInt(eventNonce)),
+			Index:     index,
+		}
+	}
+
+	logs := []gethtypes.Log{withdrawalLog(10, 1, 0), withdrawalLog(11, 2, 1)}
+
+	honest := func(_ context.Context, _ gethcommon.Hash) ([]gethtypes.Log, error) {
+		return logs, nil
+	}
+
+	// reports a different batch nonce for the second event
+	lying := func(_ context.Context, _ gethcommon.Hash) ([]gethtypes.Log, error) {
+		return []gethtypes.Log{logs[0], withdrawalLog(11, 3, 1)}, nil
+	}
+
+	// does not know about the second event
+	missing := func(_ context.Context, _ gethcommon.Hash) ([]gethtypes.Log, error) {
+		return logs[:1], nil
+	}
+
+	down := func(_ context.Context, _ gethcommon.Hash) ([]gethtypes.Log, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	testTable := []struct {
+		name             string
+		verifiers        []logsByBlockFn
+		quorum           int
+		expectedVerified int
+	}{
+		{
+			name:             "all agree",
+			verifiers:        []logsByBlockFn{honest, honest, honest},
+			quorum:           3,
+			expectedVerified: 2,
+		},
+
+		{
+			name:             "quorum despite a lying provider",
+			verifiers:        []logsByBlockFn{honest, lying, honest},
+			quorum:           2,
+			expectedVerified: 2,
+		},
+
+		{
+			name:             "decoded fields disagree",
+			verifiers:        []logsByBlockFn{honest, lying, down},
+			quorum:           2,
+			expectedVerified: 1,
+		},
+
+		{
+			name:             "event missing",
+			verifiers:        []logsByBlockFn{missing, missing, honest},
+			quorum:           2,
+			expectedVerified: 1,
+		},
+
+		{
+			name:             "providers down",
+			verifiers:        []logsByBlockFn{down, down, honest},
+			quorum:           2,
+			expectedVerified: 0,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := &eventQuorum{
+				quorum:   tt.quorum,
+				filterer: filterer,
+				svcTags:  metrics.Tags{"svc": "eth_quorum"},
+			}
+
+			for i, fn := range tt.verifiers {
+				q.verifiers = append(q.verifiers, quorumVerifier{name: string(rune('a' + i)), logsByBlock: fn})
+			}
+
+			verified, err := q.verify(context.Background(), logs)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedVerified, verified)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/provider"
+)
+
+// errUnproven is returned when a log cannot be proven to be included in its block
+var errUnproven = errors.New("log inclusion not proven")
+
+type blockReceiptsFn func(ctx context.Context, blockHash gethcommon.Hash) (gethtypes.Receipts, error)
+
+// receiptsProver proves that Peggy logs are included in their blocks. It rebuilds the receipts trie
+// from the block receipts and checks it against the receiptsRoot of the block header. The header is
+// validated independently when verifying providers are configured: the quorum of them must report
+// the same receiptsRoot for the block hash as the primary provider.
+type receiptsProver struct {
+	receiptsRoot  receiptsRootFn
+	blockReceipts blockReceiptsFn
+	verifiers     []quorumVerifier
+	quorum        int
+
+	svcTags metrics.Tags
+}
+
+func newReceiptsProver(p provider.EVMProvider, verifiers []quorumVerifier, quorum int) (*receiptsProver, error) {
+	if len(verifiers) == 0 {
+		log.Warningln("Ethereum receipts are proven against headers of the primary provider only, set verifying providers to validate the headers independently")
+	} else {
+		var err error
+		if quorum, err = quorumSize(quorum, len(verifiers)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &receiptsProver{
+		receiptsRoot:  p.ReceiptsRootByHash,
+		blockReceipts: p.BlockReceipts,
+		verifiers:     verifiers,
+		quorum:        quorum,
+		svcTags:       metrics.Tags{"svc": "eth_receipts"},
+	}, nil
+}
+
+// prove returns the number of leading logs (in the given order) proven to be included in their blocks
+func (p *receiptsProver) prove(ctx context.Context, logs []gethtypes.Log) (int, error) {
+	metrics.ReportFuncCall(p.svcTags)
+	doneFn := metrics.ReportFuncTiming(p.svcTags)
+	defer doneFn()
+
+	blocks := make(map[gethcommon.Hash]map[uint]*gethtypes.Log)
+	for i, l := range logs {
+		logFields := log.Fields{
+			"tx_hash":    l.TxHash.Hex(),
+			"log_index":  l.Index,
+			"block_hash": l.BlockHash.Hex(),
+		}
+
+		provenLogs, ok := blocks[l.BlockHash]
+		if !ok {
+			var err error
+			if provenLogs, err = p.proveBlock(ctx, l.BlockHash); err != nil {
+				if !errors.Is(err, errUnproven) {
+					metrics.ReportFuncError(p.svcTags)
+					return 0, err
+				}
+
+				log.WithFields(logFields).WithError(err).Errorln("failed to prove Peggy event inclusion in Ethereum block")
+				p.reportUnproven()
+
+				return i, nil
+			}
+
+			blocks[l.BlockHash] = provenLogs
+		}
+
+		if reason := provenLogMismatch(provenLogs, l); reason != "" {
+			log.WithFields(logFields).WithField("reason", reason).Errorln("Peggy event does not match the proven Ethereum block receipts")
+			p.reportUnproven()
+
+			return i, nil
+		}
+	}
+
+	return len(logs), nil
+}
+
+// proveBlock returns the logs of the block, indexed by log index, after checking the block receipts
+// against the validated receiptsRoot.
+func (p *receiptsProver) proveBlock(ctx context.Context, blockHash gethcommon.Hash) (map[uint]*gethtypes.Log, error) {
+	root, err := p.validatedReceiptsRoot(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := p.blockReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get receipts of block %s", blockHash.Hex())
+	}
+
+	if derived := gethtypes.DeriveSha(consensusReceipts(receipts), newReceiptsTrie()); derived != root {
+		return nil, errors.Wrapf(errUnproven, "receipts trie root %s does not match receiptsRoot %s", derived.Hex(), root.Hex())
+	}
+
+	// log indexes are derived from the position of the logs in the proven receipts
+	var (
+		logs     = make(map[uint]*gethtypes.Log)
+		logIndex uint
+	)
+
+	for _, r := range receipts {
+		for _, l := range r.Logs {
+			proven := *l
+			proven.TxHash = r.TxHash
+			logs[logIndex] = &proven
+			logIndex++
+		}
+	}
+
+	return logs, nil
+}
+
+// validatedReceiptsRoot returns the receiptsRoot of the block, confirmed by the quorum of verifying providers
+func (p *receiptsProver) validatedReceiptsRoot(ctx context.Context, blockHash gethcommon.Hash) (gethcommon.Hash, error) {
+	root, err := p.receiptsRoot(ctx, blockHash)
+	if err != nil {
+		return gethcommon.Hash{}, errors.Wrapf(err, "failed to get header of block %s", blockHash.Hex())
+	}
+
+	if len(p.verifiers) == 0 {
+		return root, nil
+	}
+
+	agreed := 0
+	for _, v := range p.verifiers {
+		verifierRoot, err := v.receiptsRoot(ctx, blockHash)
+		if err != nil {
+			log.WithField("endpoint", v.name).WithError(err).Warningln("failed to get block header from verifying Ethereum provider")
+			continue
+		}
+
+		if verifierRoot != root {
+			log.WithFields(log.Fields{"endpoint": v.name, "block_hash": blockHash.Hex(), "receipts_root": root.Hex(), "verifier_receipts_root": verifierRoot.Hex()}).Errorln("verifying Ethereum provider disagrees on block header")
+			continue
+		}
+
+		agreed++
+	}
+
+	if agreed < p.quorum {
+		return gethcommon.Hash{}, errors.Wrapf(errUnproven, "receiptsRoot of block %s confirmed by %d verifying providers, %d required", blockHash.Hex(), agreed, p.quorum)
+	}
+
+	return root, nil
+}
+
+// provenLogMismatch returns why the log does not match the proven one, or an empty string if it does
+func provenLogMismatch(provenLogs map[uint]*gethtypes.Log, l gethtypes.Log) string {
+	proven, ok := provenLogs[l.Index]
+	switch {
+	case !ok:
+		return "log not found in block receipts"
+	case proven.TxHash != l.TxHash:
+		return "tx hash mismatch"
+	case proven.Address != l.Address:
+		return "contract address mismatch"
+	case len(proven.Topics) != len(l.Topics):
+		return "topics mismatch"
+	case !bytes.Equal(proven.Data, l.Data):
+		return "data mismatch"
+	}
+
+	for i := range l.Topics {
+		if proven.Topics[i] != l.Topics[i] {
+			return "topics mismatch"
+		}
+	}
+
+	return ""
+}
+
+func (p *receiptsProver) reportUnproven() {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("eth_receipts.unproven_events", tagSpec, 1)
+	}, p.svcTags)
+}
+
+// consensusReceipts encodes receipts the way they are stored in the receipts trie. The receipts of all typed
+// transactions (including blob transactions, which are unknown to the go-ethereum version we depend on)
+// are encoded as the type byte followed by the RLP encoded receipt.
+type consensusReceipts gethtypes.Receipts
+
+type receiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             gethtypes.Bloom
+	Logs              []*gethtypes.Log
+}
+
+func (rs consensusReceipts) Len() int {
+	return len(rs)
+}
+
+func (rs consensusReceipts) EncodeIndex(i int, w *bytes.Buffer) {
+	r := rs[i]
+
+	status := r.PostState
+	if len(status) == 0 {
+		status = []byte{}
+		if r.Status == gethtypes.ReceiptStatusSuccessful {
+			status = []byte{0x01}
+		}
+	}
+
+	if r.Type != gethtypes.LegacyTxType {
+		w.WriteByte(r.Type)
+	}
+
+	// a failed encoding results in a root mismatch
+	_ = rlp.Encode(w, &receiptRLP{status, r.CumulativeGasUsed, r.Bloom, r.Logs})
+}
@@ -0,0 +1,182 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+func TestReceiptsTrie(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, gethtypes.EmptyRootHash, newReceiptsTrie().Hash())
+
+	// go-ethereum trie test vectors
+	tr := newReceiptsTrie()
+	assert.NoError(t, tr.Update([]byte("doe"), []byte("reindeer")))
+	assert.NoError(t, tr.Update([]byte("dog"), []byte("puppy")))
+	assert.NoError(t, tr.Update([]byte("dogglesworth"), []byte("cat")))
+	assert.Equal(t, gethcommon.HexToHash("8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3"), tr.Hash())
+
+	tr.Reset()
+	assert.NoError(t, tr.Update([]byte("A"), []byte("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")))
+	assert.Equal(t, gethcommon.HexToHash("d23786fb4a010da3ce639d66d5e904a11dbc02746d1ce25029e53290cabf28ab"), tr.Hash())
+}
+
+func TestReceiptsProver(t *testing.T) {
+	t.Parallel()
+
+	peggyAddr := gethcommon.HexToAddress("0x5048019d259217e6b7BC8e1E6aEfa9976B1ADFfe")
+	blockHash := gethcommon.HexToHash("0xb10c")
+
+	receipts := func() gethtypes.Receipts {
+		return gethtypes.Receipts{
+			{Type: gethtypes.LegacyTxType, Status: 1, CumulativeGasUsed: 21000, TxHash: gethcommon.HexToHash("0x01")},
+			{
+				Type:              3, // blob tx
+				Status:            1,
+				CumulativeGasUsed: 80000,
+				TxHash:            gethcommon.HexToHash("0x02"),
+				Logs: []*gethtypes.Log{
+					{Address: gethcommon.HexToAddress("0x01"), Data: []byte{1}},
+				},
+			},
+			{
+				Type:              gethtypes.DynamicFeeTxType,
+				Status:            1,
+				CumulativeGasUsed: 150000,
+				TxHash:            gethcommon.HexToHash("0x03"),
+				Logs: []*gethtypes.Log{
+					{Address: peggyAddr, Topics: []gethcommon.Hash{gethcommon.HexToHash("0xaa")}, Data: []byte{2}},
+					{Address: peggyAddr, Topics: []gethcommon.Hash{gethcommon.HexToHash("0xbb")}, Data: []byte{3}},
+				},
+			},
+		}
+	}
+
+	root := gethtypes.DeriveSha(consensusReceipts(receipts()), newReceiptsTrie())
+
+	peggyLog := func(index uint, topic string, data byte) gethtypes.Log {
+		return gethtypes.Log{
+			Address:   peggyAddr,
+			Topics:    []gethcommon.Hash{gethcommon.HexToHash(topic)},
+			Data:      []byte{data},
+			BlockHash: blockHash,
+			TxHash:    gethcommon.HexToHash("0x03"),
+			Index:     index,
+		}
+	}
+
+	logs := []gethtypes.Log{peggyLog(1, "0xaa", 2), peggyLog(2, "0xbb", 3)}
+
+	rootFn := func(root gethcommon.Hash, err error) receiptsRootFn {
+		return func(_ context.Context, _ gethcommon.Hash) (gethcommon.Hash, error) {
+			return root, err
+		}
+	}
+
+	testTable := []struct {
+		name             string
+		logs             []gethtypes.Log
+		receipts         func() gethtypes.Receipts
+		verifierRoots    []receiptsRootFn
+		expectedVerified int
+		expectedErr      bool
+	}{
+		{
+			name:             "logs proven",
+			logs:             logs,
+			receipts:         receipts,
+			expectedVerified: 2,
+		},
+
+		{
+			name:             "forged log",
+			logs:             []gethtypes.Log{logs[0], peggyLog(2, "0xbb", 4)},
+			receipts:         receipts,
+			expectedVerified: 1,
+		},
+
+		{
+			name:             "log index out of receipts",
+			logs:             []gethtypes.Log{peggyLog(3, "0xaa", 2)},
+			receipts:         receipts,
+			expectedVerified: 0,
+		},
+
+		{
+			name: "forged receipts",
+			logs: logs,
+			receipts: func() gethtypes.Receipts {
+				rs := receipts()
+				rs[2].Logs[1].Data = []byte{4}
+				return rs
+			},
+			expectedVerified: 0,
+		},
+
+		{
+			name:             "header confirmed by verifiers",
+			logs:             logs,
+			receipts:         receipts,
+			verifierRoots:    []receiptsRootFn{rootFn(root, nil), rootFn(gethcommon.Hash{}, errors.New("timeout")), rootFn(root, nil)},
+			expectedVerified: 2,
+		},
+
+		{
+			name:             "verifiers disagree on header",
+			logs:             logs,
+			receipts:         receipts,
+			verifierRoots:    []receiptsRootFn{rootFn(root, nil), rootFn(gethcommon.HexToHash("0x01"), nil), rootFn(gethcommon.HexToHash("0x01"), nil)},
+			expectedVerified: 0,
+		},
+
+		{
+			name: "receipts unavailable",
+			logs: logs,
+			receipts: func() gethtypes.Receipts {
+				return nil
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &receiptsProver{
+				receiptsRoot: rootFn(root, nil),
+				blockReceipts: func(_ context.Context, _ gethcommon.Hash) (gethtypes.Receipts, error) {
+					if rs := tt.receipts(); rs != nil {
+						return rs, nil
+					}
+
+					return nil, errors.New("connection refused")
+				},
+				quorum:  2,
+				svcTags: metrics.Tags{"svc": "eth_receipts"},
+			}
+
+			for i, fn := range tt.verifierRoots {
+				p.verifiers = append(p.verifiers, quorumVerifier{name: string(rune('a' + i)), receiptsRoot: fn})
+			}
+
+			verified, err := p.prove(context.Background(), tt.logs)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedVerified, verified)
+		})
+	}
+}
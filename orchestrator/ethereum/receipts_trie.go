@@ -0,0 +1,146 @@
+package ethereum
+
+import (
+	"bytes"
+	"sort"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// receiptsTrie computes the root hash of a Merkle Patricia trie built in memory. It implements
+// gethtypes.TrieHasher so it can be used with gethtypes.DeriveSha (go-ethereum's trie package
+// cannot be used with the database dependencies pinned by this module).
+type receiptsTrie struct {
+	entries []trieEntry
+}
+
+type trieEntry struct {
+	key   []byte // nibbles
+	value []byte
+}
+
+func newReceiptsTrie() *receiptsTrie {
+	return &receiptsTrie{}
+}
+
+func (t *receiptsTrie) Reset() {
+	t.entries = nil
+}
+
+func (t *receiptsTrie) Update(key, value []byte) error {
+	t.entries = append(t.entries, trieEntry{
+		key:   keyNibbles(key),
+		value: append([]byte(nil), value...),
+	})
+
+	return nil
+}
+
+func (t *receiptsTrie) Hash() gethcommon.Hash {
+	if len(t.entries) == 0 {
+		return gethtypes.EmptyRootHash
+	}
+
+	sort.Slice(t.entries, func(i, j int) bool {
+		return bytes.Compare(t.entries[i].key, t.entries[j].key) < 0
+	})
+
+	return crypto.Keccak256Hash(encodeTrieNode(t.entries, 0))
+}
+
+// encodeTrieNode returns the RLP encoding of the node holding the (sorted) entries below depth
+func encodeTrieNode(entries []trieEntry, depth int) []byte {
+	if len(entries) == 1 {
+		return mustEncodeRLP([]interface{}{hexPrefix(entries[0].key[depth:], true), entries[0].value})
+	}
+
+	// sorted keys share the prefix of the first and the last one
+	first, last := entries[0].key[depth:], entries[len(entries)-1].key[depth:]
+	prefix := 0
+	for prefix < len(first) && prefix < len(last) && first[prefix] == last[prefix] {
+		prefix++
+	}
+
+	if prefix > 0 {
+		child := encodeTrieNode(entries, depth+prefix)
+		return mustEncodeRLP([]interface{}{hexPrefix(first[:prefix], false), trieNodeRef(child)})
+	}
+
+	branch := make([]interface{}, 17)
+	for i := range branch {
+		branch[i] = []byte{}
+	}
+
+	// a key ending at this node is the shortest one, so it sorts first
+	if len(entries[0].key) == depth {
+		branch[16] = entries[0].value
+		entries = entries[1:]
+	}
+
+	for len(entries) > 0 {
+		nibble := entries[0].key[depth]
+
+		n := 1
+		for n < len(entries) && entries[n].key[depth] == nibble {
+			n++
+		}
+
+		branch[nibble] = trieNodeRef(encodeTrieNode(entries[:n], depth+1))
+		entries = entries[n:]
+	}
+
+	return mustEncodeRLP(branch)
+}
+
+// trieNodeRef embeds nodes shorter than a hash into their parent and references the others by hash
+func trieNodeRef(encoded []byte) interface{} {
+	if len(encoded) < 32 {
+		return rlp.RawValue(encoded)
+	}
+
+	return crypto.Keccak256(encoded)
+}
+
+func keyNibbles(key []byte) []byte {
+	nibbles := make([]byte, 0, len(key)*2)
+	for _, b := range key {
+		nibbles = append(nibbles, b/16, b%16)
+	}
+
+	return nibbles
+}
+
+// hexPrefix compacts the nibbles of a leaf or extension path
+func hexPrefix(nibbles []byte, leaf bool) []byte {
+	var flag byte
+	if leaf {
+		flag = 2
+	}
+
+	var compact []byte
+	if len(nibbles)%2 == 1 {
+		compact = append(compact, (flag+1)<<4|nibbles[0])
+		nibbles = nibbles[1:]
+	} else {
+		compact = append(compact, flag<<4)
+	}
+
+	for i := 0; i < len(nibbles); i += 2 {
+		compact = append(compact, nibbles[i]<<4|nibbles[i+1])
+	}
+
+	return compact
+}
+
+func mustEncodeRLP(v interface{}) []byte {
+	enc, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		// only byte slices and lists of them are encoded
+		panic(err)
+	}
+
+	return enc
+}
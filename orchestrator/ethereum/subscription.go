@@ -0,0 +1,104 @@
+package ethereum
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+// ErrSubscriptionsDisabled is returned by SubscribePeggyEvents if no websocket endpoint is configured
+var ErrSubscriptionsDisabled = errors.New("no websocket endpoint configured for Ethereum subscriptions")
+
+// SubscribePeggyEvents opens a new websocket connection and subscribes to new block headers and to the claimable
+// Peggy events. Logs are delivered as soon as they are included in a block, so it is up to the caller to wait
+// for enough confirmations. The returned subscription fails as soon as either of the two subscriptions
+// (or the connection) fails, so the caller can resubscribe.
+func (n *network) SubscribePeggyEvents(ctx context.Context, heads chan<- *gethtypes.Header, logs chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	if n.wsURL == "" {
+		return nil, ErrSubscriptionsDisabled
+	}
+
+	client, err := ethclient.DialContext(ctx, n.wsURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to ethereum websocket: %s", n.wsURL)
+	}
+
+	headsSub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "failed to subscribe to new heads")
+	}
+
+	logsSub, err := client.SubscribeFilterLogs(ctx, peggyEventsQuery(n.Address()), logs)
+	if err != nil {
+		headsSub.Unsubscribe()
+		client.Close()
+		return nil, errors.Wrap(err, "failed to subscribe to Peggy logs")
+	}
+
+	return newJointSubscription(client, headsSub, logsSub), nil
+}
+
+// jointSubscription merges several subscriptions sharing a single connection
+type jointSubscription struct {
+	client *ethclient.Client
+	subs   []ethereum.Subscription
+
+	errCh    chan error
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newJointSubscription(client *ethclient.Client, subs ...ethereum.Subscription) *jointSubscription {
+	s := &jointSubscription{
+		client: client,
+		subs:   subs,
+		errCh:  make(chan error, 1),
+		quit:   make(chan struct{}),
+	}
+
+	for _, sub := range subs {
+		go s.forwardErr(sub)
+	}
+
+	return s
+}
+
+func (s *jointSubscription) forwardErr(sub ethereum.Subscription) {
+	select {
+	case err, ok := <-sub.Err():
+		if !ok {
+			// unsubscribed
+			return
+		}
+
+		if err == nil {
+			err = errors.New("subscription closed")
+		}
+
+		select {
+		case s.errCh <- err:
+		default:
+		}
+	case <-s.quit:
+	}
+}
+
+func (s *jointSubscription) Err() <-chan error {
+	return s.errCh
+}
+
+func (s *jointSubscription) Unsubscribe() {
+	s.quitOnce.Do(func() {
+		close(s.quit)
+		for _, sub := range s.subs {
+			sub.Unsubscribe()
+		}
+
+		s.client.Close()
+	})
+}
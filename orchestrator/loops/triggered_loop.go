@@ -0,0 +1,46 @@
+package loops
+
+import (
+	"context"
+	"time"
+)
+
+// RunTriggeredLoop is like RunLoop, but also runs the function as soon as a signal is received from trigger.
+// The interval is counted from the last execution, so the loop serves as a fallback in case no signals arrive.
+func RunTriggeredLoop(ctx context.Context, interval time.Duration, trigger <-chan struct{}, fn func() error) (err error) {
+	defer panicRecover(&err)
+
+	delayTimer := time.NewTimer(0)
+	defer delayTimer.Stop()
+
+	for {
+		select {
+		case <-delayTimer.C:
+		case <-trigger:
+			if !delayTimer.Stop() {
+				select {
+				case <-delayTimer.C:
+				default:
+				}
+			}
+		case <-ctx.Done():
+			return nil
+		}
+
+		var start = time.Now()
+		if fnErr := fn(); fnErr != nil {
+			if fnErr == ErrGracefulStop {
+				return nil
+			}
+
+			return fnErr
+		}
+
+		if elapsed := time.Since(start); elapsed >= interval {
+			// in case of an overlap, use just interval
+			delayTimer.Reset(interval)
+		} else {
+			delayTimer.Reset(interval - elapsed)
+		}
+	}
+}
@@ -2,15 +2,21 @@ package orchestrator
 
 import (
 	"context"
+	"io"
 	"math/big"
 	"time"
 
 	cometrpc "github.com/cometbft/cometbft/rpc/core/types"
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	goethereum "github.com/ethereum/go-ethereum"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
 	log "github.com/xlab/suplog"
 
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
 	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
 	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
 )
@@ -23,6 +29,86 @@ func (p MockPriceFeed) QueryUSDPrice(address gethcommon.Address) (float64, error
 	return p.QueryUSDPriceFn(address)
 }
 
+type MockSubscription struct {
+	ErrCh chan error
+}
+
+func (s MockSubscription) Err() <-chan error {
+	return s.ErrCh
+}
+
+func (s MockSubscription) Unsubscribe() {}
+
+type MockStore struct {
+	OracleCheckpointFn        func(gethcommon.Address) (*state.OracleCheckpoint, error)
+	SetOracleCheckpointFn     func(gethcommon.Address, *state.OracleCheckpoint) error
+	CheckAndRecordSignatureFn func(state.SignedDigest) error
+	RecordOutflowFn           func(state.OutflowRecord) error
+	OutflowsFn                func(string, time.Time) ([]state.OutflowRecord, error)
+	BatchCreatorProgressFn    func() (*state.BatchCreatorProgress, error)
+	SetBatchCreatorProgressFn func(*state.BatchCreatorProgress) error
+}
+
+func (s MockStore) OracleCheckpoint(peggyContract gethcommon.Address) (*state.OracleCheckpoint, error) {
+	return s.OracleCheckpointFn(peggyContract)
+}
+
+func (s MockStore) SetOracleCheckpoint(peggyContract gethcommon.Address, cp *state.OracleCheckpoint) error {
+	return s.SetOracleCheckpointFn(peggyContract, cp)
+}
+
+func (s MockStore) CheckAndRecordSignature(d state.SignedDigest) error {
+	return s.CheckAndRecordSignatureFn(d)
+}
+
+func (s MockStore) SignedDigests() ([]state.SignedDigest, error) {
+	return nil, nil
+}
+
+func (s MockStore) ExportSignedDigests(_ io.Writer) error {
+	return nil
+}
+
+func (s MockStore) ImportSignedDigests(_ io.Reader) (int, error) {
+	return 0, nil
+}
+
+func (s MockStore) RecordOutflow(r state.OutflowRecord) error {
+	if s.RecordOutflowFn == nil {
+		return nil
+	}
+
+	return s.RecordOutflowFn(r)
+}
+
+func (s MockStore) Outflows(tokenContract string, since time.Time) ([]state.OutflowRecord, error) {
+	if s.OutflowsFn == nil {
+		return nil, nil
+	}
+
+	return s.OutflowsFn(tokenContract, since)
+}
+
+func (s MockStore) BatchCreatorProgress() (*state.BatchCreatorProgress, error) {
+	if s.BatchCreatorProgressFn == nil {
+		return nil, state.ErrNotFound
+	}
+
+	return s.BatchCreatorProgressFn()
+}
+
+func (s MockStore) SetBatchCreatorProgress(p *state.BatchCreatorProgress) error {
+	if s.SetBatchCreatorProgressFn == nil {
+		return nil
+	}
+
+	return s.SetBatchCreatorProgressFn(p)
+}
+
+func (s MockStore) Close() error {
+	return nil
+}
+
 type MockCosmosNetwork struct {
 	PeggyParamsFn                      func(ctx context.Context) (*peggytypes.Params, error)
 	LastClaimEventByAddrFn             func(ctx context.Context, address cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error)
@@ -35,10 +121,12 @@ type MockCosmosNetwork struct {
 	OldestUnsignedTransactionBatchFn   func(ctx context.Context, address cosmostypes.AccAddress) (*peggytypes.OutgoingTxBatch, error)
 	LatestTransactionBatchesFn         func(ctx context.Context) ([]*peggytypes.OutgoingTxBatch, error)
 	UnbatchedTokensWithFeesFn          func(ctx context.Context) ([]*peggytypes.BatchFees, error)
+	UnbatchedTransfersFn               func(ctx context.Context) ([]*peggytypes.OutgoingTransferTx, error)
 	TransactionBatchSignaturesFn       func(ctx context.Context, uint642 uint64, address gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error)
 	UpdatePeggyOrchestratorAddressesFn func(ctx context.Context, address gethcommon.Address, address2 cosmostypes.Address) error
 	SendValsetConfirmFn                func(ctx context.Context, address gethcommon.Address, hash gethcommon.Hash, valset *peggytypes.Valset) error
 	SendBatchConfirmFn                 func(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) error
+	SendBatchConfirmsFn                func(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error)
 	SendRequestBatchFn                 func(ctx context.Context, denom string) error
 	SendToEthFn                        func(ctx context.Context, destination gethcommon.Address, amount, fee cosmostypes.Coin) error
 	SendOldDepositClaimFn              func(ctx context.Context, deposit *peggyevents.PeggySendToCosmosEvent) error
@@ -46,8 +134,12 @@ type MockCosmosNetwork struct {
 	SendWithdrawalClaimFn              func(ctx context.Context, withdrawal *peggyevents.PeggyTransactionBatchExecutedEvent) error
 	SendValsetClaimFn                  func(ctx context.Context, vs *peggyevents.PeggyValsetUpdatedEvent) error
 	SendERC20DeployedClaimFn           func(ctx context.Context, erc20 *peggyevents.PeggyERC20DeployedEvent) error
+	SendEthereumClaimsFn               func(ctx context.Context, claims []peggytypes.EthereumClaim) error
 	GetBlockFn                         func(ctx context.Context, height int64) (*cometrpc.ResultBlock, error)
 	GetLatestBlockHeightFn             func(ctx context.Context) (int64, error)
+	GetValidatorSetFn                  func(ctx context.Context, height int64) (*cometrpc.ResultValidators, error)
+	GetDelegateEthereumAddressFn       func(ctx context.Context, validator cosmostypes.AccAddress) (gethcommon.Address, error)
+	ValidatorsFn                       func(ctx context.Context, height int64) ([]stakingtypes.Validator, error)
 }
 
 func (n MockCosmosNetwork) PeggyParams(ctx context.Context) (*peggytypes.Params, error) {
@@ -83,6 +175,11 @@ func (n MockCosmosNetwork) AllValsetConfirms(ctx context.Context, nonce uint64)
 }
 
 func (n MockCosmosNetwork) OldestUnsignedTransactionBatch(ctx context.Context, valAccountAddress cosmostypes.AccAddress) (*peggytypes.OutgoingTxBatch, error) {
+	// some batch awaits our confirmation
+	if n.OldestUnsignedTransactionBatchFn == nil {
+		return &peggytypes.OutgoingTxBatch{}, nil
+	}
+
 	return n.OldestUnsignedTransactionBatchFn(ctx, valAccountAddress)
 }
 
@@ -94,6 +191,10 @@ func (n MockCosmosNetwork) UnbatchedTokensWithFees(ctx context.Context) ([]*pegg
 	return n.UnbatchedTokensWithFeesFn(ctx)
 }
 
+func (n MockCosmosNetwork) UnbatchedTransfers(ctx context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+	return n.UnbatchedTransfersFn(ctx)
+}
+
 func (n MockCosmosNetwork) TransactionBatchSignatures(ctx context.Context, nonce uint64, tokenContract gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
 	return n.TransactionBatchSignaturesFn(ctx, nonce, tokenContract)
 }
@@ -110,6 +211,10 @@ func (n MockCosmosNetwork) SendBatchConfirm(ctx context.Context, ethFrom gethcom
 	return n.SendBatchConfirmFn(ctx, ethFrom, peggyID, batch)
 }
 
+func (n MockCosmosNetwork) SendBatchConfirms(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error) {
+	return n.SendBatchConfirmsFn(ctx, ethFrom, peggyID, batches)
+}
+
 func (n MockCosmosNetwork) SendRequestBatch(ctx context.Context, denom string) error {
 	return n.SendRequestBatchFn(ctx, denom)
 }
@@ -138,13 +243,16 @@ func (n MockCosmosNetwork) SendERC20DeployedClaim(ctx context.Context, erc20 *pe
 	return n.SendERC20DeployedClaimFn(ctx, erc20)
 }
 
+func (n MockCosmosNetwork) SendEthereumClaims(ctx context.Context, claims []peggytypes.EthereumClaim) error {
+	return n.SendEthereumClaimsFn(ctx, claims)
+}
+
 func (n MockCosmosNetwork) GetBlock(ctx context.Context, height int64) (*cometrpc.ResultBlock, error) {
 	return n.GetBlockFn(ctx, height)
 }
 
 func (n MockCosmosNetwork) GetLatestBlockHeight(ctx context.Context) (int64, error) {
-	//TODO implement me
-	panic("implement me")
+	return n.GetLatestBlockHeightFn(ctx)
 }
 
 func (n MockCosmosNetwork) GetTxs(ctx context.Context, block *cometrpc.ResultBlock) ([]*cometrpc.ResultTx, error) {
@@ -153,55 +261,103 @@ func (n MockCosmosNetwork) GetTxs(ctx context.Context, block *cometrpc.ResultBlo
 }
 
 func (n MockCosmosNetwork) GetValidatorSet(ctx context.Context, height int64) (*cometrpc.ResultValidators, error) {
-	//TODO implement me
-	panic("implement me")
+	return n.GetValidatorSetFn(ctx, height)
+}
+
+func (n MockCosmosNetwork) GetDelegateEthereumAddress(ctx context.Context, validator cosmostypes.AccAddress) (gethcommon.Address, error) {
+	return n.GetDelegateEthereumAddressFn(ctx, validator)
+}
+
+func (n MockCosmosNetwork) Validators(ctx context.Context, height int64) ([]stakingtypes.Validator, error) {
+	return n.ValidatorsFn(ctx, height)
 }
 
 type MockEthereumNetwork struct {
-	GetHeaderByNumberFn                 func(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
-	GetPeggyIDFn                        func(ctx context.Context) (gethcommon.Hash, error)
-	GetSendToCosmosEventsFn             func(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToCosmosEvent, error)
-	GetSendToInjectiveEventsFn          func(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error)
-	GetPeggyERC20DeployedEventsFn       func(startBlock, endBlock uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error)
-	GetValsetUpdatedEventsFn            func(startBlock, endBlock uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error)
-	GetTransactionBatchExecutedEventsFn func(startBlock, endBlock uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error)
-	GetValsetNonceFn                    func(ctx context.Context) (*big.Int, error)
-	SendEthValsetUpdateFn               func(ctx context.Context, oldValset *peggytypes.Valset, newValset *peggytypes.Valset, confirms []*peggytypes.MsgValsetConfirm) (*gethcommon.Hash, error)
-	GetTxBatchNonceFn                   func(ctx context.Context, erc20ContractAddress gethcommon.Address) (*big.Int, error)
-	SendTransactionBatchFn              func(ctx context.Context, currentValset *peggytypes.Valset, batch *peggytypes.OutgoingTxBatch, confirms []*peggytypes.MsgConfirmBatch) (*gethcommon.Hash, error)
-	TokenDecimalsFn                     func(ctx context.Context, address gethcommon.Address) (uint8, error)
+	GetHeaderByNumberFn           func(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	GetBlockHashFn                func(ctx context.Context, number *big.Int) (gethcommon.Hash, error)
+	GetPeggyIDFn                  func(ctx context.Context) (gethcommon.Hash, error)
+	LogsWindowFn                  func() uint64
+	GetPeggyEventsFn              func(ctx context.Context, startBlock, endBlock uint64) ([]ethereum.PeggyEvent, error)
+	GetValsetUpdatedEventsFn      func(startBlock, endBlock uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error)
+	SubscribePeggyEventsFn        func(ctx context.Context, heads chan<- *gethtypes.Header, logs chan<- gethtypes.Log) (goethereum.Subscription, error)
+	VerifyPeggyEventsFn           func(ctx context.Context, logs []gethtypes.Log) (int, error)
+	GetLastEventNonceFn           func(ctx context.Context, blockNumber *big.Int) (*big.Int, error)
+	GetValsetNonceFn              func(ctx context.Context) (*big.Int, error)
+	SendEthValsetUpdateFn         func(ctx context.Context, oldValset *peggytypes.Valset, newValset *peggytypes.Valset, confirms []*peggytypes.MsgValsetConfirm) (*gethcommon.Hash, error)
+	GetTxBatchNonceFn             func(ctx context.Context, erc20ContractAddress gethcommon.Address) (*big.Int, error)
+	SendTransactionBatchFn        func(ctx context.Context, currentValset *peggytypes.Valset, batch *peggytypes.OutgoingTxBatch, confirms []*peggytypes.MsgConfirmBatch) (*gethcommon.Hash, error)
+	EstimateTransactionBatchGasFn func(ctx context.Context, currentValset *peggytypes.Valset, batch *peggytypes.OutgoingTxBatch, confirms []*peggytypes.MsgConfirmBatch) (uint64, error)
+	TokenDecimalsFn               func(ctx context.Context, address gethcommon.Address) (uint8, error)
+	SuggestGasPriceFn             func(ctx context.Context) (*big.Int, error)
 }
 
 func (n MockEthereumNetwork) GetHeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
 	return n.GetHeaderByNumberFn(ctx, number)
 }
 
+func (n MockEthereumNetwork) GetBlockHash(ctx context.Context, number *big.Int) (gethcommon.Hash, error) {
+	// hash the mocked header unless the test mocks block hashes
+	if n.GetBlockHashFn == nil {
+		h, err := n.GetHeaderByNumberFn(ctx, number)
+		if err != nil {
+			return gethcommon.Hash{}, err
+		}
+
+		return h.Hash(), nil
+	}
+
+	return n.GetBlockHashFn(ctx, number)
+}
+
 func (n MockEthereumNetwork) TokenDecimals(ctx context.Context, tokenContract gethcommon.Address) (uint8, error) {
 	return n.TokenDecimalsFn(ctx, tokenContract)
 }
 
+func (n MockEthereumNetwork) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return n.SuggestGasPriceFn(ctx)
+}
+
 func (n MockEthereumNetwork) GetPeggyID(ctx context.Context) (gethcommon.Hash, error) {
 	return n.GetPeggyIDFn(ctx)
 }
 
-func (n MockEthereumNetwork) GetSendToCosmosEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-	return n.GetSendToCosmosEventsFn(startBlock, endBlock)
+func (n MockEthereumNetwork) LogsWindow() uint64 {
+	// the initial eth_getLogs window unless the test mocks it
+	if n.LogsWindowFn == nil {
+		return 2000
+	}
+
+	return n.LogsWindowFn()
+}
+
+func (n MockEthereumNetwork) GetPeggyEvents(ctx context.Context, startBlock, endBlock uint64) ([]ethereum.PeggyEvent, error) {
+	return n.GetPeggyEventsFn(ctx, startBlock, endBlock)
 }
 
-func (n MockEthereumNetwork) GetSendToInjectiveEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
-	return n.GetSendToInjectiveEventsFn(startBlock, endBlock)
+func (n MockEthereumNetwork) SubscribePeggyEvents(ctx context.Context, heads chan<- *gethtypes.Header, logs chan<- gethtypes.Log) (goethereum.Subscription, error) {
+	return n.SubscribePeggyEventsFn(ctx, heads, logs)
 }
 
-func (n MockEthereumNetwork) GetPeggyERC20DeployedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-	return n.GetPeggyERC20DeployedEventsFn(startBlock, endBlock)
+func (n MockEthereumNetwork) VerifyPeggyEvents(ctx context.Context, logs []gethtypes.Log) (int, error) {
+	// no verifying providers configured
+	if n.VerifyPeggyEventsFn == nil {
+		return len(logs), nil
+	}
+
+	return n.VerifyPeggyEventsFn(ctx, logs)
 }
 
 func (n MockEthereumNetwork) GetValsetUpdatedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
 	return n.GetValsetUpdatedEventsFn(startBlock, endBlock)
 }
 
-func (n MockEthereumNetwork) GetTransactionBatchExecutedEvents(startBlock, endBlock uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-	return n.GetTransactionBatchExecutedEventsFn(startBlock, endBlock)
+func (n MockEthereumNetwork) GetLastEventNonce(ctx context.Context, blockNumber *big.Int) (*big.Int, error) {
+	// no archive node
+	if n.GetLastEventNonceFn == nil {
+		return nil, errors.New("missing trie node")
+	}
+
+	return n.GetLastEventNonceFn(ctx, blockNumber)
 }
 
 func (n MockEthereumNetwork) GetValsetNonce(ctx context.Context) (*big.Int, error) {
@@ -220,6 +376,10 @@ func (n MockEthereumNetwork) SendTransactionBatch(ctx context.Context, currentVa
 	return n.SendTransactionBatchFn(ctx, currentValset, batch, confirms)
 }
 
+func (n MockEthereumNetwork) EstimateTransactionBatchGas(ctx context.Context, currentValset *peggytypes.Valset, batch *peggytypes.OutgoingTxBatch, confirms []*peggytypes.MsgConfirmBatch) (uint64, error) {
+	return n.EstimateTransactionBatchGasFn(ctx, currentValset, batch, confirms)
+}
+
 var (
 	DummyLog = DummyLogger{}
 )
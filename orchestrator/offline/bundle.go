@@ -0,0 +1,269 @@
+package offline
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	ethpeggy "github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// BundleVersion is the version of the bundle file format
+const BundleVersion = "1"
+
+// Bundle carries the valsets and batches pending confirmation by an orchestrator between the online host that
+// exports them, the offline host holding the Ethereum delegate key and back.
+type Bundle struct {
+	Version      string          `json:"version"`
+	ChainID      string          `json:"chain_id"`
+	PeggyID      gethcommon.Hash `json:"peggy_id"`
+	Orchestrator string          `json:"orchestrator"`
+	ExportedAt   time.Time       `json:"exported_at"`
+
+	// EthSigner and SignedAt are set once the bundle is signed
+	EthSigner *gethcommon.Address `json:"eth_signer,omitempty"`
+	SignedAt  *time.Time          `json:"signed_at,omitempty"`
+
+	Valsets []*ValsetConfirm `json:"valsets"`
+	Batches []*BatchConfirm  `json:"batches"`
+}
+
+// ValsetConfirm is a valset along with its checkpoint digest (EncodeValsetConfirm) and signature.
+type ValsetConfirm struct {
+	Valset    *peggytypes.Valset `json:"valset"`
+	Digest    gethcommon.Hash    `json:"digest"`
+	Signature hexutil.Bytes      `json:"signature,omitempty"`
+}
+
+// BatchConfirm is a batch along with its checkpoint digest (EncodeTxBatchConfirm) and signature.
+type BatchConfirm struct {
+	Batch     *peggytypes.OutgoingTxBatch `json:"batch"`
+	Digest    gethcommon.Hash             `json:"digest"`
+	Signature hexutil.Bytes               `json:"signature,omitempty"`
+}
+
+// PeggyID returns the peggy ID of the Peggy module params as used in the checkpoints, right padded to 32 bytes.
+func PeggyID(params *peggytypes.Params) (gethcommon.Hash, error) {
+	var peggyID gethcommon.Hash
+	if len(params.PeggyId) == 0 || len(params.PeggyId) > gethcommon.HashLength {
+		return peggyID, errors.Errorf("invalid peggy ID %q", params.PeggyId)
+	}
+
+	copy(peggyID[:], params.PeggyId)
+
+	return peggyID, nil
+}
+
+// ReadBundle decodes a bundle and checks its digests.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	b := new(Bundle)
+	if err := json.NewDecoder(r).Decode(b); err != nil {
+		return nil, errors.Wrap(err, "failed to decode confirmation bundle")
+	}
+
+	if b.Version != BundleVersion {
+		return nil, errors.Errorf("unsupported confirmation bundle version %q", b.Version)
+	}
+
+	if err := b.verifyDigests(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Write encodes the bundle.
+func (b *Bundle) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(b)
+}
+
+// verifyDigests recomputes every checkpoint digest, so that a bundle edited in transit cannot get a different
+// checkpoint signed than the valset or batch it shows
+func (b *Bundle) verifyDigests() error {
+	for _, vs := range b.Valsets {
+		if vs.Valset == nil {
+			return errors.New("valset is missing")
+		}
+
+		if digest := ethpeggy.EncodeValsetConfirm(b.PeggyID, vs.Valset); digest != vs.Digest {
+			return errors.Errorf("valset nonce %d digest is %s, bundle says %s", vs.Valset.Nonce, digest.Hex(), vs.Digest.Hex())
+		}
+	}
+
+	for _, batch := range b.Batches {
+		if batch.Batch == nil {
+			return errors.New("batch is missing")
+		}
+
+		if digest := ethpeggy.EncodeTxBatchConfirm(b.PeggyID, batch.Batch); digest != batch.Digest {
+			return errors.Errorf("token %s batch nonce %d digest is %s, bundle says %s", batch.Batch.TokenContract, batch.Batch.BatchNonce, digest.Hex(), batch.Digest.Hex())
+		}
+	}
+
+	return nil
+}
+
+// Sign signs every checkpoint of the bundle with the Ethereum key. Each checkpoint is first recorded in the
+// slashing protection database, if not nil, and nothing is signed if any of them conflicts with a checkpoint
+// signed before.
+func (b *Bundle) Sign(ethFrom gethcommon.Address, signer peggy.ConfirmSigner, protection state.SlashingProtection) error {
+	if err := b.verifyDigests(); err != nil {
+		return err
+	}
+
+	if protection != nil {
+		digests := make([]state.SignedDigest, 0, len(b.Valsets)+len(b.Batches))
+		for _, vs := range b.Valsets {
+			digests = append(digests, state.SignedDigest{Kind: state.ValsetSignature, PeggyID: b.PeggyID, Nonce: vs.Valset.Nonce, Digest: vs.Digest})
+		}
+
+		for _, batch := range b.Batches {
+			digests = append(digests, state.SignedDigest{Kind: state.BatchSignature, PeggyID: b.PeggyID, Nonce: batch.Batch.BatchNonce, Digest: batch.Digest, TokenContract: batch.Batch.TokenContract})
+		}
+
+		for _, d := range digests {
+			if err := protection.CheckAndRecordSignature(d); err != nil {
+				return errors.Wrap(err, "refusing to sign bundle")
+			}
+		}
+	}
+
+	for _, vs := range b.Valsets {
+		digest, sig, err := signer.SignValsetConfirm(ethFrom, b.PeggyID, vs.Valset)
+		if err != nil {
+			return errors.Wrapf(err, "failed to sign valset nonce %d", vs.Valset.Nonce)
+		}
+
+		if digest != vs.Digest {
+			return errors.Errorf("signed valset nonce %d digest %s, expected %s", vs.Valset.Nonce, digest.Hex(), vs.Digest.Hex())
+		}
+
+		vs.Signature = sig
+	}
+
+	for _, batch := range b.Batches {
+		digest, sig, err := signer.SignBatchConfirm(ethFrom, b.PeggyID, batch.Batch)
+		if err != nil {
+			return errors.Wrapf(err, "failed to sign token %s batch nonce %d", batch.Batch.TokenContract, batch.Batch.BatchNonce)
+		}
+
+		if digest != batch.Digest {
+			return errors.Errorf("signed token %s batch nonce %d digest %s, expected %s", batch.Batch.TokenContract, batch.Batch.BatchNonce, digest.Hex(), batch.Digest.Hex())
+		}
+
+		batch.Signature = sig
+	}
+
+	signedAt := time.Now().UTC()
+	b.EthSigner, b.SignedAt = &ethFrom, &signedAt
+
+	return nil
+}
+
+// VerifySignatures checks that every checkpoint of the bundle is signed by its Ethereum signer.
+func (b *Bundle) VerifySignatures() error {
+	if b.EthSigner == nil {
+		return errors.New("confirmation bundle is not signed")
+	}
+
+	for _, vs := range b.Valsets {
+		if err := verifySignature(*b.EthSigner, vs.Digest, vs.Signature); err != nil {
+			return errors.Wrapf(err, "invalid signature of valset nonce %d", vs.Valset.Nonce)
+		}
+	}
+
+	for _, batch := range b.Batches {
+		if err := verifySignature(*b.EthSigner, batch.Digest, batch.Signature); err != nil {
+			return errors.Wrapf(err, "invalid signature of token %s batch nonce %d", batch.Batch.TokenContract, batch.Batch.BatchNonce)
+		}
+	}
+
+	return nil
+}
+
+func verifySignature(signer gethcommon.Address, digest gethcommon.Hash, sig []byte) error {
+	if len(sig) != crypto.SignatureLength {
+		return errors.New("signature is missing")
+	}
+
+	// personal sign returns V as 27 or 28
+	sig = gethcommon.CopyBytes(sig)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(accounts.TextHash(digest.Bytes()), sig)
+	if err != nil {
+		return err
+	}
+
+	if recovered := crypto.PubkeyToAddress(*pubKey); recovered != signer {
+		return errors.Errorf("signed by %s, not %s", recovered.Hex(), signer.Hex())
+	}
+
+	return nil
+}
+
+// ConfirmSigner returns the signatures of the bundle to the broadcast client, in place of the Ethereum key.
+func (b *Bundle) ConfirmSigner() peggy.ConfirmSigner {
+	return bundleSigner{b}
+}
+
+type bundleSigner struct {
+	*Bundle
+}
+
+func (s bundleSigner) SignValsetConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) (gethcommon.Hash, []byte, error) {
+	if err := s.checkSigner(ethFrom, peggyID); err != nil {
+		return gethcommon.Hash{}, nil, err
+	}
+
+	digest := ethpeggy.EncodeValsetConfirm(peggyID, valset)
+	for _, vs := range s.Valsets {
+		if vs.Digest == digest {
+			return digest, vs.Signature, nil
+		}
+	}
+
+	return gethcommon.Hash{}, nil, errors.Errorf("valset nonce %d is not signed in the bundle", valset.Nonce)
+}
+
+func (s bundleSigner) SignBatchConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) (gethcommon.Hash, []byte, error) {
+	if err := s.checkSigner(ethFrom, peggyID); err != nil {
+		return gethcommon.Hash{}, nil, err
+	}
+
+	digest := ethpeggy.EncodeTxBatchConfirm(peggyID, batch)
+	for _, b := range s.Batches {
+		if b.Digest == digest {
+			return digest, b.Signature, nil
+		}
+	}
+
+	return gethcommon.Hash{}, nil, errors.Errorf("token %s batch nonce %d is not signed in the bundle", batch.TokenContract, batch.BatchNonce)
+}
+
+func (s bundleSigner) checkSigner(ethFrom gethcommon.Address, peggyID gethcommon.Hash) error {
+	switch {
+	case s.EthSigner == nil:
+		return errors.New("confirmation bundle is not signed")
+	case *s.EthSigner != ethFrom:
+		return errors.Errorf("confirmation bundle is signed by %s, not %s", s.EthSigner.Hex(), ethFrom.Hex())
+	case s.PeggyID != peggyID:
+		return errors.Errorf("confirmation bundle is signed for peggy ID %s, not %s", s.PeggyID.Hex(), peggyID.Hex())
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+const (
+	chainID = "injective-888"
+	tokenA  = "0x36B3D7ACe7201E28040eFf30e815290D7b37ffaD"
+	tokenB  = "0xF955C57f9EA9Dc8781965FEaE0b6A2acE2BAD6f3"
+)
+
+var orchestrator = cosmostypes.AccAddress("orchestrator")
+
+// mockNetwork returns the valsets and batches not confirmed by the orchestrator yet, and confirms the ones sent
+// with the signatures of the confirm signer, like the broadcast client
+type mockNetwork struct {
+	valsets []*peggytypes.Valset
+	batches []*peggytypes.OutgoingTxBatch
+	signer  peggy.ConfirmSigner
+
+	confirmedValsets map[uint64][]byte
+	confirmedBatches map[string][]byte
+}
+
+func (n *mockNetwork) OldestUnsignedValsets(_ context.Context, _ cosmostypes.AccAddress) ([]*peggytypes.Valset, error) {
+	var unsigned []*peggytypes.Valset
+	for _, vs := range n.valsets {
+		if _, ok := n.confirmedValsets[vs.Nonce]; !ok {
+			unsigned = append(unsigned, vs)
+		}
+	}
+
+	return unsigned, nil
+}
+
+func (n *mockNetwork) LatestTransactionBatches(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+	return n.batches, nil
+}
+
+func (n *mockNetwork) TransactionBatchSignatures(_ context.Context, nonce uint64, tokenContract gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
+	if _, ok := n.confirmedBatches[batchKey(tokenContract.Hex(), nonce)]; ok {
+		return []*peggytypes.MsgConfirmBatch{{Orchestrator: orchestrator.String()}}, nil
+	}
+
+	return []*peggytypes.MsgConfirmBatch{{Orchestrator: cosmostypes.AccAddress("other").String()}}, nil
+}
+
+func (n *mockNetwork) SendValsetConfirm(_ context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) error {
+	_, sig, err := n.signer.SignValsetConfirm(ethFrom, peggyID, valset)
+	if err != nil {
+		return err
+	}
+
+	n.confirmedValsets[valset.Nonce] = sig
+
+	return nil
+}
+
+func (n *mockNetwork) SendBatchConfirms(_ context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error) {
+	for i, batch := range batches {
+		_, sig, err := n.signer.SignBatchConfirm(ethFrom, peggyID, batch)
+		if err != nil {
+			return batches[:i], err
+		}
+
+		n.confirmedBatches[batchKey(batch.TokenContract, batch.BatchNonce)] = sig
+	}
+
+	return batches, nil
+}
+
+func batchKey(token string, nonce uint64) string {
+	return fmt.Sprintf("%s/%d", gethcommon.HexToAddress(token).Hex(), nonce)
+}
+
+func testBatch(token string, nonce uint64) *peggytypes.OutgoingTxBatch {
+	return &peggytypes.OutgoingTxBatch{
+		BatchNonce:    nonce,
+		BatchTimeout:  1000,
+		TokenContract: token,
+		Transactions: []*peggytypes.OutgoingTransferTx{{
+			Id:          nonce,
+			Sender:      orchestrator.String(),
+			DestAddress: "0x0000000000000000000000000000000000000003",
+			Erc20Token:  &peggytypes.ERC20Token{Contract: token, Amount: sdkmath.NewInt(1000)},
+			Erc20Fee:    &peggytypes.ERC20Token{Contract: token, Amount: sdkmath.NewInt(10)},
+		}},
+	}
+}
+
+func testNetwork() *mockNetwork {
+	return &mockNetwork{
+		valsets: []*peggytypes.Valset{{
+			Nonce:        3,
+			Height:       100,
+			RewardAmount: sdkmath.ZeroInt(),
+			Members:      []*peggytypes.BridgeValidator{{EthereumAddress: "0x0000000000000000000000000000000000000001", Power: 1 << 31}},
+		}},
+		batches:          []*peggytypes.OutgoingTxBatch{testBatch(tokenB, 2), testBatch(tokenA, 5), testBatch(tokenA, 4)},
+		confirmedValsets: map[uint64][]byte{},
+		confirmedBatches: map[string][]byte{batchKey(tokenA, 4): nil},
+	}
+}
+
+func TestPeggyID(t *testing.T) {
+	peggyID, err := PeggyID(&peggytypes.Params{PeggyId: "injective-peggyid"})
+	require.NoError(t, err)
+	assert.Equal(t, gethcommon.HexToHash("0x696e6a6563746976652d70656767796964000000000000000000000000000000"), peggyID)
+
+	_, err = PeggyID(&peggytypes.Params{})
+	assert.Error(t, err)
+}
+
+func TestBundle(t *testing.T) {
+	ctx := context.Background()
+	net := testNetwork()
+
+	peggyID, err := PeggyID(peggytypes.DefaultParams())
+	require.NoError(t, err)
+
+	// online: export
+	exported, err := Export(ctx, net, chainID, peggyID, orchestrator)
+	require.NoError(t, err)
+	require.Len(t, exported.Valsets, 1)
+	require.Len(t, exported.Batches, 2)
+	assert.Equal(t, uint64(5), exported.Batches[0].Batch.BatchNonce)
+	assert.Equal(t, uint64(2), exported.Batches[1].Batch.BatchNonce)
+
+	var file bytes.Buffer
+	require.NoError(t, exported.Write(&file))
+
+	// offline: sign
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	ethFrom := crypto.PubkeyToAddress(key.PublicKey)
+	personalSignFn, err := keystore.PrivateKeyPersonalSignFn(key)
+	require.NoError(t, err)
+
+	bundle, err := ReadBundle(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+	assert.Error(t, bundle.VerifySignatures())
+
+	store, err := state.NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, bundle.Sign(ethFrom, peggy.PersonalSignConfirmSigner(personalSignFn), store))
+	require.NoError(t, bundle.VerifySignatures())
+
+	signed, err := store.SignedDigests()
+	require.NoError(t, err)
+	assert.Len(t, signed, 3)
+
+	file.Reset()
+	require.NoError(t, bundle.Write(&file))
+
+	// online: import
+	bundle, err = ReadBundle(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+
+	net.signer = bundle.ConfirmSigner()
+
+	_, _, err = Import(ctx, net, bundle, cosmostypes.AccAddress("other"))
+	assert.Error(t, err)
+
+	valsets, batches, err := Import(ctx, net, bundle, orchestrator)
+	require.NoError(t, err)
+	assert.Equal(t, 1, valsets)
+	assert.Equal(t, 2, batches)
+	assert.Equal(t, []byte(bundle.Valsets[0].Signature), net.confirmedValsets[3])
+	assert.Equal(t, []byte(bundle.Batches[0].Signature), net.confirmedBatches[batchKey(tokenA, 5)])
+
+	// confirmations already sent are skipped
+	valsets, batches, err = Import(ctx, net, bundle, orchestrator)
+	require.NoError(t, err)
+	assert.Zero(t, valsets)
+	assert.Zero(t, batches)
+}
+
+func TestBundleTampering(t *testing.T) {
+	ctx := context.Background()
+
+	peggyID, err := PeggyID(peggytypes.DefaultParams())
+	require.NoError(t, err)
+
+	exported, err := Export(ctx, testNetwork(), chainID, peggyID, orchestrator)
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	personalSignFn, err := keystore.PrivateKeyPersonalSignFn(key)
+	require.NoError(t, err)
+
+	t.Run("edited batch", func(t *testing.T) {
+		var file bytes.Buffer
+		require.NoError(t, exported.Write(&file))
+
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(file.Bytes(), &raw))
+
+		batch := raw["batches"].([]interface{})[0].(map[string]interface{})["batch"].(map[string]interface{})
+		batch["transactions"].([]interface{})[0].(map[string]interface{})["dest_address"] = "0x0000000000000000000000000000000000000bad"
+
+		edited, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		_, err = ReadBundle(bytes.NewReader(edited))
+		assert.ErrorContains(t, err, "digest")
+	})
+
+	t.Run("conflicting checkpoint", func(t *testing.T) {
+		store, err := state.NewStore(t.TempDir())
+		require.NoError(t, err)
+		defer store.Close()
+
+		require.NoError(t, store.CheckAndRecordSignature(state.SignedDigest{Kind: state.BatchSignature, PeggyID: peggyID, Nonce: 2, Digest: gethcommon.HexToHash("0x01")}))
+
+		err = exported.Sign(crypto.PubkeyToAddress(key.PublicKey), peggy.PersonalSignConfirmSigner(personalSignFn), store)
+		assert.ErrorIs(t, err, state.ErrConflictingSignature)
+		assert.Empty(t, exported.Valsets[0].Signature)
+		assert.Nil(t, exported.EthSigner)
+	})
+
+	t.Run("signature of another key", func(t *testing.T) {
+		require.NoError(t, exported.Sign(crypto.PubkeyToAddress(key.PublicKey), peggy.PersonalSignConfirmSigner(personalSignFn), nil))
+
+		other := gethcommon.HexToAddress("0x01")
+		exported.EthSigner = &other
+
+		assert.ErrorContains(t, exported.VerifySignatures(), "signed by")
+	})
+}
@@ -0,0 +1,162 @@
+package offline
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	ethpeggy "github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// Queries are the Injective queries needed to find the valsets and batches pending confirmation.
+type Queries interface {
+	OldestUnsignedValsets(ctx context.Context, valAccountAddress cosmostypes.AccAddress) ([]*peggytypes.Valset, error)
+	LatestTransactionBatches(ctx context.Context) ([]*peggytypes.OutgoingTxBatch, error)
+	TransactionBatchSignatures(ctx context.Context, nonce uint64, tokenContract gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error)
+}
+
+// Network broadcasts the confirmations of a signed bundle.
+type Network interface {
+	Queries
+	SendValsetConfirm(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) error
+	SendBatchConfirms(ctx context.Context, ethFrom gethcommon.Address, peggyID gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error)
+}
+
+// Export returns a bundle of the valsets and batches not confirmed by the orchestrator yet, batches in nonce order
+// per token.
+func Export(ctx context.Context, q Queries, chainID string, peggyID gethcommon.Hash, orchestrator cosmostypes.AccAddress) (*Bundle, error) {
+	b := &Bundle{
+		Version:      BundleVersion,
+		ChainID:      chainID,
+		PeggyID:      peggyID,
+		Orchestrator: orchestrator.String(),
+		ExportedAt:   time.Now().UTC(),
+		Valsets:      []*ValsetConfirm{},
+		Batches:      []*BatchConfirm{},
+	}
+
+	valsets, err := q.OldestUnsignedValsets(ctx, orchestrator)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get unsigned valsets")
+	}
+
+	for _, vs := range valsets {
+		b.Valsets = append(b.Valsets, &ValsetConfirm{Valset: vs, Digest: ethpeggy.EncodeValsetConfirm(peggyID, vs)})
+	}
+
+	batches, err := unsignedBatches(ctx, q, orchestrator)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, batch := range batches {
+		b.Batches = append(b.Batches, &BatchConfirm{Batch: batch, Digest: ethpeggy.EncodeTxBatchConfirm(peggyID, batch)})
+	}
+
+	return b, nil
+}
+
+// Import broadcasts the confirmations of a signed bundle that the orchestrator did not send yet. It returns the
+// number of valset and batch confirmations sent. The network must sign with b.ConfirmSigner().
+func Import(ctx context.Context, net Network, b *Bundle, orchestrator cosmostypes.AccAddress) (int, int, error) {
+	if b.Orchestrator != orchestrator.String() {
+		return 0, 0, errors.Errorf("confirmation bundle was exported for orchestrator %s, not %s", b.Orchestrator, orchestrator.String())
+	}
+
+	if err := b.VerifySignatures(); err != nil {
+		return 0, 0, err
+	}
+
+	unsignedValsets, err := net.OldestUnsignedValsets(ctx, orchestrator)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get unsigned valsets")
+	}
+
+	pendingValsets := make(map[gethcommon.Hash]bool, len(unsignedValsets))
+	for _, vs := range unsignedValsets {
+		pendingValsets[ethpeggy.EncodeValsetConfirm(b.PeggyID, vs)] = true
+	}
+
+	var sentValsets int
+	for _, vs := range b.Valsets {
+		if !pendingValsets[vs.Digest] {
+			continue
+		}
+
+		if err := net.SendValsetConfirm(ctx, *b.EthSigner, b.PeggyID, vs.Valset); err != nil {
+			return sentValsets, 0, err
+		}
+
+		sentValsets++
+	}
+
+	unsigned, err := unsignedBatches(ctx, net, orchestrator)
+	if err != nil {
+		return sentValsets, 0, err
+	}
+
+	pendingBatches := make(map[gethcommon.Hash]bool, len(unsigned))
+	for _, batch := range unsigned {
+		pendingBatches[ethpeggy.EncodeTxBatchConfirm(b.PeggyID, batch)] = true
+	}
+
+	var batches []*peggytypes.OutgoingTxBatch
+	for _, batch := range b.Batches {
+		if pendingBatches[batch.Digest] {
+			batches = append(batches, batch.Batch)
+		}
+	}
+
+	if len(batches) == 0 {
+		return sentValsets, 0, nil
+	}
+
+	sent, err := net.SendBatchConfirms(ctx, *b.EthSigner, b.PeggyID, batches)
+	if err != nil {
+		return sentValsets, len(sent), err
+	}
+
+	return sentValsets, len(sent), nil
+}
+
+func unsignedBatches(ctx context.Context, q Queries, orchestrator cosmostypes.AccAddress) ([]*peggytypes.OutgoingTxBatch, error) {
+	batches, err := q.LatestTransactionBatches(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest batches")
+	}
+
+	var unsigned []*peggytypes.OutgoingTxBatch
+	for _, batch := range batches {
+		confirms, err := q.TransactionBatchSignatures(ctx, batch.BatchNonce, gethcommon.HexToAddress(batch.TokenContract))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get batch confirmations")
+		}
+
+		confirmed := false
+		for _, confirm := range confirms {
+			if confirm.Orchestrator == orchestrator.String() {
+				confirmed = true
+				break
+			}
+		}
+
+		if !confirmed {
+			unsigned = append(unsigned, batch)
+		}
+	}
+
+	sort.Slice(unsigned, func(i, j int) bool {
+		if unsigned[i].TokenContract != unsigned[j].TokenContract {
+			return unsigned[i].TokenContract < unsigned[j].TokenContract
+		}
+
+		return unsigned[i].BatchNonce < unsigned[j].BatchNonce
+	})
+
+	return unsigned, nil
+}
@@ -2,14 +2,22 @@ package orchestrator
 
 import (
 	"context"
+	"math/big"
 	"sort"
 	"time"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	log "github.com/xlab/suplog"
 
 	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos"
+	cosmospeggy "github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum"
 	"github.com/InjectiveLabs/peggo/orchestrator/loops"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
 	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
 	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
 )
@@ -17,11 +25,6 @@ import (
 const (
 	// Minimum number of confirmations for an Ethereum block to be considered valid
 	ethBlockConfirmationDelay uint64 = 12
-
-	// Maximum block range for Ethereum event query. If the orchestrator has been offline for a long time,
-	// the oracle loop can potentially run longer than defaultLoopDur due to a surge of events. This usually happens
-	// when there are more than ~50 events to claim in a single run.
-	defaultBlocksToSearch uint64 = 2000
 )
 
 // runOracle is responsible for making sure that Ethereum events are retrieved from the Ethereum blockchain
@@ -31,11 +34,17 @@ func (s *Orchestrator) runOracle(ctx context.Context, lastObservedBlock uint64)
 		Orchestrator:            s,
 		lastObservedEthHeight:   lastObservedBlock,
 		lastResyncWithInjective: time.Now(),
+		recentBlocks:            s.loadRecentBlocks(lastObservedBlock),
 	}
 
 	s.logger.WithField("loop_duration", defaultLoopDur.String()).Debugln("starting Oracle...")
 
-	return loops.RunLoop(ctx, defaultLoopDur, func() error {
+	// claim events as soon as they are confirmed if Ethereum subscriptions are available,
+	// polling keeps running in the background to fill the gaps
+	trigger := make(chan struct{}, 1)
+	go oracle.watchEthereum(ctx, trigger)
+
+	return loops.RunTriggeredLoop(ctx, defaultLoopDur, trigger, func() error {
 		return oracle.observeEthEvents(ctx)
 	})
 }
@@ -44,6 +53,7 @@ type oracle struct {
 	*Orchestrator
 	lastResyncWithInjective time.Time
 	lastObservedEthHeight   uint64
+	recentBlocks            []state.BlockRef
 }
 
 func (l *oracle) Log() log.Logger {
@@ -74,37 +84,58 @@ func (l *oracle) observeEthEvents(ctx context.Context) error {
 		return nil
 	}
 
-	latestHeight, err := l.getLatestEthHeight(ctx)
+	confirmedHeader, err := l.getConfirmedEthHeader(ctx)
 	if err != nil {
 		return err
 	}
 
-	// not enough blocks on ethereum yet
-	if latestHeight <= ethBlockConfirmationDelay {
-		l.Log().Debugln("not enough blocks on Ethereum")
+	if confirmedHeader == nil {
 		return nil
 	}
 
+	if err := l.rollbackOnReorg(ctx); err != nil {
+		return err
+	}
+
 	// ensure that latest block has minimum confirmations
-	latestHeight = latestHeight - ethBlockConfirmationDelay
+	latestHeight := confirmedHeader.Number.Uint64()
 	if latestHeight <= l.lastObservedEthHeight {
 		l.Log().WithFields(log.Fields{"latest": latestHeight, "observed": l.lastObservedEthHeight}).Debugln("latest Ethereum height already observed")
 		return nil
 	}
 
-	// ensure the block range is within defaultBlocksToSearch
-	if latestHeight > l.lastObservedEthHeight+defaultBlocksToSearch {
-		latestHeight = l.lastObservedEthHeight + defaultBlocksToSearch
+	// scan several block ranges at once if the Oracle fell far behind
+	blocksToSearch := l.ethereum.LogsWindow()
+	if latestHeight-l.lastObservedEthHeight > catchUpThreshold*blocksToSearch {
+		return l.catchUp(ctx)
 	}
 
-	events, err := l.getEthEvents(ctx, l.lastObservedEthHeight, latestHeight)
+	// ensure the block range is within the eth_getLogs window of the provider. If the orchestrator has been offline
+	// for a long time, the oracle loop can potentially run longer than defaultLoopDur due to a surge of events.
+	scanEndHeader := confirmedHeader
+	if latestHeight > l.lastObservedEthHeight+blocksToSearch {
+		if scanEndHeader, err = l.getEthHeader(ctx, new(big.Int).SetUint64(l.lastObservedEthHeight+blocksToSearch)); err != nil {
+			return err
+		}
+	}
+
+	_, err = l.scanAndClaim(ctx, scanEndHeader)
+	return err
+}
+
+// scanAndClaim claims the events between the Oracle's cursor and scanEnd, scanning block ranges of the
+// eth_getLogs window concurrently. It reports whether the cursor was moved to scanEnd.
+func (l *oracle) scanAndClaim(ctx context.Context, scanEndHeader *gethtypes.Header) (bool, error) {
+	latestHeight := scanEndHeader.Number.Uint64()
+
+	events, err := l.scanEthEvents(ctx, l.lastObservedEthHeight, latestHeight)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	lastClaim, err := l.getLastClaimEvent(ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	newEvents := filterEvents(events, lastClaim.EthereumEventNonce)
@@ -114,108 +145,267 @@ func (l *oracle) observeEthEvents(ctx context.Context) error {
 
 	if len(newEvents) == 0 {
 		l.Log().WithFields(log.Fields{"last_claimed_event_nonce": lastClaim.EthereumEventNonce, "eth_block_start": l.lastObservedEthHeight, "eth_block_end": latestHeight}).Infoln("no new events on Ethereum")
-		l.lastObservedEthHeight = latestHeight
-		return nil
+		return true, l.advance(ctx, scanEndHeader, lastClaim.EthereumEventNonce)
 	}
 
 	if expected, actual := lastClaim.EthereumEventNonce+1, newEvents[0].Nonce(); expected != actual {
-		l.Log().WithFields(log.Fields{"expected": expected, "actual": actual, "last_claimed_event_nonce": lastClaim.EthereumEventNonce}).Debugln("orchestrator missed an Ethereum event. Restarting block search from last attested claim...")
-		l.lastObservedEthHeight = lastClaim.EthereumEventHeight
-		return nil
+		if located, ok := l.locateMissingEvents(ctx, lastClaim, newEvents, latestHeight); ok {
+			newEvents = located
+		} else {
+			l.Log().WithFields(log.Fields{"expected": expected, "actual": actual, "last_claimed_event_nonce": lastClaim.EthereumEventNonce}).Debugln("orchestrator missed an Ethereum event. Restarting block search from last attested claim...")
+			scanStart := l.lastObservedEthHeight
+			l.lastObservedEthHeight = lastClaim.EthereumEventHeight
+			l.forgetBlocksAbove(l.lastObservedEthHeight)
+			l.saveCheckpoint(scanStart, latestHeight, lastClaim.EthereumEventNonce)
+			return false, nil
+		}
+	}
+
+	// make sure no claim is sent for an event that was reorged out while we were scanning
+	if ok, err := l.eventsOnCanonicalChain(ctx, newEvents); err != nil {
+		return false, err
+	} else if !ok {
+		l.Log().WithFields(log.Fields{"eth_block_start": l.lastObservedEthHeight, "eth_block_end": latestHeight}).Warningln("detected Ethereum reorg during scan, rescanning block range")
+		l.reportReorg()
+		return false, nil
 	}
 
-	if err := l.sendNewEventClaims(ctx, newEvents); err != nil {
+	// only claim events confirmed by the quorum of verifying Ethereum providers (if configured)
+	verified, err := l.verifyEthEvents(ctx, newEvents)
+	if err != nil {
+		return false, err
+	}
+
+	allVerified := verified == len(newEvents)
+	newEvents = newEvents[:verified]
+
+	if len(newEvents) > 0 {
+		if err := l.sendNewEventClaims(ctx, newEvents); err != nil {
+			return false, err
+		}
+
+		l.Log().WithFields(log.Fields{"claims": len(newEvents), "eth_block_start": l.lastObservedEthHeight, "eth_block_end": latestHeight}).Infoln("sent new event claims to Injective")
+	}
+
+	if !allVerified {
+		// keep the cursor so that the unverified events are scanned and verified again
+		l.Log().WithFields(log.Fields{"verified": verified, "eth_block_start": l.lastObservedEthHeight, "eth_block_end": latestHeight}).Warningln("not all events were confirmed by Ethereum providers quorum, retrying later")
+		return false, nil
+	}
+
+	return true, l.advance(ctx, scanEndHeader, newEvents[len(newEvents)-1].Nonce())
+}
+
+// advance moves the Oracle's cursor to the end of the scanned block range.
+func (l *oracle) advance(ctx context.Context, scanEnd *gethtypes.Header, lastClaimedNonce uint64) error {
+	hash, err := l.getEthBlockHash(ctx, scanEnd.Number)
+	if err != nil {
 		return err
 	}
 
-	l.Log().WithFields(log.Fields{"claims": len(newEvents), "eth_block_start": l.lastObservedEthHeight, "eth_block_end": latestHeight}).Infoln("sent new event claims to Injective")
-	l.lastObservedEthHeight = latestHeight
+	scanStart := l.lastObservedEthHeight
+	l.lastObservedEthHeight = scanEnd.Number.Uint64()
+	l.recordBlock(state.BlockRef{Height: l.lastObservedEthHeight, Hash: hash})
+	l.saveCheckpoint(scanStart, l.lastObservedEthHeight, lastClaimedNonce)
 
 	return nil
 }
 
+// saveCheckpoint persists the Oracle's progress so that a restart can resume scanning from
+// lastObservedEthHeight instead of rebuilding it from the last claim on Injective.
+func (l *oracle) saveCheckpoint(scanStart, scanEnd, lastClaimedNonce uint64) {
+	if l.store == nil {
+		return
+	}
+
+	cp := &state.OracleCheckpoint{
+		LastObservedEthHeight: l.lastObservedEthHeight,
+		LastClaimedEventNonce: lastClaimedNonce,
+		LastScanStart:         scanStart,
+		LastScanEnd:           scanEnd,
+		UpdatedAt:             time.Now(),
+		RecentBlocks:          append([]state.BlockRef(nil), l.recentBlocks...),
+	}
+
+	if err := l.store.SetOracleCheckpoint(l.cfg.PeggyContractAddr, cp); err != nil {
+		l.Log().WithError(err).Warningln("failed to persist oracle checkpoint")
+	}
+}
+
+// resumeFromCheckpoint returns the Ethereum height from which the Oracle should resume scanning. The persisted
+// checkpoint is trusted only if it agrees with our last claim on Injective and with the current Ethereum head,
+// otherwise the fallback height (derived from the last claim) is used.
+func (s *Orchestrator) resumeFromCheckpoint(ctx context.Context, inj cosmos.Network, eth ethereum.Network, fallback uint64) uint64 {
+	if s.store == nil {
+		return fallback
+	}
+
+	logger := s.logger.WithField("loop", "Oracle")
+
+	cp, err := s.store.OracleCheckpoint(s.cfg.PeggyContractAddr)
+	if err != nil {
+		if !errors.Is(err, state.ErrNotFound) {
+			logger.WithError(err).Warningln("failed to load oracle checkpoint")
+		}
+
+		return fallback
+	}
+
+	lastClaim, err := inj.LastClaimEventByAddr(ctx, s.cfg.CosmosAddr)
+	if err != nil {
+		logger.WithError(err).Warningln("failed to get last claim event, ignoring oracle checkpoint")
+		return fallback
+	}
+
+	latestHeader, err := eth.GetHeaderByNumber(ctx, nil)
+	if err != nil {
+		logger.WithError(err).Warningln("failed to get latest ethereum header, ignoring oracle checkpoint")
+		return fallback
+	}
+
+	fields := log.Fields{
+		"checkpoint_height":        cp.LastObservedEthHeight,
+		"checkpoint_nonce":         cp.LastClaimedEventNonce,
+		"last_claimed_event_nonce": lastClaim.EthereumEventNonce,
+		"latest_eth_height":        latestHeader.Number.Uint64(),
+	}
+
+	switch {
+	case cp.LastClaimedEventNonce > lastClaim.EthereumEventNonce:
+		logger.WithFields(fields).Warningln("oracle checkpoint is ahead of claims on Injective, ignoring it")
+		return fallback
+	case cp.LastObservedEthHeight > latestHeader.Number.Uint64():
+		logger.WithFields(fields).Warningln("oracle checkpoint is ahead of Ethereum, ignoring it")
+		return fallback
+	case cp.LastObservedEthHeight < fallback:
+		logger.WithFields(fields).Debugln("oracle checkpoint is behind last claim, ignoring it")
+		return fallback
+	}
+
+	logger.WithFields(fields).Infoln("resuming from oracle checkpoint")
+
+	return cp.LastObservedEthHeight
+}
+
 func (l *oracle) getEthEvents(ctx context.Context, startBlock, endBlock uint64) ([]event, error) {
 	var events []event
 	scanEthEventsFn := func() error {
 		events = nil // clear previous result in case a retry occurred
 
-		oldDepositEvents, err := l.ethereum.GetSendToCosmosEvents(startBlock, endBlock)
+		peggyEvents, err := l.ethereum.GetPeggyEvents(ctx, startBlock, endBlock)
 		if err != nil {
 			return err
 		}
 
-		depositEvents, err := l.ethereum.GetSendToInjectiveEvents(startBlock, endBlock)
-		if err != nil {
-			return err
+		for _, e := range peggyEvents {
+			switch e := e.Event.(type) {
+			case *peggyevents.PeggySendToCosmosEvent:
+				ev := oldDeposit(*e)
+				events = append(events, &ev)
+			case *peggyevents.PeggySendToInjectiveEvent:
+				ev := deposit(*e)
+				events = append(events, &ev)
+			case *peggyevents.PeggyTransactionBatchExecutedEvent:
+				ev := withdrawal(*e)
+				events = append(events, &ev)
+			case *peggyevents.PeggyValsetUpdatedEvent:
+				ev := valsetUpdate(*e)
+				events = append(events, &ev)
+			case *peggyevents.PeggyERC20DeployedEvent:
+				ev := erc20Deployment(*e)
+				events = append(events, &ev)
+			default:
+				return errors.Errorf("unknown Peggy event type %T", e)
+			}
 		}
 
-		withdrawalEvents, err := l.ethereum.GetTransactionBatchExecutedEvents(startBlock, endBlock)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
 
-		erc20DeploymentEvents, err := l.ethereum.GetPeggyERC20DeployedEvents(startBlock, endBlock)
-		if err != nil {
-			return err
-		}
+	if err := l.retry(ctx, scanEthEventsFn); err != nil {
+		return nil, err
+	}
 
-		valsetUpdateEvents, err := l.ethereum.GetValsetUpdatedEvents(startBlock, endBlock)
-		if err != nil {
-			return err
-		}
+	return events, nil
+}
 
-		for _, e := range oldDepositEvents {
-			ev := oldDeposit(*e)
-			events = append(events, &ev)
-		}
+// verifyEthEvents returns the number of leading events confirmed by the quorum of verifying Ethereum providers.
+func (l *oracle) verifyEthEvents(ctx context.Context, events []event) (int, error) {
+	logs := make([]gethtypes.Log, len(events))
+	for i, ev := range events {
+		logs[i] = ev.RawLog()
+	}
 
-		for _, e := range depositEvents {
-			ev := deposit(*e)
-			events = append(events, &ev)
-		}
+	var verified int
+	fn := func() (err error) {
+		verified, err = l.ethereum.VerifyPeggyEvents(ctx, logs)
+		return err
+	}
 
-		for _, e := range withdrawalEvents {
-			ev := withdrawal(*e)
-			events = append(events, &ev)
-		}
+	if err := l.retry(ctx, fn); err != nil {
+		return 0, err
+	}
 
-		for _, e := range valsetUpdateEvents {
-			ev := valsetUpdate(*e)
-			events = append(events, &ev)
-		}
+	return verified, nil
+}
 
-		for _, e := range erc20DeploymentEvents {
-			ev := erc20Deployment(*e)
-			events = append(events, &ev)
+func (l *oracle) getLatestEthHeight(ctx context.Context) (uint64, error) {
+	h, err := l.getEthHeader(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.Number.Uint64(), nil
+}
+
+func (l *oracle) getEthHeader(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	var header *gethtypes.Header
+	fn := func() (err error) {
+		header, err = l.ethereum.GetHeaderByNumber(ctx, number)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get ethereum header %s", blockNumberString(number))
 		}
 
 		return nil
 	}
 
-	if err := l.retry(ctx, scanEthEventsFn); err != nil {
+	if err := l.retry(ctx, fn); err != nil {
 		return nil, err
 	}
 
-	return events, nil
+	return header, nil
 }
 
-func (l *oracle) getLatestEthHeight(ctx context.Context) (uint64, error) {
-	latestHeight := uint64(0)
-	fn := func() error {
-		h, err := l.ethereum.GetHeaderByNumber(ctx, nil)
+// getEthBlockHash returns the block hash reported by the Ethereum node. Header.Hash() is not used since it
+// only covers the header fields known to the go-ethereum version we depend on.
+func (l *oracle) getEthBlockHash(ctx context.Context, number *big.Int) (gethcommon.Hash, error) {
+	var hash gethcommon.Hash
+	fn := func() (err error) {
+		hash, err = l.ethereum.GetBlockHash(ctx, number)
 		if err != nil {
-			return errors.Wrap(err, "failed to get latest ethereum header")
+			return errors.Wrapf(err, "failed to get ethereum block hash %s", blockNumberString(number))
 		}
 
-		latestHeight = h.Number.Uint64()
 		return nil
 	}
 
 	if err := l.retry(ctx, fn); err != nil {
-		return 0, err
+		return gethcommon.Hash{}, err
 	}
 
-	return latestHeight, nil
+	return hash, nil
+}
+
+func blockNumberString(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+
+	if number.Sign() < 0 && number.IsInt64() {
+		return rpc.BlockNumber(number.Int64()).String()
+	}
+
+	return number.String()
 }
 
 func (l *oracle) getLastClaimEvent(ctx context.Context) (*peggytypes.LastClaimEvent, error) {
@@ -245,17 +435,13 @@ func (l *oracle) sendNewEventClaims(ctx context.Context, events []event) error {
 			return nil
 		}
 
-		for _, event := range newEvents {
-			if err := l.sendEthEventClaim(ctx, event); err != nil {
-				return err
-			}
-
-			// Considering block time ~1s on Injective chain, adding Sleep to make sure new event is sent
-			// only after previous event is executed successfully. Otherwise it will through `non contiguous event nonce` failing CheckTx.
-			time.Sleep(1100 * time.Millisecond)
+		claims := make([]peggytypes.EthereumClaim, len(newEvents))
+		for i, ev := range newEvents {
+			claims[i] = l.ethEventClaim(ev)
 		}
 
-		return nil
+		// claims are bundled into as few txs as possible, preserving the event nonce order
+		return l.injective.SendEthereumClaims(ctx, claims)
 	}
 
 	if err := l.retry(ctx, sendEventsFn); err != nil {
@@ -265,23 +451,23 @@ func (l *oracle) sendNewEventClaims(ctx context.Context, events []event) error {
 	return nil
 }
 
-func (l *oracle) sendEthEventClaim(ctx context.Context, ev event) error {
+func (l *oracle) ethEventClaim(ev event) peggytypes.EthereumClaim {
 	switch e := ev.(type) {
 	case *oldDeposit:
 		ev := peggyevents.PeggySendToCosmosEvent(*e)
-		return l.injective.SendOldDepositClaim(ctx, &ev)
+		return cosmospeggy.NewOldDepositClaimMsg(&ev, l.cfg.CosmosAddr)
 	case *deposit:
 		ev := peggyevents.PeggySendToInjectiveEvent(*e)
-		return l.injective.SendDepositClaim(ctx, &ev)
+		return cosmospeggy.NewDepositClaimMsg(&ev, l.cfg.CosmosAddr)
 	case *valsetUpdate:
 		ev := peggyevents.PeggyValsetUpdatedEvent(*e)
-		return l.injective.SendValsetClaim(ctx, &ev)
+		return cosmospeggy.NewValsetUpdatedClaimMsg(&ev, l.cfg.CosmosAddr)
 	case *withdrawal:
 		ev := peggyevents.PeggyTransactionBatchExecutedEvent(*e)
-		return l.injective.SendWithdrawalClaim(ctx, &ev)
+		return cosmospeggy.NewWithdrawClaimMsg(&ev, l.cfg.CosmosAddr)
 	case *erc20Deployment:
 		ev := peggyevents.PeggyERC20DeployedEvent(*e)
-		return l.injective.SendERC20DeployedClaim(ctx, &ev)
+		return cosmospeggy.NewERC20DeployedClaimMsg(&ev, l.cfg.CosmosAddr)
 	default:
 		panic(errors.Errorf("unknown ev type %T", e))
 	}
@@ -296,6 +482,9 @@ type (
 
 	event interface {
 		Nonce() uint64
+		BlockNumber() uint64
+		BlockHash() gethcommon.Hash
+		RawLog() gethtypes.Log
 	}
 )
 
@@ -328,3 +517,63 @@ func (o *withdrawal) Nonce() uint64 {
 func (o *erc20Deployment) Nonce() uint64 {
 	return o.EventNonce.Uint64()
 }
+
+func (o *oldDeposit) BlockNumber() uint64 {
+	return o.Raw.BlockNumber
+}
+
+func (o *deposit) BlockNumber() uint64 {
+	return o.Raw.BlockNumber
+}
+
+func (o *valsetUpdate) BlockNumber() uint64 {
+	return o.Raw.BlockNumber
+}
+
+func (o *withdrawal) BlockNumber() uint64 {
+	return o.Raw.BlockNumber
+}
+
+func (o *erc20Deployment) BlockNumber() uint64 {
+	return o.Raw.BlockNumber
+}
+
+func (o *oldDeposit) BlockHash() gethcommon.Hash {
+	return o.Raw.BlockHash
+}
+
+func (o *deposit) BlockHash() gethcommon.Hash {
+	return o.Raw.BlockHash
+}
+
+func (o *valsetUpdate) BlockHash() gethcommon.Hash {
+	return o.Raw.BlockHash
+}
+
+func (o *withdrawal) BlockHash() gethcommon.Hash {
+	return o.Raw.BlockHash
+}
+
+func (o *erc20Deployment) BlockHash() gethcommon.Hash {
+	return o.Raw.BlockHash
+}
+
+func (o *oldDeposit) RawLog() gethtypes.Log {
+	return o.Raw
+}
+
+func (o *deposit) RawLog() gethtypes.Log {
+	return o.Raw
+}
+
+func (o *valsetUpdate) RawLog() gethtypes.Log {
+	return o.Raw
+}
+
+func (o *withdrawal) RawLog() gethtypes.Log {
+	return o.Raw
+}
+
+func (o *erc20Deployment) RawLog() gethtypes.Log {
+	return o.Raw
+}
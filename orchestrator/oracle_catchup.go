@@ -0,0 +1,133 @@
+package orchestrator
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+const (
+	// Number of block ranges (of the eth_getLogs window) the Oracle has to fall behind the confirmed Ethereum height
+	// to enter catch-up mode
+	catchUpThreshold = 10
+
+	// Number of block ranges (of the eth_getLogs window) scanned concurrently in catch-up mode
+	catchUpConcurrency = 4
+)
+
+// catchUp scans several block ranges at once and claims their events back to back, without waiting for
+// the next loop iteration. It returns once the Oracle reached the confirmed Ethereum height, or if it could
+// not move its cursor (missed event, reorg, unverified events), leaving the rest to the regular loop.
+func (l *oracle) catchUp(ctx context.Context) error {
+	l.Log().WithField("observed", l.lastObservedEthHeight).Infoln("Oracle fell behind Ethereum, entering catch-up mode")
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		confirmedHeader, err := l.getConfirmedEthHeader(ctx)
+		if err != nil {
+			return err
+		}
+
+		if confirmedHeader == nil {
+			return nil
+		}
+
+		latestHeight := confirmedHeader.Number.Uint64()
+		l.reportEthLag(latestHeight)
+
+		// the confirmed height already accounts for the confirmation policy
+		if latestHeight <= l.lastObservedEthHeight {
+			l.Log().WithFields(log.Fields{"latest": latestHeight, "observed": l.lastObservedEthHeight}).Infoln("Oracle caught up with Ethereum, leaving catch-up mode")
+			return nil
+		}
+
+		scanEndHeader := confirmedHeader
+		if scanEnd := l.lastObservedEthHeight + catchUpConcurrency*l.ethereum.LogsWindow(); scanEnd < latestHeight {
+			if scanEndHeader, err = l.getEthHeader(ctx, new(big.Int).SetUint64(scanEnd)); err != nil {
+				return err
+			}
+		}
+
+		advanced, err := l.scanAndClaim(ctx, scanEndHeader)
+		if err != nil {
+			return err
+		}
+
+		if !advanced {
+			return nil
+		}
+	}
+}
+
+// scanEthEvents fetches the events within [startBlock, endBlock], scanning up to catchUpConcurrency
+// block ranges of the eth_getLogs window at once.
+func (l *oracle) scanEthEvents(ctx context.Context, startBlock, endBlock uint64) ([]event, error) {
+	blocksToSearch := l.ethereum.LogsWindow()
+	if endBlock-startBlock <= blocksToSearch {
+		return l.getEthEvents(ctx, startBlock, endBlock)
+	}
+
+	type blockRange struct {
+		start, end uint64
+	}
+
+	var ranges []blockRange
+	for from := startBlock; from <= endBlock; from += blocksToSearch + 1 {
+		to := from + blocksToSearch
+		if to > endBlock {
+			to = endBlock
+		}
+
+		ranges = append(ranges, blockRange{start: from, end: to})
+	}
+
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, catchUpConcurrency)
+		results = make([][]event, len(ranges))
+		errs    = make([]error, len(ranges))
+	)
+
+	for i, r := range ranges {
+		wg.Add(1)
+		go func(i int, r blockRange) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i], errs[i] = l.getEthEvents(ctx, r.start, r.end)
+		}(i, r)
+	}
+
+	wg.Wait()
+
+	var events []event
+	for i := range ranges {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		events = append(events, results[i]...)
+	}
+
+	return events, nil
+}
+
+func (l *oracle) reportEthLag(latestHeight uint64) {
+	var lag float64
+	if latestHeight > l.lastObservedEthHeight {
+		lag = float64(latestHeight - l.lastObservedEthHeight)
+	}
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Gauge("oracle.eth_lag", lag, tagSpec, 1)
+	}, l.svcTags)
+}
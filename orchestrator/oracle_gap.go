@@ -0,0 +1,131 @@
+package orchestrator
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// Maximum number of missing events the Oracle locates one by one before falling back to a full rescan
+const maxLocatedEvents = 16
+
+// locateMissingEvents fills the nonce gap between the last claimed event and the scanned events. The block of
+// every missing event is found by binary search over the Peggy contract's state_lastEventNonce at historical
+// heights (which needs an archive node) and only that block is scanned again. It returns false if the events
+// cannot be located, in which case the Oracle rescans from the last claimed event.
+func (l *oracle) locateMissingEvents(ctx context.Context, lastClaim *peggytypes.LastClaimEvent, events []event, scanEnd uint64) ([]event, bool) {
+	for i := 0; i < maxLocatedEvents; i++ {
+		nonce, missing := firstMissingNonce(lastClaim.EthereumEventNonce, events)
+		if !missing {
+			return events, true
+		}
+
+		height, err := l.findEventHeight(ctx, nonce, lastClaim.EthereumEventHeight, scanEnd)
+		if err != nil {
+			l.Log().WithError(err).WithField("event_nonce", nonce).Infoln("failed to locate missed event using archive state")
+			return nil, false
+		}
+
+		blockEvents, err := l.getEthEvents(ctx, height, height)
+		if err != nil {
+			l.Log().WithError(err).WithField("block_number", height).Warningln("failed to scan block of missed event")
+			return nil, false
+		}
+
+		merged, found := mergeEvents(events, filterEvents(blockEvents, lastClaim.EthereumEventNonce), nonce)
+		if !found {
+			l.Log().WithFields(log.Fields{"event_nonce": nonce, "block_number": height}).Warningln("missed event not found in the located block")
+			return nil, false
+		}
+
+		l.Log().WithFields(log.Fields{"event_nonce": nonce, "block_number": height}).Infoln("located missed Ethereum event")
+		events = merged
+	}
+
+	return nil, false
+}
+
+// findEventHeight returns the lowest block in [low, high] at which the Peggy contract's last event nonce
+// reached nonce, i.e. the block that emitted the event.
+func (l *oracle) findEventHeight(ctx context.Context, nonce, low, high uint64) (uint64, error) {
+	reached := func(height uint64) (bool, error) {
+		lastNonce, err := l.ethereum.GetLastEventNonce(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get last event nonce at block %d", height)
+		}
+
+		return lastNonce.Uint64() >= nonce, nil
+	}
+
+	if ok, err := reached(high); err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, errors.Errorf("event nonce %d not reached at block %d", nonce, high)
+	}
+
+	for low < high {
+		mid := low + (high-low)/2
+
+		ok, err := reached(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return low, nil
+}
+
+// firstMissingNonce returns the first nonce after lastClaimedNonce not covered by the (sorted) events
+func firstMissingNonce(lastClaimedNonce uint64, events []event) (uint64, bool) {
+	next := lastClaimedNonce + 1
+	for _, ev := range events {
+		switch {
+		case ev.Nonce() < next:
+			continue
+		case ev.Nonce() > next:
+			return next, true
+		}
+
+		next++
+	}
+
+	return 0, false
+}
+
+// mergeEvents adds the located events to the scanned ones, ordered by nonce, and reports whether
+// the event with the given nonce was among them
+func mergeEvents(events, located []event, nonce uint64) ([]event, bool) {
+	known := make(map[uint64]struct{}, len(events))
+	for _, ev := range events {
+		known[ev.Nonce()] = struct{}{}
+	}
+
+	merged := append([]event(nil), events...)
+	found := false
+	for _, ev := range located {
+		if ev.Nonce() == nonce {
+			found = true
+		}
+
+		if _, ok := known[ev.Nonce()]; !ok {
+			merged = append(merged, ev)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Nonce() < merged[j].Nonce()
+	})
+
+	return merged, found
+}
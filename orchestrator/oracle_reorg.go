@@ -0,0 +1,198 @@
+package orchestrator
+
+import (
+	"context"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+)
+
+// Number of scanned blocks the Oracle remembers in order to detect reorgs below its cursor
+const maxRecentBlocks = 64
+
+// ConfirmationMode determines when the Oracle considers an Ethereum block safe to attest to.
+type ConfirmationMode string
+
+const (
+	// ConfirmationDepth waits for a fixed number of blocks to be built on top of the block
+	ConfirmationDepth ConfirmationMode = "depth"
+	// ConfirmationFinalized waits for the block to be finalized by the consensus layer
+	ConfirmationFinalized ConfirmationMode = "finalized"
+	// ConfirmationSafe waits for the block to be justified (the "safe" tag) by the consensus layer
+	ConfirmationSafe ConfirmationMode = "safe"
+)
+
+// ConfirmationPolicy configures how the Oracle picks the highest Ethereum block it scans for events.
+type ConfirmationPolicy struct {
+	Mode  ConfirmationMode
+	Depth uint64
+}
+
+// ParseConfirmationPolicy validates the policy mode. Depth is only used in the "depth" mode.
+func ParseConfirmationPolicy(mode string, depth uint64) (ConfirmationPolicy, error) {
+	switch m := ConfirmationMode(mode); m {
+	case ConfirmationDepth:
+		return ConfirmationPolicy{Mode: m, Depth: depth}, nil
+	case ConfirmationFinalized, ConfirmationSafe:
+		return ConfirmationPolicy{Mode: m}, nil
+	default:
+		return ConfirmationPolicy{}, errors.Errorf("unknown confirmation policy: %s", mode)
+	}
+}
+
+// depth returns the number of block confirmations in the depth mode, or the default one for the other modes
+func (p ConfirmationPolicy) depth() uint64 {
+	if p.Mode == ConfirmationDepth {
+		return p.Depth
+	}
+
+	return ethBlockConfirmationDelay
+}
+
+// getConfirmedEthHeader returns the most recent Ethereum header that satisfies the confirmation policy.
+// A nil header is returned if there are not enough blocks on Ethereum yet.
+func (l *oracle) getConfirmedEthHeader(ctx context.Context) (*gethtypes.Header, error) {
+	switch l.cfg.EthConfirmations.Mode {
+	case ConfirmationFinalized:
+		return l.getEthHeader(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	case ConfirmationSafe:
+		return l.getEthHeader(ctx, big.NewInt(int64(rpc.SafeBlockNumber)))
+	}
+
+	depth := l.cfg.EthConfirmations.depth()
+
+	latestHeight, err := l.getLatestEthHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// not enough blocks on ethereum yet
+	if latestHeight <= depth {
+		l.Log().Debugln("not enough blocks on Ethereum")
+		return nil, nil
+	}
+
+	return l.getEthHeader(ctx, new(big.Int).SetUint64(latestHeight-depth))
+}
+
+// rollbackOnReorg checks whether the most recently scanned blocks are still part of the canonical chain.
+// If a reorg happened below the Oracle's cursor, the cursor is moved back to the newest block that survived
+// it so that the affected range is scanned again.
+func (l *oracle) rollbackOnReorg(ctx context.Context) error {
+	if len(l.recentBlocks) == 0 {
+		return nil
+	}
+
+	for i := len(l.recentBlocks) - 1; i >= 0; i-- {
+		ref := l.recentBlocks[i]
+
+		hash, err := l.getEthBlockHash(ctx, new(big.Int).SetUint64(ref.Height))
+		if err != nil {
+			return err
+		}
+
+		if hash != ref.Hash {
+			continue
+		}
+
+		if i == len(l.recentBlocks)-1 {
+			return nil
+		}
+
+		l.Log().WithFields(log.Fields{"observed": l.lastObservedEthHeight, "rollback_to": ref.Height}).Warningln("detected Ethereum reorg, rescanning affected blocks")
+		l.reportReorg()
+
+		l.lastObservedEthHeight = ref.Height
+		l.recentBlocks = l.recentBlocks[:i+1]
+
+		return nil
+	}
+
+	// none of the remembered blocks survived, restart from the last attested claim
+	lastClaim, err := l.getLastClaimEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.Log().WithFields(log.Fields{"observed": l.lastObservedEthHeight, "rollback_to": lastClaim.EthereumEventHeight}).Warningln("detected deep Ethereum reorg, restarting block search from last attested claim")
+	l.reportReorg()
+
+	l.lastObservedEthHeight = lastClaim.EthereumEventHeight
+	l.recentBlocks = nil
+
+	return nil
+}
+
+// eventsOnCanonicalChain makes sure that none of the events come from a block that was reorged out
+// after it had been scanned.
+func (l *oracle) eventsOnCanonicalChain(ctx context.Context, events []event) (bool, error) {
+	canonical := make(map[uint64]gethcommon.Hash)
+	for _, ev := range events {
+		hash, ok := canonical[ev.BlockNumber()]
+		if !ok {
+			var err error
+			if hash, err = l.getEthBlockHash(ctx, new(big.Int).SetUint64(ev.BlockNumber())); err != nil {
+				return false, err
+			}
+
+			canonical[ev.BlockNumber()] = hash
+		}
+
+		if hash != ev.BlockHash() {
+			l.Log().WithFields(log.Fields{"event_nonce": ev.Nonce(), "block_number": ev.BlockNumber(), "block_hash": ev.BlockHash().Hex(), "canonical_hash": hash.Hex()}).Warningln("event is not on the canonical chain")
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// recordBlock remembers a scanned block so a later reorg below it can be detected.
+func (l *oracle) recordBlock(ref state.BlockRef) {
+	l.forgetBlocksAbove(ref.Height - 1)
+	l.recentBlocks = append(l.recentBlocks, ref)
+	if len(l.recentBlocks) > maxRecentBlocks {
+		l.recentBlocks = l.recentBlocks[len(l.recentBlocks)-maxRecentBlocks:]
+	}
+}
+
+// forgetBlocksAbove drops remembered blocks that are no longer below the Oracle's cursor.
+func (l *oracle) forgetBlocksAbove(height uint64) {
+	for len(l.recentBlocks) > 0 && l.recentBlocks[len(l.recentBlocks)-1].Height > height {
+		l.recentBlocks = l.recentBlocks[:len(l.recentBlocks)-1]
+	}
+}
+
+func (l *oracle) reportReorg() {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("oracle.eth_reorgs", tagSpec, 1)
+	}, l.svcTags)
+}
+
+// loadRecentBlocks restores the blocks remembered for reorg detection from the last oracle checkpoint.
+func (s *Orchestrator) loadRecentBlocks(lastObservedBlock uint64) []state.BlockRef {
+	if s.store == nil {
+		return nil
+	}
+
+	cp, err := s.store.OracleCheckpoint(s.cfg.PeggyContractAddr)
+	if err != nil {
+		return nil
+	}
+
+	var blocks []state.BlockRef
+	for _, ref := range cp.RecentBlocks {
+		if ref.Height <= lastObservedBlock {
+			blocks = append(blocks, ref)
+		}
+	}
+
+	return blocks
+}
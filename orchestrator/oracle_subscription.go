@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum"
+)
+
+// Delay before resubscribing after the Ethereum subscription dropped. Polling fills the gap meanwhile.
+const resubscribeDelay = 10 * time.Second
+
+// watchEthereum subscribes to new heads and Peggy logs on Ethereum and signals on trigger once a block
+// containing Peggy events satisfies the confirmation policy. Claims are still sent by observeEthEvents,
+// so a dropped subscription only means the Oracle falls back to polling until it resubscribes.
+func (l *oracle) watchEthereum(ctx context.Context, trigger chan<- struct{}) {
+	for {
+		err := l.watchEthereumOnce(ctx, trigger)
+		if errors.Is(err, ethereum.ErrSubscriptionsDisabled) {
+			l.Log().Debugln("Ethereum subscriptions disabled, polling for events")
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.Log().WithError(err).Warningln("Ethereum subscription dropped, falling back to polling")
+		l.reportSubscriptionDrop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+func (l *oracle) watchEthereumOnce(ctx context.Context, trigger chan<- struct{}) error {
+	var (
+		heads = make(chan *gethtypes.Header, 16)
+		logs  = make(chan gethtypes.Log, 128)
+	)
+
+	sub, err := l.ethereum.SubscribePeggyEvents(ctx, heads, logs)
+	if err != nil {
+		return err
+	}
+
+	defer sub.Unsubscribe()
+
+	l.Log().Infoln("subscribed to Ethereum heads and Peggy events")
+
+	// catch up on whatever happened while we were not subscribed
+	notify(trigger)
+
+	// blocks with Peggy events that are not confirmed yet
+	pending := make(map[uint64]struct{})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case lg := <-logs:
+			if lg.Removed {
+				// the block was reorged out, observeEthEvents takes care of it
+				continue
+			}
+
+			l.Log().WithFields(log.Fields{"block_number": lg.BlockNumber, "tx_hash": lg.TxHash.Hex()}).Debugln("observed Peggy event, waiting for confirmations")
+			pending[lg.BlockNumber] = struct{}{}
+		case head := <-heads:
+			if len(pending) == 0 {
+				continue
+			}
+
+			confirmedHeight, err := l.confirmedHeightAt(ctx, head)
+			if err != nil {
+				l.Log().WithError(err).Warningln("failed to get confirmed Ethereum height")
+				continue
+			}
+
+			if confirmed := dropConfirmed(pending, confirmedHeight); confirmed > 0 {
+				l.Log().WithFields(log.Fields{"blocks": confirmed, "confirmed_height": confirmedHeight}).Debugln("Peggy events reached confirmation, claiming")
+				notify(trigger)
+			}
+		}
+	}
+}
+
+// confirmedHeightAt returns the highest block that satisfies the confirmation policy given the latest head.
+func (l *oracle) confirmedHeightAt(ctx context.Context, head *gethtypes.Header) (uint64, error) {
+	switch l.cfg.EthConfirmations.Mode {
+	case ConfirmationFinalized, ConfirmationSafe:
+		h, err := l.getConfirmedEthHeader(ctx)
+		if err != nil || h == nil {
+			return 0, err
+		}
+
+		return h.Number.Uint64(), nil
+	}
+
+	depth := l.cfg.EthConfirmations.depth()
+
+	if latest := head.Number.Uint64(); latest > depth {
+		return latest - depth, nil
+	}
+
+	return 0, nil
+}
+
+// dropConfirmed removes the blocks at or below the confirmed height and returns how many were removed
+func dropConfirmed(pending map[uint64]struct{}, confirmedHeight uint64) int {
+	confirmed := 0
+	for height := range pending {
+		if height <= confirmedHeight {
+			delete(pending, height)
+			confirmed++
+		}
+	}
+
+	return confirmed
+}
+
+func notify(trigger chan<- struct{}) {
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (l *oracle) reportSubscriptionDrop() {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("oracle.eth_subscription_drops", tagSpec, 1)
+	}, l.svcTags)
+}
@@ -10,9 +10,12 @@ import (
 	log "github.com/xlab/suplog"
 
 	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/batchpolicy"
 	"github.com/InjectiveLabs/peggo/orchestrator/cosmos"
 	"github.com/InjectiveLabs/peggo/orchestrator/ethereum"
 	"github.com/InjectiveLabs/peggo/orchestrator/loops"
+	"github.com/InjectiveLabs/peggo/orchestrator/outflow"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
 )
 
 const (
@@ -27,6 +30,7 @@ type PriceFeed interface {
 type Config struct {
 	CosmosAddr           cosmostypes.AccAddress
 	EthereumAddr         gethcommon.Address
+	PeggyContractAddr    gethcommon.Address
 	MinBatchFeeUSD       float64
 	ERC20ContractMapping map[gethcommon.Address]string
 	RelayValsetOffsetDur time.Duration
@@ -34,6 +38,37 @@ type Config struct {
 	RelayValsets         bool
 	RelayBatches         bool
 	RelayerMode          bool
+	EthConfirmations     ConfirmationPolicy
+
+	// OutflowLimits bound the amounts withdrawn by the signed batches, batches over them are held in OutflowQueue
+	OutflowLimits *outflow.Limits
+	OutflowQueue  *outflow.Queue
+
+	// BatchPolicies decide when batches are requested, tokens without a policy use MinBatchFeeUSD
+	BatchPolicies *batchpolicy.Policies
+
+	// BatchGasMargin is how many times the batch fees must cover the estimated gas cost of relaying the batch, zero
+	// disables the check
+	BatchGasMargin float64
+
+	// BatchGas is the gas of submitBatch the BatchGasMargin check estimates with
+	BatchGas BatchGas
+
+	// ForceBatchAge forces a batch once a withdrawal has waited that long, at most MaxForcedBatches per token a day.
+	// Batch policies can set their own.
+	ForceBatchAge    time.Duration
+	MaxForcedBatches int
+
+	// BatchRequestGrace is how long instances not elected to request the batch of a token wait for the elected one,
+	// zero disables the coordination
+	BatchRequestGrace time.Duration
+
+	// RelayBatchMargin is how many times the batch fees must cover the simulated gas cost of relaying the batch, zero
+	// disables the check
+	RelayBatchMargin float64
+
+	// GasPriceAdjustment is what the suggested Ethereum gas price is multiplied by when sending transactions
+	GasPriceAdjustment float64
 }
 
 type Orchestrator struct {
@@ -45,12 +80,14 @@ type Orchestrator struct {
 	injective cosmos.Network
 	ethereum  ethereum.Network
 	priceFeed PriceFeed
+	store     state.Store
 }
 
 func NewOrchestrator(
 	inj cosmos.Network,
 	eth ethereum.Network,
 	priceFeed PriceFeed,
+	store state.Store,
 	cfg Config,
 ) (*Orchestrator, error) {
 	o := &Orchestrator{
@@ -59,6 +96,7 @@ func NewOrchestrator(
 		injective:   inj,
 		ethereum:    eth,
 		priceFeed:   priceFeed,
+		store:       store,
 		cfg:         cfg,
 		maxAttempts: 10,
 	}
@@ -91,6 +129,8 @@ func (s *Orchestrator) startValidatorMode(ctx context.Context, inj cosmos.Networ
 		lastObservedEthBlock = peggyParams.BridgeContractStartHeight
 	}
 
+	lastObservedEthBlock = s.resumeFromCheckpoint(ctx, inj, eth, lastObservedEthBlock)
+
 	// get peggy ID from contract
 	peggyContractID, err := eth.GetPeggyID(ctx)
 	if err != nil {
@@ -4,17 +4,32 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	sdkmath "cosmossdk.io/math"
 	cometrpc "github.com/cometbft/cometbft/rpc/core/types"
 	comettypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	goethereum "github.com/ethereum/go-ethereum"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/batchpolicy"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum"
+	ethpeggy "github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/outflow"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
 	peggyevents "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
 	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
 )
@@ -72,8 +87,11 @@ func Test_BatchCreator(t *testing.T) {
 				priceFeed: MockPriceFeed{QueryUSDPriceFn: func(_ gethcommon.Address) (float64, error) { return 1, nil }},
 				injective: MockCosmosNetwork{
 					SendRequestBatchFn: func(context.Context, string) error { return nil },
+					LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return nil, nil
+					},
 					UnbatchedTokensWithFeesFn: func(context.Context) ([]*peggytypes.BatchFees, error) {
-						fees, _ := cosmostypes.NewIntFromString("50000000000000000000")
+						fees, _ := sdkmath.NewIntFromString("50000000000000000000")
 						return []*peggytypes.BatchFees{
 							{
 								Token:     injTokenAddress.String(),
@@ -103,8 +121,11 @@ func Test_BatchCreator(t *testing.T) {
 				},
 				injective: MockCosmosNetwork{
 					SendRequestBatchFn: func(context.Context, string) error { return nil },
+					LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return nil, nil
+					},
 					UnbatchedTokensWithFeesFn: func(_ context.Context) ([]*peggytypes.BatchFees, error) {
-						fees, _ := cosmostypes.NewIntFromString("50000000000000000000")
+						fees, _ := sdkmath.NewIntFromString("50000000000000000000")
 						return []*peggytypes.BatchFees{{
 							Token:     injTokenAddress.String(),
 							TotalFees: fees,
@@ -131,6 +152,523 @@ func Test_BatchCreator(t *testing.T) {
 	}
 }
 
+func Test_BatchCreator_Policies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		usdtAddress = gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+		aaveAddress = gethcommon.HexToAddress("0x7Fc66500c84A76Ad7e9c93437bFc5Ac33E2DDaE9")
+		wethAddress = gethcommon.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	)
+
+	policies := &batchpolicy.Policies{
+		Tokens: map[gethcommon.Address]batchpolicy.Policy{
+			usdtAddress: {MinFee: decimal.NewFromInt(10), MinWithdrawals: 2, MaxWithdrawalAge: time.Hour},
+			aaveAddress: {Deny: true},
+		},
+	}
+
+	transfer := func(id uint64, token gethcommon.Address) *peggytypes.OutgoingTransferTx {
+		return &peggytypes.OutgoingTransferTx{
+			Id:         id,
+			Erc20Token: &peggytypes.ERC20Token{Contract: token.Hex(), Amount: sdkmath.NewInt(100)},
+			Erc20Fee:   &peggytypes.ERC20Token{Contract: token.Hex(), Amount: sdkmath.NewInt(1)},
+		}
+	}
+
+	testTable := []struct {
+		name      string
+		minFeeUSD float64
+		fees      []*peggytypes.BatchFees
+		transfers []*peggytypes.OutgoingTransferTx
+		firstSeen map[uint64]time.Time
+		expected  []string
+	}{
+		{
+			name:      "withdrawal count below policy",
+			fees:      []*peggytypes.BatchFees{{Token: usdtAddress.Hex(), TotalFees: sdkmath.NewInt(50)}},
+			transfers: []*peggytypes.OutgoingTransferTx{transfer(1, usdtAddress)},
+			expected:  nil,
+		},
+		{
+			name:      "withdrawal count and fee meet policy",
+			fees:      []*peggytypes.BatchFees{{Token: usdtAddress.Hex(), TotalFees: sdkmath.NewInt(50)}},
+			transfers: []*peggytypes.OutgoingTransferTx{transfer(1, usdtAddress), transfer(2, usdtAddress), transfer(3, wethAddress)},
+			expected:  []string{peggytypes.PeggyDenomString(usdtAddress)},
+		},
+		{
+			name:      "old withdrawal forces batch",
+			fees:      []*peggytypes.BatchFees{{Token: usdtAddress.Hex(), TotalFees: sdkmath.NewInt(1)}},
+			transfers: []*peggytypes.OutgoingTransferTx{transfer(1, usdtAddress)},
+			firstSeen: map[uint64]time.Time{1: time.Now().Add(-2 * time.Hour)},
+			expected:  []string{peggytypes.PeggyDenomString(usdtAddress)},
+		},
+		{
+			name:     "denied token",
+			fees:     []*peggytypes.BatchFees{{Token: aaveAddress.Hex(), TotalFees: sdkmath.NewInt(1e6)}},
+			expected: nil,
+		},
+		{
+			name:      "token without policy uses min batch fee",
+			minFeeUSD: 5,
+			fees: []*peggytypes.BatchFees{
+				{Token: wethAddress.Hex(), TotalFees: sdkmath.NewInt(4)},
+				{Token: aaveAddress.Hex(), TotalFees: sdkmath.NewInt(1e6)},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requested []string
+
+			bc := batchCreator{
+				Orchestrator: &Orchestrator{
+					logger:      DummyLog,
+					maxAttempts: maxLoopRetries,
+					cfg:         Config{MinBatchFeeUSD: tt.minFeeUSD, BatchPolicies: policies},
+					priceFeed:   MockPriceFeed{QueryUSDPriceFn: func(_ gethcommon.Address) (float64, error) { return 1, nil }},
+					injective: MockCosmosNetwork{
+						LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+							return nil, nil
+						},
+						UnbatchedTokensWithFeesFn: func(context.Context) ([]*peggytypes.BatchFees, error) {
+							return tt.fees, nil
+						},
+						UnbatchedTransfersFn: func(context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+							return tt.transfers, nil
+						},
+						SendRequestBatchFn: func(_ context.Context, denom string) error {
+							requested = append(requested, denom)
+							return nil
+						},
+					},
+					ethereum: MockEthereumNetwork{
+						TokenDecimalsFn: func(_ context.Context, _ gethcommon.Address) (uint8, error) {
+							return 0, nil
+						},
+					},
+				},
+				firstSeen: tt.firstSeen,
+			}
+
+			assert.NoError(t, bc.requestTokenBatches(context.Background()))
+			assert.Equal(t, tt.expected, requested)
+
+			// withdrawals that left the pool are forgotten
+			assert.Len(t, bc.firstSeen, len(tt.transfers))
+		})
+	}
+}
+
+func Test_BatchCreator_GasCost(t *testing.T) {
+	t.Parallel()
+
+	tokenAddress := gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	// 4 validators and 2 withdrawals: 218000 gas at 10 gwei and 2000 USD/ETH is 4.36 USD, 6.54 USD with the margin
+	valset := &peggytypes.Valset{Members: make([]*peggytypes.BridgeValidator, 4)}
+
+	withdrawals := func(fees ...int64) []*peggytypes.OutgoingTransferTx {
+		transfers := make([]*peggytypes.OutgoingTransferTx, 0, len(fees))
+		for i, fee := range fees {
+			transfers = append(transfers, &peggytypes.OutgoingTransferTx{
+				Id:         uint64(i + 1),
+				Erc20Token: &peggytypes.ERC20Token{Contract: tokenAddress.Hex(), Amount: sdkmath.NewInt(100)},
+				Erc20Fee:   &peggytypes.ERC20Token{Contract: tokenAddress.Hex(), Amount: sdkmath.NewInt(fee)},
+			})
+		}
+
+		return transfers
+	}
+
+	// 150 withdrawals paying 1 each, a full batch of 100 costs 3648000 gas: 72.96 USD, 109.44 USD with the margin
+	fullBatch := make([]int64, 150)
+	for i := range fullBatch {
+		fullBatch[i] = 1
+	}
+
+	testTable := []struct {
+		name        string
+		transfers   []*peggytypes.OutgoingTransferTx
+		batchGas    BatchGas
+		policies    *batchpolicy.Policies
+		firstSeen   map[uint64]time.Time
+		gasPriceErr error
+		expected    bool
+	}{
+		{
+			name:      "fees exceed gas cost",
+			transfers: withdrawals(3, 4),
+			expected:  true,
+		},
+		{
+			name:      "fees below gas cost",
+			transfers: withdrawals(3, 3),
+			expected:  false,
+		},
+		{
+			name:      "only the fees of a full batch count",
+			transfers: withdrawals(fullBatch...),
+			expected:  false,
+		},
+		{
+			name:      "configured gas exceeds fees",
+			transfers: withdrawals(3, 4),
+			batchGas:  BatchGas{PerTx: 100_000},
+			expected:  false,
+		},
+		{
+			name:        "gas cost unknown",
+			transfers:   withdrawals(500, 500),
+			gasPriceErr: errors.New("oops"),
+			expected:    false,
+		},
+		{
+			name:      "batch forced by withdrawal age",
+			transfers: withdrawals(1, 0),
+			policies:  &batchpolicy.Policies{Default: &batchpolicy.Policy{MaxWithdrawalAge: time.Hour}},
+			firstSeen: map[uint64]time.Time{1: time.Now().Add(-2 * time.Hour)},
+			expected:  true,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requested := false
+
+			bc := batchCreator{
+				Orchestrator: &Orchestrator{
+					logger:      DummyLog,
+					maxAttempts: maxLoopRetries,
+					cfg:         Config{BatchGasMargin: 1.5, BatchGas: tt.batchGas, BatchPolicies: tt.policies},
+					priceFeed: MockPriceFeed{QueryUSDPriceFn: func(token gethcommon.Address) (float64, error) {
+						if token == wethAddress {
+							return 2000, nil
+						}
+
+						return 1, nil
+					}},
+					injective: MockCosmosNetwork{
+						LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+							return nil, nil
+						},
+						UnbatchedTokensWithFeesFn: func(context.Context) ([]*peggytypes.BatchFees, error) {
+							totalFees := sdkmath.ZeroInt()
+							for _, tx := range tt.transfers {
+								totalFees = totalFees.Add(tx.Erc20Fee.Amount)
+							}
+
+							return []*peggytypes.BatchFees{{Token: tokenAddress.Hex(), TotalFees: totalFees}}, nil
+						},
+						UnbatchedTransfersFn: func(context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+							return tt.transfers, nil
+						},
+						CurrentValsetFn: func(context.Context) (*peggytypes.Valset, error) {
+							return valset, nil
+						},
+						SendRequestBatchFn: func(context.Context, string) error {
+							requested = true
+							return nil
+						},
+					},
+					ethereum: MockEthereumNetwork{
+						TokenDecimalsFn: func(_ context.Context, _ gethcommon.Address) (uint8, error) {
+							return 0, nil
+						},
+						SuggestGasPriceFn: func(context.Context) (*big.Int, error) {
+							if tt.gasPriceErr != nil {
+								return nil, tt.gasPriceErr
+							}
+
+							return big.NewInt(10e9), nil
+						},
+					},
+				},
+				firstSeen: tt.firstSeen,
+			}
+
+			assert.NoError(t, bc.requestTokenBatches(context.Background()))
+			assert.Equal(t, tt.expected, requested)
+		})
+	}
+
+	assert.Equal(t, uint64(submitBatchBaseGas+4*submitBatchGasPerValidator+maxBatchSize*submitBatchGasPerTx), BatchGas{}.estimate(150, 4))
+	assert.Equal(t, uint64(1000+4*submitBatchGasPerValidator+2*10), BatchGas{Base: 1000, PerTx: 10}.estimate(2, 4))
+}
+
+func Test_BatchCreator_ForcedBatches(t *testing.T) {
+	t.Parallel()
+
+	tokenAddress := gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	var (
+		requested int
+		saved     *state.BatchCreatorProgress
+	)
+
+	bc := batchCreator{
+		Orchestrator: &Orchestrator{
+			logger:      DummyLog,
+			maxAttempts: maxLoopRetries,
+			cfg:         Config{MinBatchFeeUSD: 100, ForceBatchAge: time.Hour, MaxForcedBatches: 1},
+			priceFeed:   MockPriceFeed{QueryUSDPriceFn: func(_ gethcommon.Address) (float64, error) { return 1, nil }},
+			store: MockStore{
+				// the withdrawal was first seen before a restart
+				BatchCreatorProgressFn: func() (*state.BatchCreatorProgress, error) {
+					return &state.BatchCreatorProgress{FirstSeen: map[uint64]time.Time{1: time.Now().Add(-2 * time.Hour)}}, nil
+				},
+				SetBatchCreatorProgressFn: func(p *state.BatchCreatorProgress) error {
+					saved = p
+					return nil
+				},
+			},
+			injective: MockCosmosNetwork{
+				LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+					return nil, nil
+				},
+				UnbatchedTokensWithFeesFn: func(context.Context) ([]*peggytypes.BatchFees, error) {
+					return []*peggytypes.BatchFees{{Token: tokenAddress.Hex(), TotalFees: sdkmath.NewInt(1)}}, nil
+				},
+				UnbatchedTransfersFn: func(context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+					return []*peggytypes.OutgoingTransferTx{
+						{Id: 1, Erc20Token: &peggytypes.ERC20Token{Contract: tokenAddress.Hex(), Amount: sdkmath.NewInt(100)}},
+						{Id: 2, Erc20Token: &peggytypes.ERC20Token{Contract: tokenAddress.Hex(), Amount: sdkmath.NewInt(100)}},
+					}, nil
+				},
+				SendRequestBatchFn: func(context.Context, string) error {
+					requested++
+					return nil
+				},
+			},
+			ethereum: MockEthereumNetwork{
+				TokenDecimalsFn: func(_ context.Context, _ gethcommon.Address) (uint8, error) {
+					return 0, nil
+				},
+			},
+		},
+	}
+
+	// the aged withdrawal forces a batch although the fees are below MinBatchFeeUSD
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 1, requested)
+
+	assert.NotNil(t, saved)
+	assert.Len(t, saved.FirstSeen, 2)
+	assert.Len(t, saved.ForcedBatches[tokenAddress.Hex()], 1)
+
+	// only one batch a day is forced
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 1, requested)
+}
+
+func Test_BatchCreator_UnbatchedTransfersCache(t *testing.T) {
+	t.Parallel()
+
+	tokenAddress := gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	var (
+		queries   = 0
+		requested = 0
+		pending   []*peggytypes.OutgoingTxBatch
+		policy    = &batchpolicy.Policy{MinWithdrawals: 5}
+	)
+
+	bc := batchCreator{
+		Orchestrator: &Orchestrator{
+			logger:      DummyLog,
+			maxAttempts: maxLoopRetries,
+			cfg:         Config{BatchPolicies: &batchpolicy.Policies{Default: policy}},
+			priceFeed:   MockPriceFeed{QueryUSDPriceFn: func(_ gethcommon.Address) (float64, error) { return 1, nil }},
+			injective: MockCosmosNetwork{
+				LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+					return pending, nil
+				},
+				SendRequestBatchFn: func(context.Context, string) error {
+					requested++
+					return nil
+				},
+				UnbatchedTokensWithFeesFn: func(context.Context) ([]*peggytypes.BatchFees, error) {
+					return []*peggytypes.BatchFees{{Token: tokenAddress.Hex(), TotalFees: sdkmath.NewInt(1)}}, nil
+				},
+				UnbatchedTransfersFn: func(context.Context) ([]*peggytypes.OutgoingTransferTx, error) {
+					queries++
+					return []*peggytypes.OutgoingTransferTx{
+						{Id: 1, Erc20Token: &peggytypes.ERC20Token{Contract: tokenAddress.Hex(), Amount: sdkmath.NewInt(100)}},
+					}, nil
+				},
+			},
+			ethereum: MockEthereumNetwork{
+				TokenDecimalsFn: func(_ context.Context, _ gethcommon.Address) (uint8, error) {
+					return 0, nil
+				},
+			},
+		},
+	}
+
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 1, queries)
+	assert.Len(t, bc.firstSeen, 1)
+
+	// the withdrawals are queried again once the cached ones expire
+	bc.unbatchedAt = time.Now().Add(-unbatchedTransfersTTL)
+
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 2, queries)
+
+	// and whenever the pending batches change
+	pending = []*peggytypes.OutgoingTxBatch{{TokenContract: tokenAddress.Hex(), BatchNonce: 3}}
+
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 3, queries)
+
+	pending = nil
+
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 4, queries)
+
+	// or once a batch is requested
+	policy.MinWithdrawals = 1
+
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 1, requested)
+	assert.Equal(t, 4, queries)
+
+	assert.NoError(t, bc.requestTokenBatches(context.Background()))
+	assert.Equal(t, 5, queries)
+}
+
+func Test_BatchCreator_Coordination(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tokenAddress = gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+		ourAddr      = gethcommon.HexToAddress("0x76D2dDbb89C36FA39FAa5c5e7C61ee95AC4D76C4")
+		otherAddr    = gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+	)
+
+	// all the power is on one member, so the election does not depend on the slot
+	valset := func(elected gethcommon.Address) *peggytypes.Valset {
+		return &peggytypes.Valset{Members: []*peggytypes.BridgeValidator{
+			{EthereumAddress: ourAddr.Hex(), Power: map[bool]uint64{true: 100}[elected == ourAddr]},
+			{EthereumAddress: otherAddr.Hex(), Power: map[bool]uint64{true: 100}[elected == otherAddr]},
+		}}
+	}
+
+	testTable := []struct {
+		name        string
+		elected     gethcommon.Address
+		batches     []*peggytypes.OutgoingTxBatch
+		wantedSince map[gethcommon.Address]time.Time
+		expected    bool
+	}{
+		{
+			name:     "elected requester",
+			elected:  ourAddr,
+			expected: true,
+		},
+		{
+			name:     "pending batch of the token",
+			elected:  ourAddr,
+			batches:  []*peggytypes.OutgoingTxBatch{{TokenContract: tokenAddress.Hex(), BatchNonce: 3}},
+			expected: false,
+		},
+		{
+			name:     "waiting for elected requester",
+			elected:  otherAddr,
+			expected: false,
+		},
+		{
+			name:        "elected requester missed the grace period",
+			elected:     otherAddr,
+			wantedSince: map[gethcommon.Address]time.Time{tokenAddress: time.Now().Add(-6 * time.Minute)},
+			expected:    true,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requested := false
+
+			bc := batchCreator{
+				Orchestrator: &Orchestrator{
+					logger:      DummyLog,
+					maxAttempts: maxLoopRetries,
+					cfg:         Config{EthereumAddr: ourAddr, BatchRequestGrace: 5 * time.Minute},
+					injective: MockCosmosNetwork{
+						UnbatchedTokensWithFeesFn: func(context.Context) ([]*peggytypes.BatchFees, error) {
+							return []*peggytypes.BatchFees{{Token: tokenAddress.Hex(), TotalFees: sdkmath.NewInt(1)}}, nil
+						},
+						LatestTransactionBatchesFn: func(context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+							return tt.batches, nil
+						},
+						CurrentValsetFn: func(context.Context) (*peggytypes.Valset, error) {
+							return valset(tt.elected), nil
+						},
+						SendRequestBatchFn: func(context.Context, string) error {
+							requested = true
+							return nil
+						},
+					},
+					ethereum: MockEthereumNetwork{
+						TokenDecimalsFn: func(_ context.Context, _ gethcommon.Address) (uint8, error) {
+							return 0, nil
+						},
+					},
+				},
+				wantedSince: tt.wantedSince,
+			}
+
+			assert.NoError(t, bc.requestTokenBatches(context.Background()))
+			assert.Equal(t, tt.expected, requested)
+		})
+	}
+}
+
+func Test_ElectedRequester(t *testing.T) {
+	t.Parallel()
+
+	token := gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	valset := &peggytypes.Valset{Members: []*peggytypes.BridgeValidator{
+		{EthereumAddress: "0x76D2dDbb89C36FA39FAa5c5e7C61ee95AC4D76C4", Power: 3},
+		{EthereumAddress: "0x3959f5246c452463279F690301D923D5a75bbD88", Power: 1},
+	}}
+
+	start := time.Unix(1700000400, 0) // start of a slot
+	elected := make(map[gethcommon.Address]int)
+
+	for i := 0; i < 400; i++ {
+		slotTime := start.Add(time.Duration(i) * batchRequestSlot)
+
+		addr, ok := electedRequester(valset, token, slotTime)
+		assert.True(t, ok)
+		elected[addr]++
+
+		// every instance elects the same member during a slot
+		again, _ := electedRequester(valset, token, slotTime.Add(batchRequestSlot-time.Second))
+		assert.Equal(t, addr, again)
+	}
+
+	// members are elected in proportion to their power
+	assert.Len(t, elected, 2)
+	assert.Greater(t, elected[gethcommon.HexToAddress("0x76D2dDbb89C36FA39FAa5c5e7C61ee95AC4D76C4")], 200)
+
+	_, ok := electedRequester(&peggytypes.Valset{}, token, start)
+	assert.False(t, ok)
+}
+
 func Test_Oracle(t *testing.T) {
 	t.Parallel()
 
@@ -256,7 +794,7 @@ func Test_Oracle(t *testing.T) {
 					GetHeaderByNumberFn: func(context.Context, *big.Int) (*gethtypes.Header, error) {
 						return &gethtypes.Header{Number: big.NewInt(2100)}, nil
 					},
-					GetSendToCosmosEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
 						return nil, errors.New("oops")
 					},
 				},
@@ -290,26 +828,16 @@ func Test_Oracle(t *testing.T) {
 					GetHeaderByNumberFn: func(context.Context, *big.Int) (*gethtypes.Header, error) {
 						return &gethtypes.Header{Number: big.NewInt(2100)}, nil
 					},
-					GetSendToCosmosEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-						return []*peggyevents.PeggySendToCosmosEvent{
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+						return []ethereum.PeggyEvent{
 							{
-								EventNonce: big.NewInt(100),
+								Nonce: 100,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(100),
+								},
 							},
 						}, nil
 					},
-
-					GetValsetUpdatedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
-						return nil, nil
-					},
-					GetSendToInjectiveEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
-						return nil, nil
-					},
-					GetTransactionBatchExecutedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-						return nil, nil
-					},
-					GetPeggyERC20DeployedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-						return nil, nil
-					},
 				},
 			},
 		},
@@ -343,26 +871,16 @@ func Test_Oracle(t *testing.T) {
 					GetHeaderByNumberFn: func(context.Context, *big.Int) (*gethtypes.Header, error) {
 						return &gethtypes.Header{Number: big.NewInt(2100)}, nil
 					},
-					GetSendToCosmosEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-						return []*peggyevents.PeggySendToCosmosEvent{
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+						return []ethereum.PeggyEvent{
 							{
-								EventNonce: big.NewInt(100),
+								Nonce: 100,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(100),
+								},
 							},
 						}, nil
 					},
-
-					GetValsetUpdatedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
-						return nil, nil
-					},
-					GetSendToInjectiveEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
-						return nil, nil
-					},
-					GetTransactionBatchExecutedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-						return nil, nil
-					},
-					GetPeggyERC20DeployedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-						return nil, nil
-					},
 				},
 			},
 		},
@@ -397,26 +915,16 @@ func Test_Oracle(t *testing.T) {
 					GetHeaderByNumberFn: func(context.Context, *big.Int) (*gethtypes.Header, error) {
 						return &gethtypes.Header{Number: big.NewInt(2100)}, nil
 					},
-					GetSendToCosmosEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-						return []*peggyevents.PeggySendToCosmosEvent{
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+						return []ethereum.PeggyEvent{
 							{
-								EventNonce: big.NewInt(104),
+								Nonce: 104,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(104),
+								},
 							},
 						}, nil
 					},
-
-					GetValsetUpdatedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
-						return nil, nil
-					},
-					GetSendToInjectiveEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
-						return nil, nil
-					},
-					GetTransactionBatchExecutedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-						return nil, nil
-					},
-					GetPeggyERC20DeployedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-						return nil, nil
-					},
 				},
 			},
 		},
@@ -448,7 +956,7 @@ func Test_Oracle(t *testing.T) {
 						}, nil
 					},
 
-					SendOldDepositClaimFn: func(_ context.Context, _ *peggyevents.PeggySendToCosmosEvent) error {
+					SendEthereumClaimsFn: func(_ context.Context, _ []peggytypes.EthereumClaim) error {
 						return nil
 					},
 				},
@@ -456,26 +964,17 @@ func Test_Oracle(t *testing.T) {
 					GetHeaderByNumberFn: func(context.Context, *big.Int) (*gethtypes.Header, error) {
 						return &gethtypes.Header{Number: big.NewInt(2100)}, nil
 					},
-					GetSendToCosmosEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-						return []*peggyevents.PeggySendToCosmosEvent{
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+						return []ethereum.PeggyEvent{
 							{
-								EventNonce: big.NewInt(103),
+								Nonce: 103,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(103),
+									Raw:        gethtypes.Log{BlockHash: (&gethtypes.Header{Number: big.NewInt(2100)}).Hash()},
+								},
 							},
 						}, nil
 					},
-
-					GetValsetUpdatedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
-						return nil, nil
-					},
-					GetSendToInjectiveEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
-						return nil, nil
-					},
-					GetTransactionBatchExecutedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-						return nil, nil
-					},
-					GetPeggyERC20DeployedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-						return nil, nil
-					},
 				},
 			},
 		},
@@ -506,7 +1005,7 @@ func Test_Oracle(t *testing.T) {
 						}, nil
 					},
 
-					SendOldDepositClaimFn: func(_ context.Context, _ *peggyevents.PeggySendToCosmosEvent) error {
+					SendEthereumClaimsFn: func(_ context.Context, _ []peggytypes.EthereumClaim) error {
 						return nil
 					},
 				},
@@ -514,26 +1013,17 @@ func Test_Oracle(t *testing.T) {
 					GetHeaderByNumberFn: func(context.Context, *big.Int) (*gethtypes.Header, error) {
 						return &gethtypes.Header{Number: big.NewInt(2100)}, nil
 					},
-					GetSendToCosmosEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToCosmosEvent, error) {
-						return []*peggyevents.PeggySendToCosmosEvent{
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+						return []ethereum.PeggyEvent{
 							{
-								EventNonce: big.NewInt(103),
+								Nonce: 103,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(103),
+									Raw:        gethtypes.Log{BlockHash: (&gethtypes.Header{Number: big.NewInt(2100)}).Hash()},
+								},
 							},
 						}, nil
 					},
-
-					GetValsetUpdatedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyValsetUpdatedEvent, error) {
-						return nil, nil
-					},
-					GetSendToInjectiveEventsFn: func(_, _ uint64) ([]*peggyevents.PeggySendToInjectiveEvent, error) {
-						return nil, nil
-					},
-					GetTransactionBatchExecutedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyTransactionBatchExecutedEvent, error) {
-						return nil, nil
-					},
-					GetPeggyERC20DeployedEventsFn: func(_, _ uint64) ([]*peggyevents.PeggyERC20DeployedEvent, error) {
-						return nil, nil
-					},
 				},
 			},
 		},
@@ -560,72 +1050,844 @@ func Test_Oracle(t *testing.T) {
 	}
 }
 
-func Test_Relayer_Valsets(t *testing.T) {
+func Test_Oracle_ResumeFromCheckpoint(t *testing.T) {
 	t.Parallel()
 
+	lastClaimFn := func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+		return &peggytypes.LastClaimEvent{
+			EthereumEventNonce:  102,
+			EthereumEventHeight: 1000,
+		}, nil
+	}
+
+	latestHeaderFn := func(context.Context, *big.Int) (*gethtypes.Header, error) {
+		return &gethtypes.Header{Number: big.NewInt(2100)}, nil
+	}
+
 	testTable := []struct {
 		name     string
-		expected error
-		orch     *Orchestrator
+		expected uint64
+		store    MockStore
 	}{
 		{
-			name:     "failed to get latest valset updates",
-			expected: errors.New("oops"),
-			orch: &Orchestrator{
-				maxAttempts: maxLoopRetries,
-				svcTags:     metrics.Tags{"svc": "relayer"},
-				injective: MockCosmosNetwork{
-					LatestValsetsFn: func(_ context.Context) ([]*peggytypes.Valset, error) {
-						return nil, errors.New("oops")
-					},
+			name:     "no checkpoint",
+			expected: 1000,
+			store: MockStore{
+				OracleCheckpointFn: func(_ gethcommon.Address) (*state.OracleCheckpoint, error) {
+					return nil, state.ErrNotFound
 				},
 			},
 		},
 
 		{
-			name:     "failed to get valset confirmations",
-			expected: errors.New("oops"),
-			orch: &Orchestrator{
-				maxAttempts: maxLoopRetries,
-				svcTags:     metrics.Tags{"svc": "relayer"},
-				injective: MockCosmosNetwork{
-					LatestValsetsFn: func(_ context.Context) ([]*peggytypes.Valset, error) {
-						return []*peggytypes.Valset{{}}, nil // non-empty will do
-					},
-
-					AllValsetConfirmsFn: func(_ context.Context, _ uint64) ([]*peggytypes.MsgValsetConfirm, error) {
-						return nil, errors.New("oops")
-					},
+			name:     "checkpoint ahead of claims on injective",
+			expected: 1000,
+			store: MockStore{
+				OracleCheckpointFn: func(_ gethcommon.Address) (*state.OracleCheckpoint, error) {
+					return &state.OracleCheckpoint{LastObservedEthHeight: 1500, LastClaimedEventNonce: 105}, nil
 				},
 			},
 		},
 
 		{
-			name:     "no new valset to relay",
-			expected: nil,
-			orch: &Orchestrator{
-				logger:      DummyLog,
-				maxAttempts: maxLoopRetries,
-				svcTags:     metrics.Tags{"svc": "relayer"},
-				injective: MockCosmosNetwork{
-					LatestValsetsFn: func(_ context.Context) ([]*peggytypes.Valset, error) {
-						return nil, nil
-					},
-
-					AllValsetConfirmsFn: func(_ context.Context, _ uint64) ([]*peggytypes.MsgValsetConfirm, error) {
-						return nil, nil
-					},
+			name:     "checkpoint ahead of ethereum",
+			expected: 1000,
+			store: MockStore{
+				OracleCheckpointFn: func(_ gethcommon.Address) (*state.OracleCheckpoint, error) {
+					return &state.OracleCheckpoint{LastObservedEthHeight: 3000, LastClaimedEventNonce: 102}, nil
 				},
 			},
 		},
 
 		{
-			name:     "no new valset to relay",
-			expected: nil,
-			orch: &Orchestrator{
-				logger:      DummyLog,
-				maxAttempts: maxLoopRetries,
-				svcTags:     metrics.Tags{"svc": "relayer"},
+			name:     "valid checkpoint",
+			expected: 1500,
+			store: MockStore{
+				OracleCheckpointFn: func(_ gethcommon.Address) (*state.OracleCheckpoint, error) {
+					return &state.OracleCheckpoint{LastObservedEthHeight: 1500, LastClaimedEventNonce: 102}, nil
+				},
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			inj := MockCosmosNetwork{LastClaimEventByAddrFn: lastClaimFn}
+			eth := MockEthereumNetwork{GetHeaderByNumberFn: latestHeaderFn}
+			o := &Orchestrator{
+				logger: DummyLog,
+				store:  tt.store,
+			}
+
+			assert.Equal(t, tt.expected, o.resumeFromCheckpoint(context.Background(), inj, eth, 1000))
+		})
+	}
+}
+
+func Test_Oracle_Reorg(t *testing.T) {
+	t.Parallel()
+
+	ethAddr := gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+
+	// canonical headers differ only by number, so their hashes are deterministic
+	canonicalHeader := func(number int64) *gethtypes.Header {
+		return &gethtypes.Header{Number: big.NewInt(number)}
+	}
+
+	headerFn := func(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+		switch {
+		case number == nil:
+			return canonicalHeader(2100), nil
+		case number.Int64() == int64(rpc.FinalizedBlockNumber):
+			return canonicalHeader(2000), nil
+		default:
+			return canonicalHeader(number.Int64()), nil
+		}
+	}
+
+	testTable := []struct {
+		name                  string
+		policy                ConfirmationPolicy
+		recentBlocks          []state.BlockRef
+		eventBlockHash        gethcommon.Hash
+		expectedScanStart     uint64
+		expectedObservedBlock uint64
+		expectedClaims        int
+	}{
+		{
+			name:                  "finalized policy",
+			policy:                ConfirmationPolicy{Mode: ConfirmationFinalized},
+			eventBlockHash:        canonicalHeader(1800).Hash(),
+			expectedScanStart:     1500,
+			expectedObservedBlock: 2000,
+			expectedClaims:        1,
+		},
+
+		{
+			name:                  "depth policy",
+			policy:                ConfirmationPolicy{Mode: ConfirmationDepth, Depth: 64},
+			eventBlockHash:        canonicalHeader(1800).Hash(),
+			expectedScanStart:     1500,
+			expectedObservedBlock: 2036,
+			expectedClaims:        1,
+		},
+
+		{
+			name:                  "event reorged out during scan",
+			eventBlockHash:        gethcommon.HexToHash("0xdead"),
+			expectedScanStart:     1500,
+			expectedObservedBlock: 1500,
+			expectedClaims:        0,
+		},
+
+		{
+			name: "reorg below cursor",
+			recentBlocks: []state.BlockRef{
+				{Height: 1400, Hash: canonicalHeader(1400).Hash()},
+				{Height: 1500, Hash: gethcommon.HexToHash("0xdead")},
+			},
+			eventBlockHash:        canonicalHeader(1800).Hash(),
+			expectedScanStart:     1400,
+			expectedObservedBlock: 2088,
+			expectedClaims:        1,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				scanStart uint64
+				claims    int
+			)
+
+			orch := &Orchestrator{
+				logger:      DummyLog,
+				cfg:         Config{EthereumAddr: ethAddr, EthConfirmations: tt.policy},
+				maxAttempts: maxLoopRetries,
+				injective: MockCosmosNetwork{
+					CurrentValsetFn: func(_ context.Context) (*peggytypes.Valset, error) {
+						return &peggytypes.Valset{
+							Members: []*peggytypes.BridgeValidator{
+								{
+									EthereumAddress: ethAddr.String(),
+								},
+							},
+						}, nil
+					},
+					LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+						return &peggytypes.LastClaimEvent{
+							EthereumEventNonce:  102,
+							EthereumEventHeight: 1000,
+						}, nil
+					},
+					SendEthereumClaimsFn: func(_ context.Context, sent []peggytypes.EthereumClaim) error {
+						claims += len(sent)
+						return nil
+					},
+				},
+				ethereum: MockEthereumNetwork{
+					GetHeaderByNumberFn: headerFn,
+					GetPeggyEventsFn: func(_ context.Context, start, _ uint64) ([]ethereum.PeggyEvent, error) {
+						scanStart = start
+						return []ethereum.PeggyEvent{
+							{
+								Nonce: 103,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(103),
+									Raw:        gethtypes.Log{BlockNumber: 1800, BlockHash: tt.eventBlockHash},
+								},
+							},
+						}, nil
+					},
+				},
+			}
+
+			o := oracle{
+				Orchestrator:            orch,
+				lastResyncWithInjective: time.Now(), // skip auto resync
+				lastObservedEthHeight:   1500,
+				recentBlocks:            tt.recentBlocks,
+			}
+
+			assert.NoError(t, o.observeEthEvents(context.Background()))
+			assert.Equal(t, tt.expectedScanStart, scanStart)
+			assert.Equal(t, tt.expectedObservedBlock, o.lastObservedEthHeight)
+			assert.Equal(t, tt.expectedClaims, claims)
+		})
+	}
+}
+
+func Test_Oracle_EventQuorum(t *testing.T) {
+	t.Parallel()
+
+	ethAddr := gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+
+	testTable := []struct {
+		name                  string
+		verified              int
+		expectedClaims        []uint64
+		expectedObservedBlock uint64
+	}{
+		{
+			name:                  "all events verified",
+			verified:              2,
+			expectedClaims:        []uint64{103, 104},
+			expectedObservedBlock: 1988,
+		},
+
+		{
+			name:                  "second event not verified",
+			verified:              1,
+			expectedClaims:        []uint64{103},
+			expectedObservedBlock: 1500,
+		},
+
+		{
+			name:                  "no event verified",
+			verified:              0,
+			expectedObservedBlock: 1500,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var claims []uint64
+
+			orch := &Orchestrator{
+				logger:      DummyLog,
+				cfg:         Config{EthereumAddr: ethAddr},
+				maxAttempts: maxLoopRetries,
+				injective: MockCosmosNetwork{
+					CurrentValsetFn: func(_ context.Context) (*peggytypes.Valset, error) {
+						return &peggytypes.Valset{
+							Members: []*peggytypes.BridgeValidator{
+								{
+									EthereumAddress: ethAddr.String(),
+								},
+							},
+						}, nil
+					},
+					LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+						return &peggytypes.LastClaimEvent{
+							EthereumEventNonce:  102,
+							EthereumEventHeight: 1000,
+						}, nil
+					},
+					SendEthereumClaimsFn: func(_ context.Context, sent []peggytypes.EthereumClaim) error {
+						for _, claim := range sent {
+							claims = append(claims, claim.GetEventNonce())
+						}
+
+						return nil
+					},
+				},
+				ethereum: MockEthereumNetwork{
+					GetHeaderByNumberFn: func(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+						if number == nil {
+							return &gethtypes.Header{Number: big.NewInt(2000)}, nil
+						}
+
+						return &gethtypes.Header{Number: number}, nil
+					},
+					GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+						return []ethereum.PeggyEvent{
+							{
+								Nonce: 103,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(103),
+									Raw:        gethtypes.Log{BlockNumber: 1800, BlockHash: (&gethtypes.Header{Number: big.NewInt(1800)}).Hash(), Index: 1},
+								},
+							},
+							{
+								Nonce: 104,
+								Event: &peggyevents.PeggySendToCosmosEvent{
+									EventNonce: big.NewInt(104),
+									Raw:        gethtypes.Log{BlockNumber: 1800, BlockHash: (&gethtypes.Header{Number: big.NewInt(1800)}).Hash(), Index: 2},
+								},
+							},
+						}, nil
+					},
+					VerifyPeggyEventsFn: func(_ context.Context, logs []gethtypes.Log) (int, error) {
+						assert.Equal(t, []uint{1, 2}, []uint{logs[0].Index, logs[1].Index})
+						return tt.verified, nil
+					},
+				},
+			}
+
+			o := oracle{
+				Orchestrator:            orch,
+				lastResyncWithInjective: time.Now(), // skip auto resync
+				lastObservedEthHeight:   1500,
+			}
+
+			assert.NoError(t, o.observeEthEvents(context.Background()))
+			assert.Equal(t, tt.expectedClaims, claims)
+			assert.Equal(t, tt.expectedObservedBlock, o.lastObservedEthHeight)
+		})
+	}
+}
+
+func Test_Oracle_CatchUp(t *testing.T) {
+	t.Parallel()
+
+	ethAddr := gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+
+	var (
+		mux              sync.Mutex
+		scanned          [][2]uint64
+		inFlight         int
+		maxInFlight      int
+		lastClaimedNonce uint64 = 102
+		claims           []uint64
+	)
+
+	// one event every 5000 blocks, starting at block 5000 with nonce 103
+	eventsFn := func(_ context.Context, start, end uint64) ([]ethereum.PeggyEvent, error) {
+		mux.Lock()
+		scanned = append(scanned, [2]uint64{start, end})
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mux.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		var events []ethereum.PeggyEvent
+		for block := (start + 4999) / 5000 * 5000; block <= end; block += 5000 {
+			if block == 0 {
+				continue
+			}
+
+			nonce := 102 + block/5000
+			events = append(events, ethereum.PeggyEvent{
+				Nonce: nonce,
+				Event: &peggyevents.PeggySendToCosmosEvent{
+					EventNonce: new(big.Int).SetUint64(nonce),
+					Amount:     big.NewInt(1),
+					Raw:        gethtypes.Log{BlockNumber: block, BlockHash: (&gethtypes.Header{Number: new(big.Int).SetUint64(block)}).Hash()},
+				},
+			})
+		}
+
+		mux.Lock()
+		inFlight--
+		mux.Unlock()
+
+		return events, nil
+	}
+
+	orch := &Orchestrator{
+		logger:      DummyLog,
+		cfg:         Config{EthereumAddr: ethAddr},
+		maxAttempts: maxLoopRetries,
+		injective: MockCosmosNetwork{
+			CurrentValsetFn: func(_ context.Context) (*peggytypes.Valset, error) {
+				return &peggytypes.Valset{
+					Members: []*peggytypes.BridgeValidator{
+						{
+							EthereumAddress: ethAddr.String(),
+						},
+					},
+				}, nil
+			},
+			LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+				return &peggytypes.LastClaimEvent{EthereumEventNonce: lastClaimedNonce}, nil
+			},
+			SendEthereumClaimsFn: func(_ context.Context, sent []peggytypes.EthereumClaim) error {
+				for _, claim := range sent {
+					claims = append(claims, claim.GetEventNonce())
+				}
+
+				lastClaimedNonce = claims[len(claims)-1]
+				return nil
+			},
+		},
+		ethereum: MockEthereumNetwork{
+			GetHeaderByNumberFn: func(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+				if number == nil {
+					return &gethtypes.Header{Number: big.NewInt(31012)}, nil
+				}
+
+				return &gethtypes.Header{Number: number}, nil
+			},
+			GetPeggyEventsFn: eventsFn,
+		},
+	}
+
+	o := oracle{
+		Orchestrator:            orch,
+		lastResyncWithInjective: time.Now(), // skip auto resync
+		lastObservedEthHeight:   1000,
+	}
+
+	assert.NoError(t, o.observeEthEvents(context.Background()))
+
+	assert.Equal(t, uint64(31000), o.lastObservedEthHeight)
+	assert.Equal(t, []uint64{103, 104, 105, 106, 107, 108}, claims)
+	assert.LessOrEqual(t, maxInFlight, catchUpConcurrency)
+	assert.Greater(t, maxInFlight, 1)
+
+	// every block up to the confirmed height was scanned
+	covered := uint64(1000)
+	for _, r := range sortedRanges(scanned) {
+		assert.LessOrEqual(t, r[0], covered+1)
+		if r[1] > covered {
+			covered = r[1]
+		}
+	}
+
+	assert.Equal(t, uint64(31000), covered)
+}
+
+func Test_Oracle_CatchUp_ConfirmedHeight(t *testing.T) {
+	t.Parallel()
+
+	ethAddr := gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+
+	orch := &Orchestrator{
+		logger:      DummyLog,
+		cfg:         Config{EthereumAddr: ethAddr},
+		maxAttempts: maxLoopRetries,
+		injective: MockCosmosNetwork{
+			CurrentValsetFn: func(_ context.Context) (*peggytypes.Valset, error) {
+				return &peggytypes.Valset{Members: []*peggytypes.BridgeValidator{{EthereumAddress: ethAddr.String()}}}, nil
+			},
+			LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+				return &peggytypes.LastClaimEvent{EthereumEventNonce: 102}, nil
+			},
+		},
+		ethereum: MockEthereumNetwork{
+			GetHeaderByNumberFn: func(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+				if number == nil {
+					return &gethtypes.Header{Number: big.NewInt(25017)}, nil
+				}
+
+				return &gethtypes.Header{Number: number}, nil
+			},
+			GetPeggyEventsFn: func(_ context.Context, _, _ uint64) ([]ethereum.PeggyEvent, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	o := oracle{
+		Orchestrator:            orch,
+		lastResyncWithInjective: time.Now(), // skip auto resync
+		lastObservedEthHeight:   1000,
+	}
+
+	// the last ranges end 5 blocks short of the confirmed height, they are scanned too
+	assert.NoError(t, o.observeEthEvents(context.Background()))
+	assert.Equal(t, uint64(25005), o.lastObservedEthHeight)
+}
+
+func Test_Oracle_LogsWindow(t *testing.T) {
+	t.Parallel()
+
+	ethAddr := gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+
+	var scanned [][2]uint64
+
+	orch := &Orchestrator{
+		logger:      DummyLog,
+		cfg:         Config{EthereumAddr: ethAddr},
+		maxAttempts: maxLoopRetries,
+		injective: MockCosmosNetwork{
+			CurrentValsetFn: func(_ context.Context) (*peggytypes.Valset, error) {
+				return &peggytypes.Valset{Members: []*peggytypes.BridgeValidator{{EthereumAddress: ethAddr.String()}}}, nil
+			},
+			LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+				return &peggytypes.LastClaimEvent{EthereumEventNonce: 102}, nil
+			},
+		},
+		ethereum: MockEthereumNetwork{
+			GetHeaderByNumberFn: func(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+				if number == nil {
+					return &gethtypes.Header{Number: big.NewInt(3012)}, nil
+				}
+
+				return &gethtypes.Header{Number: number}, nil
+			},
+			LogsWindowFn: func() uint64 { return 500 },
+			GetPeggyEventsFn: func(_ context.Context, start, end uint64) ([]ethereum.PeggyEvent, error) {
+				scanned = append(scanned, [2]uint64{start, end})
+				return nil, nil
+			},
+		},
+	}
+
+	o := oracle{
+		Orchestrator:            orch,
+		lastResyncWithInjective: time.Now(), // skip auto resync
+		lastObservedEthHeight:   1000,
+	}
+
+	// the block range follows the window the provider accepts
+	assert.NoError(t, o.observeEthEvents(context.Background()))
+	assert.Equal(t, [][2]uint64{{1000, 1500}}, scanned)
+	assert.Equal(t, uint64(1500), o.lastObservedEthHeight)
+}
+
+func Test_Oracle_LocateMissingEvent(t *testing.T) {
+	t.Parallel()
+
+	ethAddr := gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88")
+
+	peggyEvent := func(nonce, block uint64) ethereum.PeggyEvent {
+		return ethereum.PeggyEvent{
+			Nonce: nonce,
+			Event: &peggyevents.PeggySendToCosmosEvent{
+				EventNonce: new(big.Int).SetUint64(nonce),
+				Amount:     big.NewInt(1),
+				Raw:        gethtypes.Log{BlockNumber: block, BlockHash: (&gethtypes.Header{Number: new(big.Int).SetUint64(block)}).Hash()},
+			},
+		}
+	}
+
+	// event 103 is emitted at block 1500 but the range scan only returns event 104 (block 1800)
+	eventsFn := func(_ context.Context, start, end uint64) ([]ethereum.PeggyEvent, error) {
+		if start == 1500 && end == 1500 {
+			return []ethereum.PeggyEvent{peggyEvent(103, 1500)}, nil
+		}
+
+		return []ethereum.PeggyEvent{peggyEvent(104, 1800)}, nil
+	}
+
+	archiveFn := func(_ context.Context, blockNumber *big.Int) (*big.Int, error) {
+		switch height := blockNumber.Uint64(); {
+		case height >= 1800:
+			return big.NewInt(104), nil
+		case height >= 1500:
+			return big.NewInt(103), nil
+		default:
+			return big.NewInt(102), nil
+		}
+	}
+
+	testTable := []struct {
+		name              string
+		lastEventNonceFn  func(context.Context, *big.Int) (*big.Int, error)
+		expectedClaims    []uint64
+		expectedEthHeight uint64
+	}{
+		{
+			name:              "missing event located with archive state",
+			lastEventNonceFn:  archiveFn,
+			expectedClaims:    []uint64{103, 104},
+			expectedEthHeight: 2100,
+		},
+
+		{
+			name:              "no archive state falls back to rescan",
+			expectedEthHeight: 1000,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var claims []uint64
+
+			orch := &Orchestrator{
+				logger:      DummyLog,
+				cfg:         Config{EthereumAddr: ethAddr},
+				maxAttempts: maxLoopRetries,
+				injective: MockCosmosNetwork{
+					CurrentValsetFn: func(_ context.Context) (*peggytypes.Valset, error) {
+						return &peggytypes.Valset{
+							Members: []*peggytypes.BridgeValidator{
+								{
+									EthereumAddress: ethAddr.String(),
+								},
+							},
+						}, nil
+					},
+					LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+						return &peggytypes.LastClaimEvent{
+							EthereumEventNonce:  102,
+							EthereumEventHeight: 1000,
+						}, nil
+					},
+					SendEthereumClaimsFn: func(_ context.Context, sent []peggytypes.EthereumClaim) error {
+						for _, claim := range sent {
+							claims = append(claims, claim.GetEventNonce())
+						}
+
+						return nil
+					},
+				},
+				ethereum: MockEthereumNetwork{
+					GetHeaderByNumberFn: func(_ context.Context, number *big.Int) (*gethtypes.Header, error) {
+						if number == nil {
+							return &gethtypes.Header{Number: big.NewInt(2112)}, nil
+						}
+
+						return &gethtypes.Header{Number: number}, nil
+					},
+					GetPeggyEventsFn:    eventsFn,
+					GetLastEventNonceFn: tt.lastEventNonceFn,
+				},
+			}
+
+			o := oracle{
+				Orchestrator:            orch,
+				lastResyncWithInjective: time.Now(), // skip auto resync
+				lastObservedEthHeight:   1000,
+			}
+
+			assert.NoError(t, o.observeEthEvents(context.Background()))
+			assert.Equal(t, tt.expectedClaims, claims)
+			assert.Equal(t, tt.expectedEthHeight, o.lastObservedEthHeight)
+		})
+	}
+}
+
+func sortedRanges(ranges [][2]uint64) [][2]uint64 {
+	sorted := append([][2]uint64(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0] < sorted[j][0]
+	})
+
+	return sorted
+}
+
+func Test_Oracle_SendNewEventClaims(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lastClaimedNonce uint64 = 102
+		sent             [][]uint64
+	)
+
+	orch := &Orchestrator{
+		logger:      DummyLog,
+		maxAttempts: 2,
+		injective: MockCosmosNetwork{
+			LastClaimEventByAddrFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.LastClaimEvent, error) {
+				return &peggytypes.LastClaimEvent{EthereumEventNonce: lastClaimedNonce}, nil
+			},
+			SendEthereumClaimsFn: func(_ context.Context, claims []peggytypes.EthereumClaim) error {
+				nonces := make([]uint64, len(claims))
+				for i, claim := range claims {
+					nonces[i] = claim.GetEventNonce()
+				}
+
+				sent = append(sent, nonces)
+				if len(sent) == 1 {
+					// first bundle got through, the second one failed
+					lastClaimedNonce = 104
+					return errors.New("oops")
+				}
+
+				lastClaimedNonce = nonces[len(nonces)-1]
+				return nil
+			},
+		},
+	}
+
+	events := []event{
+		&oldDeposit{EventNonce: big.NewInt(103), Amount: big.NewInt(1)},
+		&withdrawal{EventNonce: big.NewInt(104), BatchNonce: big.NewInt(1)},
+		&deposit{EventNonce: big.NewInt(105), Amount: big.NewInt(1)},
+	}
+
+	o := oracle{Orchestrator: orch}
+
+	assert.NoError(t, o.sendNewEventClaims(context.Background(), events))
+	assert.Equal(t, [][]uint64{{103, 104, 105}, {105}}, sent)
+}
+
+func Test_Oracle_WatchEthereum(t *testing.T) {
+	t.Parallel()
+
+	var (
+		headsCh chan<- *gethtypes.Header
+		logsCh  chan<- gethtypes.Log
+		errCh   = make(chan error, 1)
+		ready   = make(chan struct{})
+	)
+
+	orch := &Orchestrator{
+		logger:      DummyLog,
+		maxAttempts: maxLoopRetries,
+		ethereum: MockEthereumNetwork{
+			SubscribePeggyEventsFn: func(_ context.Context, heads chan<- *gethtypes.Header, logs chan<- gethtypes.Log) (goethereum.Subscription, error) {
+				headsCh, logsCh = heads, logs
+				close(ready)
+				return MockSubscription{ErrCh: errCh}, nil
+			},
+		},
+	}
+
+	o := oracle{Orchestrator: orch}
+	trigger := make(chan struct{}, 1)
+	done := make(chan error)
+
+	go func() {
+		done <- o.watchEthereumOnce(context.Background(), trigger)
+	}()
+
+	<-ready
+
+	// the Oracle catches up right after subscribing
+	<-trigger
+
+	logsCh <- gethtypes.Log{BlockNumber: 100}
+	headsCh <- &gethtypes.Header{Number: big.NewInt(105)} // 5 confirmations
+	headsCh <- &gethtypes.Header{Number: big.NewInt(112)} // 12 confirmations
+
+	select {
+	case <-trigger:
+	case <-time.After(time.Second):
+		t.Fatal("event was not scheduled for claiming after reaching confirmation depth")
+	}
+
+	// a new head without pending events does not trigger the Oracle
+	headsCh <- &gethtypes.Header{Number: big.NewInt(113)}
+
+	errCh <- errors.New("connection reset")
+	assert.EqualError(t, <-done, "connection reset")
+	assert.Len(t, trigger, 0)
+}
+
+func Test_Oracle_WatchEthereum_Disabled(t *testing.T) {
+	t.Parallel()
+
+	orch := &Orchestrator{
+		logger: DummyLog,
+		ethereum: MockEthereumNetwork{
+			SubscribePeggyEventsFn: func(_ context.Context, _ chan<- *gethtypes.Header, _ chan<- gethtypes.Log) (goethereum.Subscription, error) {
+				return nil, ethereum.ErrSubscriptionsDisabled
+			},
+		},
+	}
+
+	o := oracle{Orchestrator: orch}
+	trigger := make(chan struct{}, 1)
+
+	// returns right away, leaving the Oracle in polling mode
+	o.watchEthereum(context.Background(), trigger)
+	assert.Len(t, trigger, 0)
+}
+
+func Test_Relayer_Valsets(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		expected error
+		orch     *Orchestrator
+	}{
+		{
+			name:     "failed to get latest valset updates",
+			expected: errors.New("oops"),
+			orch: &Orchestrator{
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "relayer"},
+				injective: MockCosmosNetwork{
+					LatestValsetsFn: func(_ context.Context) ([]*peggytypes.Valset, error) {
+						return nil, errors.New("oops")
+					},
+				},
+			},
+		},
+
+		{
+			name:     "failed to get valset confirmations",
+			expected: errors.New("oops"),
+			orch: &Orchestrator{
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "relayer"},
+				injective: MockCosmosNetwork{
+					LatestValsetsFn: func(_ context.Context) ([]*peggytypes.Valset, error) {
+						return []*peggytypes.Valset{{}}, nil // non-empty will do
+					},
+
+					AllValsetConfirmsFn: func(_ context.Context, _ uint64) ([]*peggytypes.MsgValsetConfirm, error) {
+						return nil, errors.New("oops")
+					},
+				},
+			},
+		},
+
+		{
+			name:     "no new valset to relay",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "relayer"},
+				injective: MockCosmosNetwork{
+					LatestValsetsFn: func(_ context.Context) ([]*peggytypes.Valset, error) {
+						return nil, nil
+					},
+
+					AllValsetConfirmsFn: func(_ context.Context, _ uint64) ([]*peggytypes.MsgValsetConfirm, error) {
+						return nil, nil
+					},
+				},
+			},
+		},
+
+		{
+			name:     "no new valset to relay",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "relayer"},
 				ethereum: MockEthereumNetwork{
 					GetValsetNonceFn: func(_ context.Context) (*big.Int, error) {
 						return nil, errors.New("oops")
@@ -1064,19 +2326,176 @@ func Test_Relayer_Batches(t *testing.T) {
 			},
 		},
 
-		{
-			name:     "sent batch update",
-			expected: nil,
-			orch: &Orchestrator{
+		{
+			name:     "sent batch update",
+			expected: nil,
+			orch: &Orchestrator{
+				maxAttempts: maxLoopRetries,
+				logger:      DummyLog,
+				svcTags:     metrics.Tags{"svc": "relayer"},
+				cfg:         Config{RelayBatchOffsetDur: 0},
+				injective: MockCosmosNetwork{
+					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return []*peggytypes.OutgoingTxBatch{{
+							BatchNonce: 101,
+						}}, nil
+					},
+
+					TransactionBatchSignaturesFn: func(_ context.Context, _ uint64, _ gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
+						return []*peggytypes.MsgConfirmBatch{{}}, nil
+					},
+
+					GetBlockFn: func(_ context.Context, _ int64) (*cometrpc.ResultBlock, error) {
+						return &cometrpc.ResultBlock{
+							Block: &comettypes.Block{
+								Header: comettypes.Header{Time: time.Now()},
+							},
+						}, nil
+					},
+				},
+				ethereum: MockEthereumNetwork{
+					GetTxBatchNonceFn: func(_ context.Context, _ gethcommon.Address) (*big.Int, error) {
+						return big.NewInt(100), nil
+					},
+
+					GetHeaderByNumberFn: func(_ context.Context, _ *big.Int) (*gethtypes.Header, error) {
+						return &gethtypes.Header{Number: big.NewInt(10)}, nil
+					},
+
+					SendTransactionBatchFn: func(_ context.Context, _ *peggytypes.Valset, _ *peggytypes.OutgoingTxBatch, _ []*peggytypes.MsgConfirmBatch) (*gethcommon.Hash, error) {
+						return &gethcommon.Hash{}, nil
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := relayer{Orchestrator: tt.orch}
+			err := r.relayTokenBatch(context.Background(), &peggytypes.Valset{Nonce: 101})
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_Relayer_BatchProfitability(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tokenAddress = gethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+		otherToken   = gethcommon.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	)
+
+	// 200000 gas at 10 gwei and 2000 USD/ETH is 4 USD, 6 USD with the margin
+	testTable := []struct {
+		name        string
+		margin      float64
+		adjustment  float64
+		fees        []int64
+		otherFees   []int64
+		estimateErr error
+		priceErr    error
+
+		// nonce of the batch relayed, 0 if none
+		expected uint64
+	}{
+		{
+			name:        "check disabled",
+			margin:      0,
+			fees:        []int64{1},
+			estimateErr: errors.New("oops"),
+			expected:    101,
+		},
+		{
+			name:     "fees exceed gas cost",
+			margin:   1.5,
+			fees:     []int64{3_500_000, 3_500_000},
+			expected: 101,
+		},
+		{
+			name:     "fees below gas cost",
+			margin:   1.5,
+			fees:     []int64{2_500_000, 2_500_000},
+			expected: 0,
+		},
+		{
+			name:       "fees below gas cost at the adjusted gas price",
+			margin:     1.5,
+			adjustment: 1.3,
+			fees:       []int64{3_500_000, 3_500_000},
+			expected:   0,
+		},
+		{
+			name:      "next batch relayed when the first is unprofitable",
+			margin:    1.5,
+			fees:      []int64{2_500_000, 2_500_000},
+			otherFees: []int64{7_000_000},
+			expected:  102,
+		},
+		{
+			name:        "batch submission reverts",
+			margin:      1.5,
+			fees:        []int64{100_000_000},
+			estimateErr: errors.New("execution reverted"),
+			expected:    0,
+		},
+		{
+			name:     "token price unknown",
+			margin:   1.5,
+			fees:     []int64{100_000_000},
+			priceErr: errors.New("oops"),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			newBatch := func(nonce uint64, token gethcommon.Address, fees []int64) *peggytypes.OutgoingTxBatch {
+				batch := &peggytypes.OutgoingTxBatch{BatchNonce: nonce, BatchTimeout: 100, TokenContract: token.Hex()}
+				for _, fee := range fees {
+					batch.Transactions = append(batch.Transactions, &peggytypes.OutgoingTransferTx{
+						Erc20Fee: &peggytypes.ERC20Token{Contract: token.Hex(), Amount: sdkmath.NewInt(fee)},
+					})
+				}
+
+				return batch
+			}
+
+			// the last confirmed batch is relayed first
+			batches := []*peggytypes.OutgoingTxBatch{newBatch(101, tokenAddress, tt.fees)}
+			if len(tt.otherFees) > 0 {
+				batches = append([]*peggytypes.OutgoingTxBatch{newBatch(102, otherToken, tt.otherFees)}, batches...)
+			}
+
+			var sent uint64
+
+			r := relayer{Orchestrator: &Orchestrator{
 				maxAttempts: maxLoopRetries,
 				logger:      DummyLog,
 				svcTags:     metrics.Tags{"svc": "relayer"},
-				cfg:         Config{RelayBatchOffsetDur: 0},
+				cfg:         Config{RelayBatchMargin: tt.margin, GasPriceAdjustment: tt.adjustment},
+				priceFeed: MockPriceFeed{QueryUSDPriceFn: func(token gethcommon.Address) (float64, error) {
+					if token == wethAddress {
+						return 2000, nil
+					}
+
+					return 1, tt.priceErr
+				}},
 				injective: MockCosmosNetwork{
 					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
-						return []*peggytypes.OutgoingTxBatch{{
-							BatchNonce: 101,
-						}}, nil
+						return batches, nil
 					},
 
 					TransactionBatchSignaturesFn: func(_ context.Context, _ uint64, _ gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
@@ -1086,7 +2505,7 @@ func Test_Relayer_Batches(t *testing.T) {
 					GetBlockFn: func(_ context.Context, _ int64) (*cometrpc.ResultBlock, error) {
 						return &cometrpc.ResultBlock{
 							Block: &comettypes.Block{
-								Header: comettypes.Header{Time: time.Now()},
+								Header: comettypes.Header{Time: time.Now().Add(-time.Minute)},
 							},
 						}, nil
 					},
@@ -1100,27 +2519,27 @@ func Test_Relayer_Batches(t *testing.T) {
 						return &gethtypes.Header{Number: big.NewInt(10)}, nil
 					},
 
-					SendTransactionBatchFn: func(_ context.Context, _ *peggytypes.Valset, _ *peggytypes.OutgoingTxBatch, _ []*peggytypes.MsgConfirmBatch) (*gethcommon.Hash, error) {
-						return &gethcommon.Hash{}, nil
+					TokenDecimalsFn: func(_ context.Context, _ gethcommon.Address) (uint8, error) {
+						return 6, nil
 					},
-				},
-			},
-		},
-	}
 
-	for _, tt := range testTable {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
+					SuggestGasPriceFn: func(_ context.Context) (*big.Int, error) {
+						return big.NewInt(10_000_000_000), nil
+					},
 
-			r := relayer{Orchestrator: tt.orch}
-			err := r.relayTokenBatch(context.Background(), &peggytypes.Valset{Nonce: 101})
+					EstimateTransactionBatchGasFn: func(_ context.Context, _ *peggytypes.Valset, _ *peggytypes.OutgoingTxBatch, _ []*peggytypes.MsgConfirmBatch) (uint64, error) {
+						return 200_000, tt.estimateErr
+					},
 
-			if tt.expected == nil {
-				assert.NoError(t, err)
-			} else {
-				assert.Error(t, err)
-			}
+					SendTransactionBatchFn: func(_ context.Context, _ *peggytypes.Valset, batch *peggytypes.OutgoingTxBatch, _ []*peggytypes.MsgConfirmBatch) (*gethcommon.Hash, error) {
+						sent = batch.BatchNonce
+						return &gethcommon.Hash{}, nil
+					},
+				},
+			}}
+
+			assert.NoError(t, r.relayTokenBatch(context.Background(), &peggytypes.Valset{Nonce: 101}))
+			assert.Equal(t, tt.expected, sent)
 		})
 	}
 }
@@ -1128,6 +2547,82 @@ func Test_Relayer_Batches(t *testing.T) {
 func Test_Signer_Valsets(t *testing.T) {
 	t.Parallel()
 
+	// three Tendermint validators, the last one did not register delegate keys
+	var (
+		consKeys          = []cryptotypes.PubKey{ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey()}
+		votingPowers      = []int64{30, 10, 20}
+		ethAddrs          = []gethcommon.Address{gethcommon.HexToAddress("0x76D2dDbb89C36FA39FAa5c5e7C61ee95AC4D76C4"), gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88"), {}}
+		stakingValidators []stakingtypes.Validator
+		tmValidators      []*comettypes.Validator
+		delegateKeys      = make(map[string]gethcommon.Address)
+	)
+
+	for i, consKey := range consKeys {
+		valAddr := cosmostypes.ValAddress(consKey.Address())
+
+		v, err := stakingtypes.NewValidator(valAddr.String(), consKey, stakingtypes.Description{})
+		assert.NoError(t, err)
+
+		stakingValidators = append(stakingValidators, v)
+		tmValidators = append(tmValidators, &comettypes.Validator{Address: consKey.Address(), VotingPower: votingPowers[i]})
+		delegateKeys[cosmostypes.AccAddress(valAddr).String()] = ethAddrs[i]
+	}
+
+	// powers normalized to 2^32 - 1 over the 40 power of the bridge validators
+	valset := &peggytypes.Valset{
+		Nonce:  5,
+		Height: 100,
+		Members: []*peggytypes.BridgeValidator{
+			{Power: 1073741823, EthereumAddress: ethAddrs[1].Hex()},
+			{Power: 3221225471, EthereumAddress: ethAddrs[0].Hex()},
+		},
+	}
+
+	forgedValset := &peggytypes.Valset{
+		Nonce:  5,
+		Height: 100,
+		Members: []*peggytypes.BridgeValidator{
+			{Power: 1073741823, EthereumAddress: ethAddrs[1].Hex()},
+			{Power: 3221225471, EthereumAddress: gethcommon.HexToAddress("0xdead").Hex()},
+		},
+	}
+
+	rewardedValset := &peggytypes.Valset{
+		Nonce:        valset.Nonce,
+		Height:       valset.Height,
+		Members:      valset.Members,
+		RewardAmount: sdkmath.ZeroInt(),
+	}
+
+	validatorsNetwork := func(latestHeight int64, valsets []*peggytypes.Valset, sendFn func(context.Context, gethcommon.Address, gethcommon.Hash, *peggytypes.Valset) error) MockCosmosNetwork {
+		return MockCosmosNetwork{
+			OldestUnsignedValsetsFn: func(_ context.Context, _ cosmostypes.AccAddress) ([]*peggytypes.Valset, error) {
+				return valsets, nil
+			},
+			GetLatestBlockHeightFn: func(_ context.Context) (int64, error) {
+				return latestHeight, nil
+			},
+			ValidatorsFn: func(_ context.Context, height int64) ([]stakingtypes.Validator, error) {
+				if height != int64(valset.Height) {
+					return nil, errors.New("validators queried at the wrong height")
+				}
+
+				return stakingValidators, nil
+			},
+			GetValidatorSetFn: func(_ context.Context, _ int64) (*cometrpc.ResultValidators, error) {
+				return &cometrpc.ResultValidators{Validators: tmValidators, Count: len(tmValidators), Total: len(tmValidators)}, nil
+			},
+			GetDelegateEthereumAddressFn: func(_ context.Context, validator cosmostypes.AccAddress) (gethcommon.Address, error) {
+				if addr := delegateKeys[validator.String()]; addr != (gethcommon.Address{}) {
+					return addr, nil
+				}
+
+				return gethcommon.Address{}, peggy.ErrNotFound
+			},
+			SendValsetConfirmFn: sendFn,
+		}
+	}
+
 	testTable := []struct {
 		name     string
 		expected error
@@ -1167,15 +2662,9 @@ func Test_Signer_Valsets(t *testing.T) {
 			orch: &Orchestrator{
 				logger:      DummyLog,
 				maxAttempts: maxLoopRetries,
-				injective: MockCosmosNetwork{
-					OldestUnsignedValsetsFn: func(_ context.Context, _ cosmostypes.AccAddress) ([]*peggytypes.Valset, error) {
-						return []*peggytypes.Valset{{}}, nil
-					},
-
-					SendValsetConfirmFn: func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
-						return errors.New("oops")
-					},
-				},
+				injective: validatorsNetwork(102, []*peggytypes.Valset{valset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+					return errors.New("oops")
+				}),
 			},
 		},
 
@@ -1185,15 +2674,115 @@ func Test_Signer_Valsets(t *testing.T) {
 			orch: &Orchestrator{
 				logger:      DummyLog,
 				maxAttempts: maxLoopRetries,
-				injective: MockCosmosNetwork{
-					OldestUnsignedValsetsFn: func(_ context.Context, _ cosmostypes.AccAddress) ([]*peggytypes.Valset, error) {
-						return []*peggytypes.Valset{{}}, nil
+				injective: validatorsNetwork(102, []*peggytypes.Valset{valset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+					return nil
+				}),
+			},
+		},
+
+		{
+			name:     "forged valset is not signed",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "signer"},
+				injective: validatorsNetwork(102, []*peggytypes.Valset{forgedValset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+					return errors.New("forged valset signed")
+				}),
+			},
+		},
+
+		{
+			name:     "conflicting valset signature is refused",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "signer"},
+				store: MockStore{
+					CheckAndRecordSignatureFn: func(d state.SignedDigest) error {
+						return state.ErrConflictingSignature
 					},
+				},
+				injective: validatorsNetwork(102, []*peggytypes.Valset{rewardedValset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+					return errors.New("conflicting valset signed")
+				}),
+			},
+		},
+
+		{
+			name:     "valset signature recorded",
+			expected: errors.New("recorded"),
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				store: MockStore{
+					CheckAndRecordSignatureFn: func(d state.SignedDigest) error {
+						if d.Kind != state.ValsetSignature || d.Nonce != 5 {
+							return errors.New("unexpected signature")
+						}
 
-					SendValsetConfirmFn: func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
 						return nil
 					},
 				},
+				injective: validatorsNetwork(102, []*peggytypes.Valset{rewardedValset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+					return errors.New("recorded")
+				}),
+			},
+		},
+
+		{
+			name:     "valset height pruned",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "signer"},
+				injective: func() MockCosmosNetwork {
+					n := validatorsNetwork(102, []*peggytypes.Valset{valset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+						return errors.New("unverified valset signed")
+					})
+
+					n.GetValidatorSetFn = func(_ context.Context, _ int64) (*cometrpc.ResultValidators, error) {
+						return nil, errors.New("height 101 is not available, lowest height is 5000")
+					}
+
+					return n
+				}(),
+			},
+		},
+
+		{
+			name:     "unknown Tendermint validator",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "signer"},
+				injective: func() MockCosmosNetwork {
+					n := validatorsNetwork(102, []*peggytypes.Valset{valset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+						return errors.New("unverified valset signed")
+					})
+
+					n.ValidatorsFn = func(_ context.Context, _ int64) ([]stakingtypes.Validator, error) {
+						return stakingValidators[1:], nil
+					}
+
+					return n
+				}(),
+			},
+		},
+
+		{
+			name:     "valset not verifiable yet",
+			expected: nil,
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				injective: validatorsNetwork(100, []*peggytypes.Valset{valset}, func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.Valset) error {
+					return errors.New("unverified valset signed")
+				}),
 			},
 		},
 	}
@@ -1218,19 +2807,39 @@ func Test_Signer_Valsets(t *testing.T) {
 func Test_Signer_Batches(t *testing.T) {
 	t.Parallel()
 
+	cosmosAddr := cosmostypes.AccAddress("orchestrator")
+
+	// batches of two tokens, the second token's batch 3 was already confirmed by our orchestrator
+	batches := []*peggytypes.OutgoingTxBatch{
+		{BatchNonce: 5, TokenContract: "0xB8c77482e45F1F44dE1745F52C74426C631bDD52"},
+		{BatchNonce: 4, TokenContract: "0x3959f5246c452463279F690301D923D5a75bbD88"},
+		{BatchNonce: 2, TokenContract: "0xB8c77482e45F1F44dE1745F52C74426C631bDD52"},
+		{BatchNonce: 3, TokenContract: "0x3959f5246c452463279F690301D923D5a75bbD88"},
+	}
+
+	signaturesFn := func(_ context.Context, nonce uint64, _ gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
+		confirms := []*peggytypes.MsgConfirmBatch{{Orchestrator: cosmostypes.AccAddress("other").String()}}
+		if nonce == 3 {
+			confirms = append(confirms, &peggytypes.MsgConfirmBatch{Orchestrator: cosmosAddr.String()})
+		}
+
+		return confirms, nil
+	}
+
 	testTable := []struct {
-		name     string
-		expected error
-		orch     *Orchestrator
+		name            string
+		expected        error
+		expectedBatches []uint64
+		orch            *Orchestrator
 	}{
 		{
-			name:     "failed to get unsigned batches/no batch to confirm",
-			expected: nil,
+			name:     "failed to get unsigned batches",
+			expected: errors.New("ooops"),
 			orch: &Orchestrator{
 				logger:      DummyLog,
 				maxAttempts: maxLoopRetries,
 				injective: MockCosmosNetwork{
-					OldestUnsignedTransactionBatchFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.OutgoingTxBatch, error) {
+					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
 						return nil, errors.New("ooops")
 					},
 				},
@@ -1238,38 +2847,84 @@ func Test_Signer_Batches(t *testing.T) {
 		},
 
 		{
-			name:     "failed to send batch confirm",
-			expected: errors.New("oops"),
+			name:     "no batch to confirm",
+			expected: nil,
 			orch: &Orchestrator{
 				logger:      DummyLog,
 				maxAttempts: maxLoopRetries,
 				injective: MockCosmosNetwork{
+					// the batches and their confirmations are not queried
 					OldestUnsignedTransactionBatchFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.OutgoingTxBatch, error) {
-						return &peggytypes.OutgoingTxBatch{}, nil
-					},
-
-					SendBatchConfirmFn: func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.OutgoingTxBatch) error {
-						return errors.New("oops")
+						return nil, nil
 					},
 				},
 			},
 		},
 
 		{
-			name:     "sent batch confirm",
+			name:     "failed batch confirms do not stop the signer",
 			expected: nil,
 			orch: &Orchestrator{
 				logger:      DummyLog,
 				maxAttempts: maxLoopRetries,
+				cfg:         Config{CosmosAddr: cosmosAddr},
 				injective: MockCosmosNetwork{
-					OldestUnsignedTransactionBatchFn: func(_ context.Context, _ cosmostypes.AccAddress) (*peggytypes.OutgoingTxBatch, error) {
-						return &peggytypes.OutgoingTxBatch{}, nil
+					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return batches, nil
+					},
+
+					TransactionBatchSignaturesFn: signaturesFn,
+
+					SendBatchConfirmsFn: func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error) {
+						return nil, errors.New("oops")
 					},
+				},
+			},
+		},
+
+		{
+			name:            "conflicting batch signature is refused",
+			expected:        nil,
+			expectedBatches: []uint64{4, 5},
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "signer"},
+				cfg:         Config{CosmosAddr: cosmosAddr},
+				store: MockStore{
+					CheckAndRecordSignatureFn: func(d state.SignedDigest) error {
+						if d.Nonce == 2 {
+							return state.ErrConflictingSignature
+						}
 
-					SendBatchConfirmFn: func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, _ *peggytypes.OutgoingTxBatch) error {
 						return nil
 					},
 				},
+				injective: MockCosmosNetwork{
+					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return batches, nil
+					},
+
+					TransactionBatchSignaturesFn: signaturesFn,
+				},
+			},
+		},
+
+		{
+			name:            "sent all batch confirms",
+			expected:        nil,
+			expectedBatches: []uint64{4, 2, 5},
+			orch: &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				cfg:         Config{CosmosAddr: cosmosAddr},
+				injective: MockCosmosNetwork{
+					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return batches, nil
+					},
+
+					TransactionBatchSignaturesFn: signaturesFn,
+				},
 			},
 		},
 	}
@@ -1279,14 +2934,295 @@ func Test_Signer_Batches(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
+			var confirmed []uint64
+			if inj := tt.orch.injective.(MockCosmosNetwork); inj.SendBatchConfirmsFn == nil {
+				inj.SendBatchConfirmsFn = func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error) {
+					for _, batch := range batches {
+						confirmed = append(confirmed, batch.BatchNonce)
+					}
+
+					return batches, nil
+				}
+
+				tt.orch.injective = inj
+			}
+
 			s := signer{Orchestrator: tt.orch}
-			err := s.signNewBatch(context.Background())
+			err := s.signBatches(context.Background())
 
 			if tt.expected == nil {
 				assert.NoError(t, err)
 			} else {
 				assert.Error(t, err)
 			}
+
+			assert.Equal(t, tt.expectedBatches, confirmed)
+		})
+	}
+}
+
+func Test_Signer_OutflowLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cosmosAddr = cosmostypes.AccAddress("orchestrator")
+		usdt       = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+		weth       = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	)
+
+	// amounts in token units of a token with 6 decimals, a tenth of them paid as fee
+	batch := func(token string, nonce uint64, amount int64) *peggytypes.OutgoingTxBatch {
+		return &peggytypes.OutgoingTxBatch{
+			BatchNonce:    nonce,
+			TokenContract: token,
+			Transactions: []*peggytypes.OutgoingTransferTx{{
+				Id:         nonce,
+				Erc20Token: &peggytypes.ERC20Token{Contract: token, Amount: sdkmath.NewInt(amount * 900_000)},
+				Erc20Fee:   &peggytypes.ERC20Token{Contract: token, Amount: sdkmath.NewInt(amount * 100_000)},
+			}},
+		}
+	}
+
+	limits := &outflow.Limits{
+		Tokens: map[gethcommon.Address]outflow.Limit{
+			gethcommon.HexToAddress(usdt): {
+				Window:          24 * time.Hour,
+				MaxBatchAmount:  decimal.NewFromInt(100),
+				MaxWindowAmount: decimal.NewFromInt(150),
+			},
+		},
+	}
+
+	usdLimits := &outflow.Limits{
+		Default: &outflow.Limit{MaxBatchUSD: decimal.NewFromInt(1000)},
+	}
+
+	testTable := []struct {
+		name            string
+		limits          *outflow.Limits
+		batches         []*peggytypes.OutgoingTxBatch
+		recorded        []state.OutflowRecord
+		approve         []uint64
+		conflicting     []uint64
+		priceErr        error
+		decimalsErr     error // of weth only
+		failing         []uint64
+		expectedBatches []uint64
+		expectedHeld    []uint64
+
+		// batches of tokens without limits are not recorded
+		expectedRecorded int
+	}{
+		{
+			name:             "batches within limits are signed",
+			limits:           limits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 50), batch(usdt, 3, 100)},
+			expectedBatches:  []uint64{2, 3},
+			expectedRecorded: 2,
+		},
+
+		{
+			name:            "batch over the batch limit is held",
+			limits:          limits,
+			batches:         []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 101), batch(weth, 1, 1000)},
+			expectedBatches: []uint64{1},
+			expectedHeld:    []uint64{2},
+		},
+
+		{
+			name:             "window limit counts the batches signed before",
+			limits:           limits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 50), batch(usdt, 3, 60)},
+			recorded:         []state.OutflowRecord{{TokenContract: usdt, BatchNonce: 1, Amount: decimal.NewFromInt(90)}},
+			expectedBatches:  []uint64{2},
+			expectedHeld:     []uint64{3},
+			expectedRecorded: 2,
+		},
+
+		{
+			name:             "window limit counts the batches signed in the same loop",
+			limits:           limits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 100), batch(usdt, 3, 60)},
+			expectedBatches:  []uint64{2},
+			expectedHeld:     []uint64{3},
+			expectedRecorded: 1,
+		},
+
+		{
+			name:             "batch refused by slashing protection is not recorded",
+			limits:           limits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 100), batch(usdt, 3, 50)},
+			conflicting:      []uint64{2},
+			expectedBatches:  []uint64{3},
+			expectedRecorded: 1,
+		},
+
+		{
+			name:             "batches failing to broadcast are not recorded",
+			limits:           limits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 50), batch(usdt, 3, 50)},
+			failing:          []uint64{2},
+			expectedBatches:  []uint64{2, 3},
+			expectedRecorded: 1,
+		},
+
+		{
+			name:             "approved batch is signed",
+			limits:           limits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(usdt, 2, 500)},
+			approve:          []uint64{2},
+			expectedBatches:  []uint64{2},
+			expectedRecorded: 1,
+		},
+
+		{
+			name:             "batch within USD limit is signed",
+			limits:           usdLimits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(weth, 2, 999)},
+			expectedBatches:  []uint64{2},
+			expectedRecorded: 1,
+		},
+
+		{
+			name:         "batch is held without token price",
+			limits:       usdLimits,
+			batches:      []*peggytypes.OutgoingTxBatch{batch(weth, 2, 1)},
+			priceErr:     errors.New("rate limited"),
+			expectedHeld: []uint64{2},
+		},
+
+		{
+			name:             "batch is held without token decimals, the others are signed",
+			limits:           usdLimits,
+			batches:          []*peggytypes.OutgoingTxBatch{batch(weth, 2, 1), batch(usdt, 3, 1)},
+			decimalsErr:      errors.New("eth node unavailable"),
+			expectedBatches:  []uint64{3},
+			expectedHeld:     []uint64{2},
+			expectedRecorded: 1,
+		},
+	}
+
+	for _, tt := range testTable {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queue, err := outflow.NewQueue(t.TempDir())
+			assert.NoError(t, err)
+
+			var (
+				mux       sync.Mutex
+				recorded  = tt.recorded
+				confirmed []uint64
+			)
+
+			orch := &Orchestrator{
+				logger:      DummyLog,
+				maxAttempts: maxLoopRetries,
+				svcTags:     metrics.Tags{"svc": "signer"},
+				cfg:         Config{CosmosAddr: cosmosAddr, OutflowLimits: tt.limits, OutflowQueue: queue},
+				priceFeed: MockPriceFeed{QueryUSDPriceFn: func(_ gethcommon.Address) (float64, error) {
+					return 1, tt.priceErr
+				}},
+				store: MockStore{
+					CheckAndRecordSignatureFn: func(d state.SignedDigest) error {
+						for _, nonce := range tt.conflicting {
+							if d.Nonce == nonce {
+								return state.ErrConflictingSignature
+							}
+						}
+
+						return nil
+					},
+					RecordOutflowFn: func(r state.OutflowRecord) error {
+						mux.Lock()
+						defer mux.Unlock()
+
+						recorded = append(recorded, r)
+						return nil
+					},
+					OutflowsFn: func(token string, _ time.Time) ([]state.OutflowRecord, error) {
+						mux.Lock()
+						defer mux.Unlock()
+
+						var records []state.OutflowRecord
+						for _, r := range recorded {
+							if r.TokenContract == token {
+								records = append(records, r)
+							}
+						}
+
+						return records, nil
+					},
+				},
+				ethereum: MockEthereumNetwork{
+					TokenDecimalsFn: func(_ context.Context, token gethcommon.Address) (uint8, error) {
+						if token == gethcommon.HexToAddress(weth) && tt.decimalsErr != nil {
+							return 0, tt.decimalsErr
+						}
+
+						return 6, nil
+					},
+				},
+				injective: MockCosmosNetwork{
+					LatestTransactionBatchesFn: func(_ context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+						return tt.batches, nil
+					},
+					TransactionBatchSignaturesFn: func(_ context.Context, _ uint64, _ gethcommon.Address) ([]*peggytypes.MsgConfirmBatch, error) {
+						return nil, nil
+					},
+					SendBatchConfirmsFn: func(_ context.Context, _ gethcommon.Address, _ gethcommon.Hash, batches []*peggytypes.OutgoingTxBatch) ([]*peggytypes.OutgoingTxBatch, error) {
+						var (
+							sent []*peggytypes.OutgoingTxBatch
+							err  error
+						)
+
+					batches:
+						for _, batch := range batches {
+							confirmed = append(confirmed, batch.BatchNonce)
+
+							for _, nonce := range tt.failing {
+								if batch.BatchNonce == nonce {
+									err = errors.New("fail")
+									continue batches
+								}
+							}
+
+							sent = append(sent, batch)
+						}
+
+						return sent, err
+					},
+				},
+			}
+
+			s := signer{Orchestrator: orch}
+
+			for _, nonce := range tt.approve {
+				for _, b := range tt.batches {
+					if b.BatchNonce == nonce {
+						assert.NoError(t, queue.Hold(outflow.HeldBatch{TokenContract: b.TokenContract, BatchNonce: nonce, Digest: ethpeggy.EncodeTxBatchConfirm(s.peggyID, b)}))
+						_, err := queue.Approve(b.TokenContract, nonce)
+						assert.NoError(t, err)
+					}
+				}
+			}
+
+			assert.NoError(t, s.signBatches(context.Background()))
+
+			sort.Slice(confirmed, func(i, j int) bool { return confirmed[i] < confirmed[j] })
+			assert.Equal(t, tt.expectedBatches, confirmed)
+
+			held, err := queue.Held()
+			assert.NoError(t, err)
+
+			var heldNonces []uint64
+			for _, b := range held {
+				heldNonces = append(heldNonces, b.BatchNonce)
+			}
+
+			assert.Equal(t, tt.expectedHeld, heldNonces)
+			assert.Len(t, recorded, tt.expectedRecorded)
 		})
 	}
 }
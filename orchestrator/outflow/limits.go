@@ -0,0 +1,186 @@
+package outflow
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ErrLimitExceeded is returned when a batch would withdraw more than the limits of its token allow.
+var ErrLimitExceeded = errors.New("outflow limit exceeded")
+
+// Limit bounds the amount of a token withdrawn through the batches signed by the orchestrator. Amounts are in
+// token units, USD values use the price feed. A zero value means no limit.
+type Limit struct {
+	// Window is the rolling time window of MaxWindowAmount and MaxWindowUSD
+	Window time.Duration
+
+	MaxBatchAmount  decimal.Decimal
+	MaxWindowAmount decimal.Decimal
+	MaxBatchUSD     decimal.Decimal
+	MaxWindowUSD    decimal.Decimal
+}
+
+// Limits are the outflow limits of every token. Tokens without limits of their own use Default, if set.
+type Limits struct {
+	Default *Limit
+	Tokens  map[gethcommon.Address]Limit
+}
+
+// Outflow is the amount a batch withdraws, along with the amount already withdrawn in the window of its limit.
+// USD values are nil if the token price is unknown.
+type Outflow struct {
+	Batch     decimal.Decimal
+	Window    decimal.Decimal
+	BatchUSD  *decimal.Decimal
+	WindowUSD *decimal.Decimal
+}
+
+type limitsFile struct {
+	Default *limitEntry           `json:"default"`
+	Tokens  map[string]limitEntry `json:"tokens"`
+}
+
+type limitEntry struct {
+	Window          string `json:"window"`
+	MaxBatchAmount  string `json:"max_batch_amount"`
+	MaxWindowAmount string `json:"max_window_amount"`
+	MaxBatchUSD     string `json:"max_batch_usd"`
+	MaxWindowUSD    string `json:"max_window_usd"`
+}
+
+// LoadLimits reads the outflow limits from a JSON file:
+//
+//	{
+//	  "default": {"window": "24h", "max_window_usd": "1000000"},
+//	  "tokens": {
+//	    "0xdAC17F958D2ee523a2206206994597C13D831ec7": {"window": "24h", "max_batch_amount": "100000", "max_window_amount": "500000"}
+//	  }
+//	}
+func LoadLimits(file string) (*Limits, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read outflow limits")
+	}
+
+	var f limitsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode outflow limits in %s", file)
+	}
+
+	limits := &Limits{Tokens: make(map[gethcommon.Address]Limit, len(f.Tokens))}
+
+	if f.Default != nil {
+		limit, err := f.Default.parse()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid default outflow limit")
+		}
+
+		limits.Default = &limit
+	}
+
+	for token, entry := range f.Tokens {
+		if !gethcommon.IsHexAddress(token) {
+			return nil, errors.Errorf("invalid token contract %q in outflow limits", token)
+		}
+
+		limit, err := entry.parse()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid outflow limit of token %s", token)
+		}
+
+		limits.Tokens[gethcommon.HexToAddress(token)] = limit
+	}
+
+	return limits, nil
+}
+
+func (e limitEntry) parse() (Limit, error) {
+	var (
+		limit Limit
+		err   error
+	)
+
+	if e.Window != "" {
+		if limit.Window, err = time.ParseDuration(e.Window); err != nil {
+			return Limit{}, errors.Wrap(err, "invalid window")
+		}
+	}
+
+	for _, v := range []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{"max_batch_amount", e.MaxBatchAmount, &limit.MaxBatchAmount},
+		{"max_window_amount", e.MaxWindowAmount, &limit.MaxWindowAmount},
+		{"max_batch_usd", e.MaxBatchUSD, &limit.MaxBatchUSD},
+		{"max_window_usd", e.MaxWindowUSD, &limit.MaxWindowUSD},
+	} {
+		if strings.TrimSpace(v.value) == "" {
+			continue
+		}
+
+		if *v.dst, err = decimal.NewFromString(v.value); err != nil {
+			return Limit{}, errors.Wrapf(err, "invalid %s", v.name)
+		}
+
+		if v.dst.IsNegative() {
+			return Limit{}, errors.Errorf("%s cannot be negative", v.name)
+		}
+	}
+
+	if limit.Window <= 0 && (!limit.MaxWindowAmount.IsZero() || !limit.MaxWindowUSD.IsZero()) {
+		return Limit{}, errors.New("window must be set along with max_window_amount or max_window_usd")
+	}
+
+	return limit, nil
+}
+
+// For returns the limit of the token, if any.
+func (l *Limits) For(token gethcommon.Address) (Limit, bool) {
+	if l == nil {
+		return Limit{}, false
+	}
+
+	if limit, ok := l.Tokens[token]; ok {
+		return limit, true
+	}
+
+	if l.Default != nil {
+		return *l.Default, true
+	}
+
+	return Limit{}, false
+}
+
+// UsesUSD reports whether the limit needs the token price.
+func (l Limit) UsesUSD() bool {
+	return !l.MaxBatchUSD.IsZero() || !l.MaxWindowUSD.IsZero()
+}
+
+// Check returns ErrLimitExceeded if the batch goes over the limit. A USD limit cannot be checked without the
+// token price, so the batch is refused as well.
+func (l Limit) Check(o Outflow) error {
+	windowTotal := o.Window.Add(o.Batch)
+
+	switch {
+	case !l.MaxBatchAmount.IsZero() && o.Batch.GreaterThan(l.MaxBatchAmount):
+		return errors.Wrapf(ErrLimitExceeded, "batch amount %s is above the limit of %s per batch", o.Batch, l.MaxBatchAmount)
+	case !l.MaxWindowAmount.IsZero() && windowTotal.GreaterThan(l.MaxWindowAmount):
+		return errors.Wrapf(ErrLimitExceeded, "amount %s over the last %s is above the limit of %s", windowTotal, l.Window, l.MaxWindowAmount)
+	case l.UsesUSD() && (o.BatchUSD == nil || o.WindowUSD == nil):
+		return errors.Wrap(ErrLimitExceeded, "USD value is unknown")
+	case !l.MaxBatchUSD.IsZero() && o.BatchUSD.GreaterThan(l.MaxBatchUSD):
+		return errors.Wrapf(ErrLimitExceeded, "batch value %s USD is above the limit of %s USD per batch", o.BatchUSD.StringFixed(2), l.MaxBatchUSD)
+	case !l.MaxWindowUSD.IsZero() && o.WindowUSD.Add(*o.BatchUSD).GreaterThan(l.MaxWindowUSD):
+		return errors.Wrapf(ErrLimitExceeded, "value %s USD over the last %s is above the limit of %s USD", o.WindowUSD.Add(*o.BatchUSD).StringFixed(2), l.Window, l.MaxWindowUSD)
+	}
+
+	return nil
+}
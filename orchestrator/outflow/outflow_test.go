@@ -0,0 +1,166 @@
+package outflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	usdt = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	weth = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func TestLoadLimits(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "limits.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{
+		"default": {"window": "24h", "max_window_usd": "1000000"},
+		"tokens": {
+			"`+usdt+`": {"window": "12h", "max_batch_amount": "100000", "max_window_amount": "500000.5"}
+		}
+	}`), 0o600))
+
+	limits, err := LoadLimits(file)
+	require.NoError(t, err)
+
+	limit, ok := limits.For(gethcommon.HexToAddress(usdt))
+	require.True(t, ok)
+	assert.Equal(t, 12*time.Hour, limit.Window)
+	assert.True(t, limit.MaxBatchAmount.Equal(dec("100000")))
+	assert.True(t, limit.MaxWindowAmount.Equal(dec("500000.5")))
+	assert.False(t, limit.UsesUSD())
+
+	limit, ok = limits.For(gethcommon.HexToAddress(weth))
+	require.True(t, ok)
+	assert.Equal(t, 24*time.Hour, limit.Window)
+	assert.True(t, limit.UsesUSD())
+
+	_, ok = (&Limits{}).For(gethcommon.HexToAddress(weth))
+	assert.False(t, ok)
+
+	for name, content := range map[string]string{
+		"invalid token":       `{"tokens": {"usdt": {"max_batch_amount": "1"}}}`,
+		"negative amount":     `{"tokens": {"` + usdt + `": {"max_batch_amount": "-1"}}}`,
+		"invalid amount":      `{"default": {"max_batch_usd": "lots"}}`,
+		"window limit only":   `{"default": {"max_window_amount": "1"}}`,
+		"invalid window":      `{"default": {"window": "1 day", "max_window_amount": "1"}}`,
+		"not a limits object": `[]`,
+	} {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+		_, err := LoadLimits(file)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLimitCheck(t *testing.T) {
+	limit := Limit{
+		Window:          24 * time.Hour,
+		MaxBatchAmount:  dec("100"),
+		MaxWindowAmount: dec("250"),
+	}
+
+	usdLimit := Limit{
+		Window:       24 * time.Hour,
+		MaxBatchUSD:  dec("1000"),
+		MaxWindowUSD: dec("2000"),
+	}
+
+	testTable := []struct {
+		name     string
+		limit    Limit
+		outflow  Outflow
+		exceeded bool
+	}{
+		{name: "within limits", limit: limit, outflow: Outflow{Batch: dec("100"), Window: dec("150")}},
+		{name: "batch over limit", limit: limit, outflow: Outflow{Batch: dec("100.01")}, exceeded: true},
+		{name: "window over limit", limit: limit, outflow: Outflow{Batch: dec("50"), Window: dec("200.5")}, exceeded: true},
+		{name: "no limit", limit: Limit{}, outflow: Outflow{Batch: dec("1000000")}},
+		{name: "within USD limits", limit: usdLimit, outflow: Outflow{Batch: dec("1"), BatchUSD: decPtr("999"), WindowUSD: decPtr("1001")}},
+		{name: "batch over USD limit", limit: usdLimit, outflow: Outflow{Batch: dec("1"), BatchUSD: decPtr("1000.01"), WindowUSD: decPtr("0")}, exceeded: true},
+		{name: "window over USD limit", limit: usdLimit, outflow: Outflow{Batch: dec("1"), BatchUSD: decPtr("500"), WindowUSD: decPtr("1600")}, exceeded: true},
+		{name: "unknown USD value", limit: usdLimit, outflow: Outflow{Batch: dec("1")}, exceeded: true},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limit.Check(tt.outflow)
+			if tt.exceeded {
+				assert.ErrorIs(t, err, ErrLimitExceeded)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQueue(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := NewQueue(dir)
+	require.NoError(t, err)
+
+	_, err = q.Approve(usdt, 4)
+	assert.ErrorIs(t, err, ErrNotHeld)
+
+	heldAt := time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, q.Hold(HeldBatch{TokenContract: usdt, BatchNonce: 4, Digest: gethcommon.HexToHash("0x04"), Amount: dec("1000"), Reason: "too much", HeldAt: heldAt}))
+	require.NoError(t, q.Hold(HeldBatch{TokenContract: weth, BatchNonce: 2, Digest: gethcommon.HexToHash("0x02"), Amount: dec("3")}))
+
+	// holding the batch again keeps the time it was first held at
+	require.NoError(t, q.Hold(HeldBatch{TokenContract: usdt, BatchNonce: 4, Digest: gethcommon.HexToHash("0x04"), Amount: dec("1000"), Reason: "too much"}))
+
+	held, err := q.Held()
+	require.NoError(t, err)
+	require.Len(t, held, 2)
+	assert.Equal(t, weth, held[0].TokenContract)
+	assert.Equal(t, usdt, held[1].TokenContract)
+	assert.True(t, heldAt.Equal(held[1].HeldAt))
+
+	approved, err := q.Approved(usdt, 4, gethcommon.HexToHash("0x04"))
+	require.NoError(t, err)
+	assert.False(t, approved)
+
+	// the CLI approves through its own queue on the same dir
+	cli, err := NewQueue(dir)
+	require.NoError(t, err)
+
+	b, err := cli.Approve(usdt, 4)
+	require.NoError(t, err)
+	assert.Equal(t, gethcommon.HexToHash("0x04"), b.Digest)
+
+	approved, err = q.Approved(usdt, 4, gethcommon.HexToHash("0x04"))
+	require.NoError(t, err)
+	assert.True(t, approved)
+
+	// the approval does not cover another checkpoint with the same nonce
+	approved, err = q.Approved(usdt, 4, gethcommon.HexToHash("0x05"))
+	require.NoError(t, err)
+	assert.False(t, approved)
+
+	require.NoError(t, q.Release(usdt, 4))
+	require.NoError(t, q.Release(usdt, 4))
+
+	held, err = q.Held()
+	require.NoError(t, err)
+	assert.Len(t, held, 1)
+
+	approved, err = q.Approved(usdt, 4, gethcommon.HexToHash("0x04"))
+	require.NoError(t, err)
+	assert.False(t, approved)
+}
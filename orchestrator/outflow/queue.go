@@ -0,0 +1,192 @@
+package outflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNotHeld is returned when approving a batch that is not held.
+var ErrNotHeld = errors.New("batch is not held")
+
+const (
+	heldDir     = "held"
+	approvedDir = "approved"
+)
+
+// HeldBatch is a batch left unsigned because it goes over the outflow limits of its token.
+type HeldBatch struct {
+	TokenContract string          `json:"token_contract"`
+	BatchNonce    uint64          `json:"batch_nonce"`
+	Digest        gethcommon.Hash `json:"digest"`
+
+	Amount   decimal.Decimal  `json:"amount"`
+	USDValue *decimal.Decimal `json:"usd_value,omitempty"`
+	Reason   string           `json:"reason"`
+	HeldAt   time.Time        `json:"held_at"`
+}
+
+// Approval lets the orchestrator sign a held batch despite the outflow limits. It only applies to the
+// checkpoint digest the batch was held with.
+type Approval struct {
+	TokenContract string          `json:"token_contract"`
+	BatchNonce    uint64          `json:"batch_nonce"`
+	Digest        gethcommon.Hash `json:"digest"`
+	ApprovedAt    time.Time       `json:"approved_at"`
+}
+
+// Queue keeps the held batches and their approvals as files, so that an operator can approve a batch from the
+// CLI while the orchestrator runs.
+type Queue struct {
+	dir string
+}
+
+// NewQueue opens (or creates) the queue located in dir.
+func NewQueue(dir string) (*Queue, error) {
+	for _, sub := range []string{heldDir, approvedDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, errors.Wrapf(err, "failed to create outflow queue dir %s", dir)
+		}
+	}
+
+	return &Queue{dir: dir}, nil
+}
+
+// Hold adds the batch to the held batches. Holding it again keeps the time it was first held at.
+func (q *Queue) Hold(b HeldBatch) error {
+	var held HeldBatch
+	switch err := readJSON(q.path(heldDir, b.TokenContract, b.BatchNonce), &held); {
+	case err == nil && held.Digest == b.Digest:
+		b.HeldAt = held.HeldAt
+	case err == nil, os.IsNotExist(errors.Cause(err)):
+	default:
+		return err
+	}
+
+	if b.HeldAt.IsZero() {
+		b.HeldAt = time.Now().UTC()
+	}
+
+	return writeJSON(q.path(heldDir, b.TokenContract, b.BatchNonce), b)
+}
+
+// Held returns the held batches, by token and nonce.
+func (q *Queue) Held() ([]HeldBatch, error) {
+	files, err := filepath.Glob(filepath.Join(q.dir, heldDir, "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list held batches")
+	}
+
+	batches := make([]HeldBatch, 0, len(files))
+	for _, file := range files {
+		var b HeldBatch
+		if err := readJSON(file, &b); err != nil {
+			return nil, err
+		}
+
+		batches = append(batches, b)
+	}
+
+	sort.Slice(batches, func(i, j int) bool {
+		if batches[i].TokenContract != batches[j].TokenContract {
+			return batches[i].TokenContract < batches[j].TokenContract
+		}
+
+		return batches[i].BatchNonce < batches[j].BatchNonce
+	})
+
+	return batches, nil
+}
+
+// Approve approves the held batch with the given token and nonce.
+func (q *Queue) Approve(tokenContract string, nonce uint64) (*HeldBatch, error) {
+	var held HeldBatch
+	if err := readJSON(q.path(heldDir, tokenContract, nonce), &held); err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, errors.Wrapf(ErrNotHeld, "token %s batch nonce %d", tokenContract, nonce)
+		}
+
+		return nil, err
+	}
+
+	approval := Approval{
+		TokenContract: held.TokenContract,
+		BatchNonce:    held.BatchNonce,
+		Digest:        held.Digest,
+		ApprovedAt:    time.Now().UTC(),
+	}
+
+	if err := writeJSON(q.path(approvedDir, tokenContract, nonce), approval); err != nil {
+		return nil, err
+	}
+
+	return &held, nil
+}
+
+// Approved reports whether the batch was approved with this checkpoint digest.
+func (q *Queue) Approved(tokenContract string, nonce uint64, digest gethcommon.Hash) (bool, error) {
+	var approval Approval
+	switch err := readJSON(q.path(approvedDir, tokenContract, nonce), &approval); {
+	case err == nil:
+		return approval.Digest == digest, nil
+	case os.IsNotExist(errors.Cause(err)):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Release removes a signed batch from the queue.
+func (q *Queue) Release(tokenContract string, nonce uint64) error {
+	for _, sub := range []string{heldDir, approvedDir} {
+		if err := os.Remove(q.path(sub, tokenContract, nonce)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to release batch from outflow queue")
+		}
+	}
+
+	return nil
+}
+
+func (q *Queue) path(sub, tokenContract string, nonce uint64) string {
+	return filepath.Join(q.dir, sub, fmt.Sprintf("%s-%020d.json", strings.ToLower(tokenContract), nonce))
+}
+
+func readJSON(file string, v interface{}) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Wrapf(err, "failed to decode %s", file)
+	}
+
+	return nil
+}
+
+// writeJSON replaces the file atomically, so that the CLI and the orchestrator never read a partial file
+func writeJSON(file string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s", file)
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write %s", file)
+	}
+
+	if err := os.Rename(tmp, file); err != nil {
+		return errors.Wrapf(err, "failed to write %s", file)
+	}
+
+	return nil
+}
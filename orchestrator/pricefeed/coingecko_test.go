@@ -4,7 +4,7 @@ import (
 	"math/big"
 	"testing"
 
-	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	sdkmath "cosmossdk.io/math"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,15 +18,15 @@ func TestFeeThresholdTwoDecimals(t *testing.T) {
 
 	minFeeInUSD := float64(23.5) // 23.5 USD to submit batch tx
 	minInj := minFeeInUSD / currentTokenPrice
-	var DecimalReduction = cosmtypes.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+	var DecimalReduction = sdkmath.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
 
 	// FeeAccumulated is greater than ExpectedFee
-	totalFeeInINJ := cosmtypes.NewInt(int64(minInj) + 1).Mul(DecimalReduction)
+	totalFeeInINJ := sdkmath.NewInt(int64(minInj) + 1).Mul(DecimalReduction)
 	isFeeLimitExceeded := coingeckoFeed.CheckFeeThreshold(injTokenContract, totalFeeInINJ, minFeeInUSD)
 	assert.True(t, isFeeLimitExceeded, "FeeAccumulated is less than ExpectedFee")
 
 	// FeeAccumulated is less than ExpectedFee
-	totalFeeInINJ = cosmtypes.NewInt(int64(minInj) - 1).Mul(DecimalReduction)
+	totalFeeInINJ = sdkmath.NewInt(int64(minInj) - 1).Mul(DecimalReduction)
 	isFeeLimitExceeded = coingeckoFeed.CheckFeeThreshold(injTokenContract, totalFeeInINJ, minFeeInUSD)
 	assert.False(t, isFeeLimitExceeded, "FeeAccumulated is greater than ExpectedFee")
 }
@@ -39,15 +39,15 @@ func TestFeeThresholdNineDecimals(t *testing.T) {
 
 	minFeeInUSD := float64(23.5) // 23.5 USD to submit batch tx
 	minShib := minFeeInUSD / currentTokenPrice
-	var DecimalReduction = cosmtypes.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+	var DecimalReduction = sdkmath.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
 
 	// FeeAccumulated is greater than ExpectedFee
-	totalFeeInSHIB := cosmtypes.NewInt(int64(minShib) + 1).Mul(DecimalReduction)
+	totalFeeInSHIB := sdkmath.NewInt(int64(minShib) + 1).Mul(DecimalReduction)
 	isFeeLimitExceeded := coingeckoFeed.CheckFeeThreshold(shibTokenContract, totalFeeInSHIB, minFeeInUSD)
 	assert.True(t, isFeeLimitExceeded, "FeeAccumulated is less than ExpectedFee")
 
 	// FeeAccumulated is less than ExpectedFee
-	totalFeeInSHIB = cosmtypes.NewInt(int64(minShib) - 1).Mul(DecimalReduction)
+	totalFeeInSHIB = sdkmath.NewInt(int64(minShib) - 1).Mul(DecimalReduction)
 	isFeeLimitExceeded = coingeckoFeed.CheckFeeThreshold(shibTokenContract, totalFeeInSHIB, minFeeInUSD)
 	assert.False(t, isFeeLimitExceeded, "FeeAccumulated is greater than ExpectedFee")
 }
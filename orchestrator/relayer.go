@@ -18,8 +18,7 @@ import (
 )
 
 const (
-	defaultRelayerLoopDur    = 5 * time.Minute
-	findValsetBlocksToSearch = 2000
+	defaultRelayerLoopDur = 5 * time.Minute
 )
 
 func (s *Orchestrator) runRelayer(ctx context.Context) error {
@@ -184,6 +183,16 @@ func (l *relayer) shouldRelayValset(ctx context.Context, vs *peggytypes.Valset)
 	return true
 }
 
+// batchCandidate is a confirmed batch along with the confirmations to submit it with
+type batchCandidate struct {
+	batch         *peggytypes.OutgoingTxBatch
+	confirmations []*peggytypes.MsgConfirmBatch
+}
+
+// relayTokenBatch relays one confirmed batch per loop. The last confirmed batch of each token is a candidate, the
+// last of all goes first. A candidate that is not due yet or not profitable is skipped for the next one: batches of
+// other tokens do not depend on it, and earlier batches of its token are not candidates since relaying them would
+// not make the skipped one any cheaper.
 func (l *relayer) relayTokenBatch(ctx context.Context, latestEthValset *peggytypes.Valset) error {
 	metrics.ReportFuncCall(l.svcTags)
 	doneFn := metrics.ReportFuncTiming(l.svcTags)
@@ -200,11 +209,16 @@ func (l *relayer) relayTokenBatch(ctx context.Context, latestEthValset *peggytyp
 	}
 
 	var (
-		oldestConfirmedBatch *peggytypes.OutgoingTxBatch
-		confirmations        []*peggytypes.MsgConfirmBatch
+		candidates []batchCandidate
+		hasBatch   = make(map[string]bool)
 	)
 
-	for _, batch := range batches {
+	for i := len(batches) - 1; i >= 0; i-- {
+		batch := batches[i]
+		if hasBatch[batch.TokenContract] {
+			continue
+		}
+
 		if batch.BatchTimeout <= latestEthHeight.Number.Uint64() {
 			l.Log().WithFields(log.Fields{"batch_nonce": batch.BatchNonce, "batch_timeout_height": batch.BatchTimeout, "latest_eth_height": latestEthHeight.Number.Uint64()}).Debugln("skipping timed out batch")
 			continue
@@ -219,29 +233,46 @@ func (l *relayer) relayTokenBatch(ctx context.Context, latestEthValset *peggytyp
 			continue
 		}
 
-		oldestConfirmedBatch = batch
-		confirmations = sigs
+		hasBatch[batch.TokenContract] = true
+		candidates = append(candidates, batchCandidate{batch: batch, confirmations: sigs})
 	}
 
-	if oldestConfirmedBatch == nil {
+	if len(candidates) == 0 {
 		l.Log().Infoln("no token batch to relay")
 		return nil
 	}
 
-	if !l.shouldRelayBatch(ctx, oldestConfirmedBatch) {
-		return nil
-	}
+	for _, c := range candidates {
+		if !l.shouldRelayBatch(ctx, c.batch) {
+			continue
+		}
+
+		if l.cfg.RelayBatchMargin > 0 {
+			ok, reason := l.isBatchProfitable(ctx, latestEthValset, c.batch, c.confirmations)
+			logger := l.Log().WithFields(log.Fields{"token_contract": c.batch.TokenContract, "batch_nonce": c.batch.BatchNonce, "reason": reason})
+
+			if !ok {
+				logger.Infoln("skipping unprofitable batch")
+				l.reportUnprofitableBatch(gethcommon.HexToAddress(c.batch.TokenContract))
+				continue
+			}
+
+			logger.Debugln("batch is profitable to relay")
+		}
+
+		txHash, err := l.ethereum.SendTransactionBatch(ctx, latestEthValset, c.batch, c.confirmations)
+		if err != nil {
+			// Returning an error here triggers retries which don't help much except risk a binary crash
+			// Better to warn the user and try again in the next loop interval
+			log.WithError(err).Warningln("failed to send outgoing tx batch to Ethereum")
+			return nil
+		}
+
+		l.Log().WithField("tx_hash", txHash.Hex()).Infoln("sent outgoing tx batch to Ethereum")
 
-	txHash, err := l.ethereum.SendTransactionBatch(ctx, latestEthValset, oldestConfirmedBatch, confirmations)
-	if err != nil {
-		// Returning an error here triggers retries which don't help much except risk a binary crash
-		// Better to warn the user and try again in the next loop interval
-		log.WithError(err).Warningln("failed to send outgoing tx batch to Ethereum")
 		return nil
 	}
 
-	l.Log().WithField("tx_hash", txHash.Hex()).Infoln("sent outgoing tx batch to Ethereum")
-
 	return nil
 }
 
@@ -297,14 +328,17 @@ func (l *relayer) findLatestValsetOnEth(ctx context.Context) (*peggytypes.Valset
 		return nil, errors.Wrap(err, "failed to get Injective valset")
 	}
 
-	currentBlock := latestHeader.Number.Uint64()
+	var (
+		currentBlock   = latestHeader.Number.Uint64()
+		blocksToSearch = l.ethereum.LogsWindow()
+	)
 
 	for currentBlock > 0 {
 		var startSearchBlock uint64
-		if currentBlock <= findValsetBlocksToSearch {
+		if currentBlock <= blocksToSearch {
 			startSearchBlock = 0
 		} else {
-			startSearchBlock = currentBlock - findValsetBlocksToSearch
+			startSearchBlock = currentBlock - blocksToSearch
 		}
 
 		valsetUpdatedEvents, err := l.ethereum.GetValsetUpdatedEvents(startSearchBlock, currentBlock)
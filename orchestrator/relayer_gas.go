@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/metrics"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// isBatchProfitable reports whether the fees of the batch exceed the gas cost of relaying it times RelayBatchMargin,
+// and why. The gas is simulated against the Peggy contract, so a batch that would revert is not profitable either.
+func (l *relayer) isBatchProfitable(
+	ctx context.Context,
+	valset *peggytypes.Valset,
+	batch *peggytypes.OutgoingTxBatch,
+	confirms []*peggytypes.MsgConfirmBatch,
+) (bool, string) {
+	feeUSD, err := l.getBatchFeeUSD(ctx, batch)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	costUSD, err := l.getBatchRelayCostUSD(ctx, valset, batch, confirms)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	margin := decimal.NewFromFloat(l.cfg.RelayBatchMargin)
+
+	if !feeUSD.GreaterThan(costUSD.Mul(margin)) {
+		return false, fmt.Sprintf("batch fee %s USD does not exceed the gas cost of %s USD times %s", feeUSD.StringFixed(2), costUSD.StringFixed(2), margin)
+	}
+
+	return true, fmt.Sprintf("batch fee %s USD exceeds the gas cost of %s USD times %s", feeUSD.StringFixed(2), costUSD.StringFixed(2), margin)
+}
+
+// getBatchFeeUSD values the fees collected by relaying the batch in USD
+func (l *relayer) getBatchFeeUSD(ctx context.Context, batch *peggytypes.OutgoingTxBatch) (decimal.Decimal, error) {
+	tokenAddress := gethcommon.HexToAddress(batch.TokenContract)
+
+	tokenDecimals, err := l.ethereum.TokenDecimals(ctx, tokenAddress)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to get token decimals")
+	}
+
+	totalFee := new(big.Int)
+	for _, tx := range batch.Transactions {
+		totalFee.Add(totalFee, tx.Erc20Fee.Amount.BigInt())
+	}
+
+	tokenPriceUSD, err := l.priceFeed.QueryUSDPrice(tokenAddress)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to query token price")
+	}
+
+	return decimal.NewFromBigInt(totalFee, -1*int32(tokenDecimals)).Mul(decimal.NewFromFloat(tokenPriceUSD)), nil
+}
+
+// getBatchRelayCostUSD values the gas of submitting the batch in USD, using the current Ethereum gas price times
+// GasPriceAdjustment, as the batch is sent with, and the ETH price of the price feed
+func (l *relayer) getBatchRelayCostUSD(
+	ctx context.Context,
+	valset *peggytypes.Valset,
+	batch *peggytypes.OutgoingTxBatch,
+	confirms []*peggytypes.MsgConfirmBatch,
+) (decimal.Decimal, error) {
+	gas, err := l.ethereum.EstimateTransactionBatchGas(ctx, valset, batch, confirms)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to simulate batch submission")
+	}
+
+	gasPrice, err := l.ethereum.SuggestGasPrice(ctx)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to get Ethereum gas price")
+	}
+
+	ethPriceUSD, err := l.priceFeed.QueryUSDPrice(wethAddress)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to query ETH price")
+	}
+
+	adjustment := decimal.NewFromInt(1)
+	if l.cfg.GasPriceAdjustment > 0 {
+		adjustment = decimal.NewFromFloat(l.cfg.GasPriceAdjustment)
+	}
+
+	gasCost := new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+
+	return decimal.NewFromBigInt(gasCost, -18).Mul(adjustment).Mul(decimal.NewFromFloat(ethPriceUSD)), nil
+}
+
+func (l *relayer) reportUnprofitableBatch(token gethcommon.Address) {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("relayer.batch_unprofitable", tagSpec, 1)
+	}, metrics.Tags{"svc": l.svcTags["svc"], "token_contract": token.Hex()})
+}
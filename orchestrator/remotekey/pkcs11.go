@@ -0,0 +1,298 @@
+package remotekey
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+// pkcs11Key signs with a private key stored on a PKCS#11 token (HSM, SoftHSM, ...). The token PIN is read from
+// the pin-source file or PEGGO_PKCS11_PIN, it is never part of the URI.
+type pkcs11Key struct {
+	mux sync.Mutex
+
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	priv    pkcs11.ObjectHandle
+	pub     *ecdsa.PublicKey
+}
+
+// pkcs11URI is the subset of RFC 7512 PKCS#11 URIs needed to find a key
+type pkcs11URI struct {
+	token      string
+	object     string
+	id         []byte
+	modulePath string
+	pinSource  string
+}
+
+func parsePKCS11URI(uri string) (pkcs11URI, error) {
+	var u pkcs11URI
+
+	path, query, _ := strings.Cut(strings.TrimPrefix(uri, "pkcs11:"), "?")
+
+	err := parsePKCS11Attrs(path, ";", func(k, v string) error {
+		switch k {
+		case "token":
+			u.token = v
+		case "object":
+			u.object = v
+		case "id":
+			u.id = []byte(v)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return pkcs11URI{}, err
+	}
+
+	err = parsePKCS11Attrs(query, "&", func(k, v string) error {
+		switch k {
+		case "module-path":
+			u.modulePath = v
+		case "pin-source":
+			u.pinSource = strings.TrimPrefix(v, "file:")
+		case "pin-value":
+			return errors.New("pin-value is not accepted in PKCS#11 URIs, use pin-source or PEGGO_PKCS11_PIN")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return pkcs11URI{}, err
+	}
+
+	switch {
+	case u.modulePath == "":
+		return pkcs11URI{}, errors.New("PKCS#11 URI must set module-path")
+	case u.token == "":
+		return pkcs11URI{}, errors.New("PKCS#11 URI must set the token label")
+	case u.object == "" && len(u.id) == 0:
+		return pkcs11URI{}, errors.New("PKCS#11 URI must set the key object label or id")
+	}
+
+	return u, nil
+}
+
+// parsePKCS11Attrs calls fn with every percent-decoded attribute of the URI component
+func parsePKCS11Attrs(attrs, sep string, fn func(k, v string) error) error {
+	for _, attr := range strings.Split(attrs, sep) {
+		if attr == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(attr, "=")
+		if !ok {
+			return errors.Errorf("invalid PKCS#11 URI attribute %q", attr)
+		}
+
+		value, err := url.PathUnescape(v)
+		if err != nil {
+			return errors.Wrapf(err, "invalid PKCS#11 URI attribute %q", k)
+		}
+
+		if err := fn(k, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u pkcs11URI) pin() (string, error) {
+	if u.pinSource == "" {
+		pin := os.Getenv("PEGGO_PKCS11_PIN")
+		if pin == "" {
+			return "", errors.New("PKCS#11 PIN must be provided with pin-source or PEGGO_PKCS11_PIN")
+		}
+
+		return pin, nil
+	}
+
+	pin, err := os.ReadFile(u.pinSource)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read PKCS#11 pin-source")
+	}
+
+	return strings.TrimSpace(string(pin)), nil
+}
+
+// keyTemplate returns the attributes matching the key objects of the given class
+func (u pkcs11URI) keyTemplate(class uint) []*pkcs11.Attribute {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+	}
+
+	if u.object != "" {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_LABEL, u.object))
+	}
+
+	if len(u.id) > 0 {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_ID, u.id))
+	}
+
+	return template
+}
+
+// OpenPKCS11Key opens the secp256k1 key pair identified by the PKCS#11 URI and logs into its token.
+func OpenPKCS11Key(uri string) (Key, error) {
+	u, err := parsePKCS11URI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	pin, err := u.pin()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := pkcs11.New(u.modulePath)
+	if ctx == nil {
+		return nil, errors.Errorf("failed to load PKCS#11 module %s", u.modulePath)
+	}
+
+	if err := ctx.Initialize(); err != nil && !isPKCS11Error(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		return nil, errors.Wrap(err, "failed to initialize PKCS#11 module")
+	}
+
+	slot, err := findTokenSlot(ctx, u.token)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open PKCS#11 session")
+	}
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil && !isPKCS11Error(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		_ = ctx.CloseSession(session)
+		return nil, errors.Wrapf(err, "failed to log into PKCS#11 token %s", u.token)
+	}
+
+	k := &pkcs11Key{ctx: ctx, session: session}
+
+	if k.priv, err = k.findObject(u.keyTemplate(pkcs11.CKO_PRIVATE_KEY)); err != nil {
+		return nil, errors.Wrap(err, "failed to find PKCS#11 private key")
+	}
+
+	pubHandle, err := k.findObject(u.keyTemplate(pkcs11.CKO_PUBLIC_KEY))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find PKCS#11 public key")
+	}
+
+	if k.pub, err = k.publicKey(pubHandle); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (k *pkcs11Key) PublicKey() *ecdsa.PublicKey {
+	return k.pub
+}
+
+func (k *pkcs11Key) SignDigest(digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, errors.Errorf("invalid digest length %d", len(digest))
+	}
+
+	// PKCS#11 sessions must not be used concurrently
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	if err := k.ctx.SignInit(k.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, k.priv); err != nil {
+		return nil, errors.Wrap(err, "failed to init PKCS#11 signature")
+	}
+
+	sig, err := k.ctx.Sign(k.session, digest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign with PKCS#11 key")
+	}
+
+	// CKM_ECDSA signatures are R || S
+	if len(sig) != 64 {
+		return nil, errors.Errorf("unexpected PKCS#11 signature length %d", len(sig))
+	}
+
+	return recoverableSignature(k.pub, digest, new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]))
+}
+
+func (k *pkcs11Key) findObject(template []*pkcs11.Attribute) (pkcs11.ObjectHandle, error) {
+	if err := k.ctx.FindObjectsInit(k.session, template); err != nil {
+		return 0, err
+	}
+
+	objects, _, err := k.ctx.FindObjects(k.session, 2)
+	_ = k.ctx.FindObjectsFinal(k.session)
+
+	switch {
+	case err != nil:
+		return 0, err
+	case len(objects) == 0:
+		return 0, errors.New("no matching object")
+	case len(objects) > 1:
+		return 0, errors.New("more than one matching object")
+	}
+
+	return objects[0], nil
+}
+
+func (k *pkcs11Key) publicKey(handle pkcs11.ObjectHandle) (*ecdsa.PublicKey, error) {
+	attrs, err := k.ctx.GetAttributeValue(k.session, handle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read PKCS#11 public key")
+	}
+
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(attrs[0].Value, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return nil, errors.New("PKCS#11 key is not a secp256k1 key")
+	}
+
+	// CKA_EC_POINT is a DER octet string, although some modules return the raw point
+	point := attrs[1].Value
+	var wrapped []byte
+	if rest, err := asn1.Unmarshal(point, &wrapped); err == nil && len(rest) == 0 {
+		point = wrapped
+	}
+
+	return ecPublicKey(point)
+}
+
+func findTokenSlot(ctx *pkcs11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list PKCS#11 slots")
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+
+		// token labels are padded with spaces
+		if strings.TrimRight(info.Label, " ") == label {
+			return slot, nil
+		}
+	}
+
+	return 0, errors.Errorf("PKCS#11 token %s not found", label)
+}
+
+func isPKCS11Error(err error, code uint) bool {
+	var e pkcs11.Error
+	return errors.As(err, &e) && uint(e) == code
+}
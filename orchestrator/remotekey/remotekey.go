@@ -0,0 +1,97 @@
+// Package remotekey implements secp256k1 keys held by an external signing backend (HashiCorp Vault Transit or
+// a PKCS#11 token), so that orchestrator keys never exist in flags or on-disk keystores.
+package remotekey
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// Key is a secp256k1 key whose private part never leaves the backend holding it.
+type Key interface {
+	PublicKey() *ecdsa.PublicKey
+
+	// SignDigest returns the [R || S || V] signature of the 32 bytes digest, with a low S and V in {0, 1}.
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// Open opens the key identified by the URI:
+//
+//	vault://host:8200/<transit mount>/<key name>      (vault+http:// for a Vault without TLS)
+//	pkcs11:token=<label>;object=<label>?module-path=/path/to/module.so&pin-source=/path/to/pin
+func Open(uri string) (Key, error) {
+	switch {
+	case strings.HasPrefix(uri, "vault://"), strings.HasPrefix(uri, "vault+http://"):
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse Vault key URI")
+		}
+
+		return OpenVaultKey(u)
+	case strings.HasPrefix(uri, "pkcs11:"):
+		return OpenPKCS11Key(uri)
+	default:
+		return nil, errors.Errorf("unsupported signer URI %q, expected vault:// or pkcs11:", redactURI(uri))
+	}
+}
+
+// secp256k1 curve OID, the only curve Ethereum and Injective accept
+var oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// recoverableSignature turns an ECDSA (r, s) signature into the [R || S || V] format, normalizing S to the lower
+// half of the curve order and finding the recovery id matching the public key.
+func recoverableSignature(pub *ecdsa.PublicKey, digest []byte, r, s *big.Int) ([]byte, error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("invalid signature values")
+	}
+
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+
+	expected := crypto.FromECDSAPub(pub)
+	for v := byte(0); v < 2; v++ {
+		sig[crypto.RecoveryIDOffset] = v
+
+		recovered, err := crypto.Ecrecover(digest, sig)
+		if err == nil && string(recovered) == string(expected) {
+			return sig, nil
+		}
+	}
+
+	return nil, errors.New("signature does not match the public key")
+}
+
+// ecPublicKey parses an uncompressed secp256k1 point
+func ecPublicKey(point []byte) (*ecdsa.PublicKey, error) {
+	pub, err := crypto.UnmarshalPubkey(point)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid secp256k1 public key")
+	}
+
+	return pub, nil
+}
+
+// redactURI drops the query of the URI, which may reference secrets
+func redactURI(uri string) string {
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		return uri[:i]
+	}
+
+	return uri
+}
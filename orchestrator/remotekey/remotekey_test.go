@@ -0,0 +1,264 @@
+package remotekey
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oid of EC public keys in SubjectPublicKeyInfo
+var oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+// vaultStub serves the Transit endpoints of a single ecdsa-p256k1 key
+func vaultStub(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	spki, err := asn1.Marshal(struct {
+		Algorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}{
+		Algorithm: struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}{oidECPublicKey, oidSecp256k1},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+	})
+	require.NoError(t, err)
+
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+
+	reply := func(w http.ResponseWriter, data interface{}) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/transit/keys/peggo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		reply(w, map[string]interface{}{
+			"type":           "ecdsa-p256k1",
+			"latest_version": 2,
+			"keys": map[string]interface{}{
+				"2": map[string]string{"public_key": publicKey},
+			},
+		})
+	})
+
+	mux.HandleFunc("/v1/transit/sign/peggo", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input      string `json:"input"`
+			Prehashed  bool   `json:"prehashed"`
+			KeyVersion int    `json:"key_version"`
+		}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.True(t, req.Prehashed)
+		require.Equal(t, 2, req.KeyVersion)
+
+		digest, err := base64.StdEncoding.DecodeString(req.Input)
+		require.NoError(t, err)
+
+		rr, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		require.NoError(t, err)
+
+		// Vault does not normalize S, make sure the high S case is covered
+		if s.Cmp(secp256k1HalfN) <= 0 {
+			s = new(big.Int).Sub(secp256k1N, s)
+		}
+
+		der, err := asn1.Marshal(struct{ R, S *big.Int }{rr, s})
+		require.NoError(t, err)
+
+		reply(w, map[string]string{"signature": "vault:v2:" + base64.StdEncoding.EncodeToString(der)})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestVaultKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	srv := vaultStub(t, key)
+	defer srv.Close()
+
+	uri := "vault+http://" + strings.TrimPrefix(srv.URL, "http://") + "/transit/peggo"
+
+	t.Run("missing token", func(t *testing.T) {
+		t.Setenv("VAULT_TOKEN", "")
+
+		_, err := Open(uri)
+		assert.ErrorContains(t, err, "VAULT_TOKEN")
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		t.Setenv("VAULT_TOKEN", "other-token")
+
+		_, err := Open(uri)
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("signs with the key", func(t *testing.T) {
+		t.Setenv("VAULT_TOKEN", "test-token")
+
+		k, err := Open(uri)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*k.PublicKey()))
+
+		digest := crypto.Keccak256([]byte("checkpoint"))
+		sig, err := k.SignDigest(digest)
+		require.NoError(t, err)
+
+		pub, err := crypto.SigToPub(digest, sig)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pub))
+		assert.True(t, crypto.ValidateSignatureValues(sig[64], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), true))
+	})
+}
+
+func TestParsePKCS11URI(t *testing.T) {
+	testCases := []struct {
+		uri      string
+		expected pkcs11URI
+		err      string
+	}{
+		{
+			uri: "pkcs11:token=peggo;object=eth%20key?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=file:/run/pin",
+			expected: pkcs11URI{
+				token:      "peggo",
+				object:     "eth key",
+				modulePath: "/usr/lib/softhsm/libsofthsm2.so",
+				pinSource:  "/run/pin",
+			},
+		},
+		{
+			uri: "pkcs11:token=peggo;id=%01%02?module-path=/lib/hsm.so",
+			expected: pkcs11URI{
+				token:      "peggo",
+				id:         []byte{1, 2},
+				modulePath: "/lib/hsm.so",
+			},
+		},
+		{
+			uri: "pkcs11:token=peggo;object=eth?module-path=/lib/hsm.so&pin-value=1234",
+			err: "pin-value is not accepted",
+		},
+		{
+			uri: "pkcs11:token=peggo;object=eth",
+			err: "must set module-path",
+		},
+		{
+			uri: "pkcs11:token=peggo?module-path=/lib/hsm.so",
+			err: "must set the key object label or id",
+		},
+	}
+
+	for _, tc := range testCases {
+		u, err := parsePKCS11URI(tc.uri)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.uri)
+			continue
+		}
+
+		require.NoError(t, err, tc.uri)
+		assert.Equal(t, tc.expected, u, tc.uri)
+	}
+}
+
+// TestPKCS11Key runs against SoftHSM (or any PKCS#11 module supporting secp256k1) with a token labeled "peggo"
+// and user PIN 1234, e.g. softhsm2-util --init-token --free --label peggo --pin 1234 --so-pin 1234
+func TestPKCS11Key(t *testing.T) {
+	module := os.Getenv("PEGGO_TEST_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("PEGGO_TEST_PKCS11_MODULE is not set")
+	}
+
+	t.Setenv("PEGGO_PKCS11_PIN", "1234")
+
+	label := generatePKCS11Key(t, module, "peggo", "1234")
+
+	k, err := Open("pkcs11:token=peggo;object=" + url.PathEscape(label) + "?module-path=" + module)
+	require.NoError(t, err)
+
+	digest := crypto.Keccak256([]byte("checkpoint"))
+	sig, err := k.SignDigest(digest)
+	require.NoError(t, err)
+
+	pub, err := crypto.SigToPub(digest, sig)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(*k.PublicKey()), crypto.PubkeyToAddress(*pub))
+
+	pinFile := filepath.Join(t.TempDir(), "pin")
+	require.NoError(t, os.WriteFile(pinFile, []byte("4321\n"), 0o600))
+
+	_, err = Open("pkcs11:token=peggo;object=" + url.PathEscape(label) + "?module-path=" + module + "&pin-source=" + pinFile)
+	assert.ErrorContains(t, err, "failed to log into PKCS#11 token")
+}
+
+func generatePKCS11Key(t *testing.T, module, token, pin string) string {
+	ctx := pkcs11.New(module)
+	require.NotNil(t, ctx)
+
+	if err := ctx.Initialize(); err != nil {
+		require.True(t, isPKCS11Error(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED), err)
+	}
+
+	slot, err := findTokenSlot(ctx, token)
+	require.NoError(t, err)
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.NoError(t, err)
+
+	defer ctx.CloseSession(session)
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		require.True(t, isPKCS11Error(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN), err)
+	}
+
+	params, err := asn1.Marshal(oidSecp256k1)
+	require.NoError(t, err)
+
+	// keys are created on the token, a unique label keeps the test repeatable
+	label := fmt.Sprintf("peggo-test-%d", time.Now().UnixNano())
+
+	_, _, err = ctx.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		},
+	)
+	require.NoError(t, err)
+
+	return label
+}
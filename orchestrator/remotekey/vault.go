@@ -0,0 +1,260 @@
+package remotekey
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	vaultKeyType = "ecdsa-p256k1"
+	vaultTimeout = 10 * time.Second
+)
+
+// vaultKey signs with a key of the Vault Transit secrets engine. The Vault token is read from VAULT_TOKEN,
+// the namespace from the namespace query parameter or VAULT_NAMESPACE and the CA certificate from VAULT_CACERT.
+type vaultKey struct {
+	addr      *url.URL
+	mount     string
+	name      string
+	token     string
+	namespace string
+	client    *http.Client
+
+	version int
+	pub     *ecdsa.PublicKey
+}
+
+// OpenVaultKey opens the Transit key at vault://host:port/<mount>/<name>. The latest version of the key is used.
+func OpenVaultKey(u *url.URL) (Key, error) {
+	path := strings.Trim(u.Path, "/")
+
+	i := strings.LastIndexByte(path, '/')
+	if i <= 0 || i == len(path)-1 {
+		return nil, errors.Errorf("Vault key URI must be vault://host:port/<transit mount>/<key name>, got %s", redactURI(u.String()))
+	}
+
+	scheme := "https"
+	if u.Scheme == "vault+http" {
+		scheme = "http"
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, errors.New("VAULT_TOKEN must be set to use a Vault key")
+	}
+
+	namespace := u.Query().Get("namespace")
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	client, err := vaultHTTPClient(os.Getenv("VAULT_CACERT"))
+	if err != nil {
+		return nil, err
+	}
+
+	k := &vaultKey{
+		addr:      &url.URL{Scheme: scheme, Host: u.Host},
+		mount:     path[:i],
+		name:      path[i+1:],
+		token:     token,
+		namespace: namespace,
+		client:    client,
+	}
+
+	if err := k.loadPublicKey(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func vaultHTTPClient(caCertFile string) (*http.Client, error) {
+	client := &http.Client{Timeout: vaultTimeout}
+	if caCertFile == "" {
+		return client, nil
+	}
+
+	caCert, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read VAULT_CACERT")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("no certificate found in VAULT_CACERT")
+	}
+
+	client.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+	}
+
+	return client, nil
+}
+
+func (k *vaultKey) PublicKey() *ecdsa.PublicKey {
+	return k.pub
+}
+
+func (k *vaultKey) SignDigest(digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, errors.Errorf("invalid digest length %d", len(digest))
+	}
+
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+
+	// the digest is signed as is, Vault only checks its length against the hash algorithm
+	req := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"hash_algorithm":       "sha2-256",
+		"marshaling_algorithm": "asn1",
+		"key_version":          k.version,
+	}
+
+	if err := k.do(http.MethodPost, "sign/"+k.name, req, &resp); err != nil {
+		return nil, errors.Wrapf(err, "failed to sign with Vault key %s", k.name)
+	}
+
+	// vault:v<version>:<base64 signature>
+	parts := strings.SplitN(resp.Signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, errors.Errorf("unexpected Vault signature format %q", resp.Signature)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode Vault signature")
+	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) > 0 {
+		return nil, errors.New("failed to decode Vault ASN.1 signature")
+	}
+
+	return recoverableSignature(k.pub, digest, sig.R, sig.S)
+}
+
+func (k *vaultKey) loadPublicKey() error {
+	var resp struct {
+		Type          string `json:"type"`
+		LatestVersion int    `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	}
+
+	if err := k.do(http.MethodGet, "keys/"+k.name, nil, &resp); err != nil {
+		return errors.Wrapf(err, "failed to read Vault key %s", k.name)
+	}
+
+	if resp.Type != vaultKeyType {
+		return errors.Errorf("Vault key %s is of type %s, expected %s", k.name, resp.Type, vaultKeyType)
+	}
+
+	version, ok := resp.Keys[strconv.Itoa(resp.LatestVersion)]
+	if !ok {
+		return errors.Errorf("Vault key %s has no version %d", k.name, resp.LatestVersion)
+	}
+
+	block, _ := pem.Decode([]byte(version.PublicKey))
+	if block == nil {
+		return errors.Errorf("failed to decode public key of Vault key %s", k.name)
+	}
+
+	// x509 does not support secp256k1, so the SubjectPublicKeyInfo is decoded by hand
+	var spki struct {
+		Algorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}
+
+	if rest, err := asn1.Unmarshal(block.Bytes, &spki); err != nil || len(rest) > 0 {
+		return errors.Errorf("failed to decode public key of Vault key %s", k.name)
+	}
+
+	if !spki.Algorithm.Parameters.Equal(oidSecp256k1) {
+		return errors.Errorf("Vault key %s is not a secp256k1 key", k.name)
+	}
+
+	pub, err := ecPublicKey(spki.PublicKey.Bytes)
+	if err != nil {
+		return err
+	}
+
+	k.version, k.pub = resp.LatestVersion, pub
+
+	return nil
+}
+
+// do calls the Transit API and decodes the data of the response
+func (k *vaultKey) do(method, path string, reqBody, respData interface{}) error {
+	var body io.Reader
+	if reqBody != nil {
+		data, err := json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, k.addr.JoinPath("v1", k.mount, path).String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Vault-Token", k.token)
+	if k.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", k.namespace)
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	var vaultResp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&vaultResp); err != nil {
+		return errors.Wrapf(err, "failed to decode Vault response (%s)", resp.Status)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s: %s", resp.Status, strings.Join(vaultResp.Errors, "; "))
+	}
+
+	return json.Unmarshal(vaultResp.Data, respData)
+}
@@ -2,18 +2,22 @@ package orchestrator
 
 import (
 	"context"
+	"sort"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 	log "github.com/xlab/suplog"
 
 	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
 	"github.com/InjectiveLabs/peggo/orchestrator/loops"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
 	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
 )
 
-// runSigner simply signs off on any batches or validator sets provided by the validator
-// since these are provided directly by a trusted Injective node they can simply be assumed to be
-// valid and signed off on.
+// runSigner signs off on any batches or validator sets provided by the Injective node. Validator sets are
+// only signed if they match the bridge validator set rebuilt from the Tendermint validator set, so that a
+// compromised or misconfigured node cannot get our key to sign an arbitrary validator set.
 func (s *Orchestrator) runSigner(ctx context.Context, peggyID gethcommon.Hash) error {
 	signer := signer{
 		Orchestrator: s,
@@ -45,7 +49,7 @@ func (l *signer) sign(ctx context.Context) error {
 		return err
 	}
 
-	if err := l.signNewBatch(ctx); err != nil {
+	if err := l.signBatches(ctx); err != nil {
 		return err
 	}
 
@@ -69,6 +73,24 @@ func (l *signer) signValidatorSets(ctx context.Context) error {
 	}
 
 	for _, vs := range valsets {
+		// a valset that cannot be verified, e.g. because its height was pruned, must not stop the other loops
+		verified, err := l.verifyValset(ctx, vs)
+		if err != nil {
+			l.Log().WithError(err).WithFields(log.Fields{"valset_nonce": vs.Nonce, "valset_height": vs.Height}).Errorln("failed to verify valset against the Tendermint validator set, refusing to sign it")
+			l.reportValsetMismatch()
+			continue
+		}
+
+		if !verified {
+			continue
+		}
+
+		if ok, err := l.canSignValset(vs); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
 		if err := l.retry(ctx, func() error {
 			return l.injective.SendValsetConfirm(ctx, l.cfg.EthereumAddr, l.peggyID, vs)
 		}); err != nil {
@@ -81,33 +103,113 @@ func (l *signer) signValidatorSets(ctx context.Context) error {
 	return nil
 }
 
-func (l *signer) signNewBatch(ctx context.Context) error {
-	var oldestUnsignedBatch *peggytypes.OutgoingTxBatch
-	getBatchFn := func() error {
-		oldestUnsignedBatch, _ = l.injective.OldestUnsignedTransactionBatch(ctx, l.cfg.CosmosAddr)
-		return nil
+func (l *signer) signBatches(ctx context.Context) error {
+	var unsignedBatches []*peggytypes.OutgoingTxBatch
+	getBatchesFn := func() (err error) {
+		unsignedBatches, err = l.unsignedBatches(ctx)
+		return
 	}
 
-	if err := l.retry(ctx, getBatchFn); err != nil {
+	if err := l.retry(ctx, getBatchesFn); err != nil {
 		return err
 	}
 
-	if oldestUnsignedBatch == nil {
+	if len(unsignedBatches) == 0 {
 		l.Log().Infoln("no token batch to confirm")
 		return nil
 	}
 
-	if err := l.retry(ctx, func() error {
-		return l.injective.SendBatchConfirm(ctx,
-			l.cfg.EthereumAddr,
-			l.peggyID,
-			oldestUnsignedBatch,
-		)
-	}); err != nil {
+	var (
+		batches  = make([]*peggytypes.OutgoingTxBatch, 0, len(unsignedBatches))
+		outflows []state.OutflowRecord
+	)
+
+	for _, batch := range unsignedBatches {
+		outflow, ok, err := l.withinOutflowLimits(ctx, batch, outflows)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		if ok, err := l.canSignBatch(batch); err != nil {
+			return err
+		} else if ok {
+			batches = append(batches, batch)
+			if outflow != nil {
+				outflows = append(outflows, *outflow)
+			}
+		}
+	}
+
+	if len(batches) == 0 {
+		return nil
+	}
+
+	// a failing batch is left out by SendBatchConfirms and retried in the next loop, the others are still confirmed
+	confirmed, err := l.injective.SendBatchConfirms(ctx, l.cfg.EthereumAddr, l.peggyID, batches)
+	if err != nil {
+		l.Log().WithError(err).Warningf("failed to confirm %d out of %d batches", len(batches)-len(confirmed), len(batches))
+	}
+
+	if err := l.recordOutflows(confirmedOutflows(outflows, confirmed)); err != nil {
 		return err
 	}
 
-	l.Log().WithFields(log.Fields{"token_contract": oldestUnsignedBatch.TokenContract, "batch_nonce": oldestUnsignedBatch.BatchNonce, "txs": len(oldestUnsignedBatch.Transactions)}).Infoln("confirmed batch on Injective")
+	for _, batch := range confirmed {
+		l.Log().WithFields(log.Fields{"token_contract": batch.TokenContract, "batch_nonce": batch.BatchNonce, "txs": len(batch.Transactions)}).Infoln("confirmed batch on Injective")
+	}
 
 	return nil
 }
+
+// unsignedBatches returns all the outgoing batches not confirmed by our orchestrator yet, in nonce order per token.
+// LastPendingBatchRequestByAddr tells in a single query whether any batch awaits our confirmation, the confirmations
+// of every batch are only queried when one does.
+func (l *signer) unsignedBatches(ctx context.Context) ([]*peggytypes.OutgoingTxBatch, error) {
+	pending, err := l.injective.OldestUnsignedTransactionBatch(ctx, l.cfg.CosmosAddr)
+	if err != nil && !errors.Is(err, peggy.ErrNotFound) {
+		return nil, err
+	}
+
+	if pending == nil {
+		return nil, nil
+	}
+
+	batches, err := l.injective.LatestTransactionBatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsigned []*peggytypes.OutgoingTxBatch
+	for _, batch := range batches {
+		confirms, err := l.injective.TransactionBatchSignatures(ctx, batch.BatchNonce, gethcommon.HexToAddress(batch.TokenContract))
+		if err != nil {
+			return nil, err
+		}
+
+		if !l.confirmedBatch(confirms) {
+			unsigned = append(unsigned, batch)
+		}
+	}
+
+	sort.Slice(unsigned, func(i, j int) bool {
+		if unsigned[i].TokenContract != unsigned[j].TokenContract {
+			return unsigned[i].TokenContract < unsigned[j].TokenContract
+		}
+
+		return unsigned[i].BatchNonce < unsigned[j].BatchNonce
+	})
+
+	return unsigned, nil
+}
+
+func (l *signer) confirmedBatch(confirms []*peggytypes.MsgConfirmBatch) bool {
+	for _, confirm := range confirms {
+		if confirm.Orchestrator == l.cfg.CosmosAddr.String() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/outflow"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// withinOutflowLimits reports whether the batch can be signed under the outflow limits of its token, along with its
+// outflow, nil for tokens without limits. The rolling window counts the recorded outflows and the signing ones, of
+// the batches signed in the same loop. Batches over the limits are held unsigned until an operator approves them
+// with `peggo outflow approve`.
+func (l *signer) withinOutflowLimits(
+	ctx context.Context,
+	batch *peggytypes.OutgoingTxBatch,
+	signing []state.OutflowRecord,
+) (*state.OutflowRecord, bool, error) {
+	limit, ok := l.cfg.OutflowLimits.For(gethcommon.HexToAddress(batch.TokenContract))
+	if !ok {
+		return nil, true, nil
+	}
+
+	var (
+		tokenAddr = gethcommon.HexToAddress(batch.TokenContract)
+		digest    = peggy.EncodeTxBatchConfirm(l.peggyID, batch)
+		now       = time.Now().UTC()
+	)
+
+	var decimals uint8
+	if err := l.retry(ctx, func() (err error) {
+		decimals, err = l.ethereum.TokenDecimals(ctx, tokenAddr)
+		return
+	}); err != nil {
+		// without the decimals the amount is unknown, the batch is held like one without a USD price
+		l.holdBatch(batch, digest, outflow.Outflow{}, errors.Wrap(err, "failed to get token decimals"))
+		return nil, false, nil
+	}
+
+	o := outflow.Outflow{Batch: batchAmount(batch, decimals)}
+
+	var price *decimal.Decimal
+	if limit.UsesUSD() {
+		if p, err := l.priceFeed.QueryUSDPrice(tokenAddr); err != nil {
+			l.Log().WithError(err).WithField("token_contract", batch.TokenContract).Warningln("failed to query price feed for outflow limits")
+		} else {
+			price = new(decimal.Decimal)
+			*price = decimal.NewFromFloat(p)
+			o.BatchUSD = new(decimal.Decimal)
+			*o.BatchUSD = o.Batch.Mul(*price)
+		}
+	}
+
+	if l.store != nil && limit.Window > 0 {
+		records, err := l.store.Outflows(batch.TokenContract, now.Add(-limit.Window))
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, r := range signing {
+			if r.TokenContract == batch.TokenContract {
+				records = append(records, r)
+			}
+		}
+
+		var windowUSD decimal.Decimal
+		usdKnown := true
+		for _, r := range records {
+			// the batch may be recorded already if its confirmation failed to broadcast
+			if r.BatchNonce == batch.BatchNonce {
+				continue
+			}
+
+			o.Window = o.Window.Add(r.Amount)
+			if r.USDValue != nil {
+				windowUSD = windowUSD.Add(*r.USDValue)
+			} else if price != nil {
+				windowUSD = windowUSD.Add(r.Amount.Mul(*price))
+			} else {
+				usdKnown = false
+			}
+		}
+
+		if usdKnown {
+			o.WindowUSD = &windowUSD
+		}
+	} else {
+		o.WindowUSD = new(decimal.Decimal)
+	}
+
+	approved := false
+	if l.cfg.OutflowQueue != nil {
+		var err error
+		if approved, err = l.cfg.OutflowQueue.Approved(batch.TokenContract, batch.BatchNonce, digest); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := limit.Check(o); err != nil && !approved {
+		l.holdBatch(batch, digest, o, err)
+		return nil, false, nil
+	} else if err != nil {
+		l.Log().WithError(err).WithFields(log.Fields{"token_contract": batch.TokenContract, "batch_nonce": batch.BatchNonce}).Warningln("signing batch over outflow limits, approved by operator")
+	}
+
+	return &state.OutflowRecord{
+		TokenContract: batch.TokenContract,
+		BatchNonce:    batch.BatchNonce,
+		Amount:        o.Batch,
+		USDValue:      o.BatchUSD,
+		SignedAt:      now,
+	}, true, nil
+}
+
+// confirmedOutflows returns the outflows of the batches confirmed on Injective.
+func confirmedOutflows(outflows []state.OutflowRecord, confirmed []*peggytypes.OutgoingTxBatch) []state.OutflowRecord {
+	type batchKey struct {
+		token string
+		nonce uint64
+	}
+
+	sent := make(map[batchKey]bool, len(confirmed))
+	for _, batch := range confirmed {
+		sent[batchKey{token: batch.TokenContract, nonce: batch.BatchNonce}] = true
+	}
+
+	var records []state.OutflowRecord
+	for _, r := range outflows {
+		if sent[batchKey{token: r.TokenContract, nonce: r.BatchNonce}] {
+			records = append(records, r)
+		}
+	}
+
+	return records
+}
+
+// recordOutflows records the outflows of the batches whose confirmations were broadcast, and releases them from
+// the outflow queue. Batches refused or failing to broadcast do not count against the rolling window.
+func (l *signer) recordOutflows(outflows []state.OutflowRecord) error {
+	for _, r := range outflows {
+		if l.store != nil {
+			if err := l.store.RecordOutflow(r); err != nil {
+				return errors.Wrap(err, "failed to record batch outflow")
+			}
+		}
+
+		if l.cfg.OutflowQueue != nil {
+			if err := l.cfg.OutflowQueue.Release(r.TokenContract, r.BatchNonce); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *signer) holdBatch(batch *peggytypes.OutgoingTxBatch, digest gethcommon.Hash, o outflow.Outflow, reason error) {
+	fields := log.Fields{
+		"token_contract": batch.TokenContract,
+		"batch_nonce":    batch.BatchNonce,
+		"amount":         o.Batch.String(),
+		"window_amount":  o.Window.String(),
+		"digest":         digest.Hex(),
+	}
+
+	l.Log().WithError(reason).WithFields(fields).Errorln("holding batch over outflow limits, approve it with `peggo outflow approve` to sign it")
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("signer.outflow_held", tagSpec, 1)
+	}, metrics.Tags{"svc": l.svcTags["svc"], "token_contract": batch.TokenContract})
+
+	if l.cfg.OutflowQueue == nil {
+		return
+	}
+
+	if err := l.cfg.OutflowQueue.Hold(outflow.HeldBatch{
+		TokenContract: batch.TokenContract,
+		BatchNonce:    batch.BatchNonce,
+		Digest:        digest,
+		Amount:        o.Batch,
+		USDValue:      o.BatchUSD,
+		Reason:        reason.Error(),
+	}); err != nil {
+		l.Log().WithError(err).WithFields(fields).Errorln("failed to add batch to the outflow queue")
+	}
+}
+
+// batchAmount returns the total of the batch transfers and fees in token units, both are paid out by Peggy.sol
+func batchAmount(batch *peggytypes.OutgoingTxBatch, decimals uint8) decimal.Decimal {
+	total := sdkmath.ZeroInt()
+	for _, tx := range batch.Transactions {
+		if tx.Erc20Token != nil {
+			total = total.Add(tx.Erc20Token.Amount)
+		}
+
+		if tx.Erc20Fee != nil {
+			total = total.Add(tx.Erc20Fee.Amount)
+		}
+	}
+
+	return decimal.NewFromBigInt(total.BigInt(), -int32(decimals))
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// canSignValset records the valset checkpoint in the slashing protection database and reports whether it can be
+// signed, i.e. no different checkpoint was signed for the same valset nonce.
+func (l *signer) canSignValset(vs *peggytypes.Valset) (bool, error) {
+	if l.store == nil {
+		return true, nil
+	}
+
+	return l.recordSignature(state.SignedDigest{
+		Kind:   state.ValsetSignature,
+		Nonce:  vs.Nonce,
+		Digest: peggy.EncodeValsetConfirm(l.peggyID, vs),
+	})
+}
+
+// canSignBatch records the batch checkpoint in the slashing protection database and reports whether it can be
+// signed, i.e. no different checkpoint was signed for the same batch nonce.
+func (l *signer) canSignBatch(batch *peggytypes.OutgoingTxBatch) (bool, error) {
+	if l.store == nil {
+		return true, nil
+	}
+
+	return l.recordSignature(state.SignedDigest{
+		Kind:          state.BatchSignature,
+		Nonce:         batch.BatchNonce,
+		Digest:        peggy.EncodeTxBatchConfirm(l.peggyID, batch),
+		TokenContract: batch.TokenContract,
+	})
+}
+
+func (l *signer) recordSignature(d state.SignedDigest) (bool, error) {
+	d.PeggyID = l.peggyID
+
+	err := l.store.CheckAndRecordSignature(d)
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, state.ErrConflictingSignature):
+		l.Log().WithError(err).WithFields(log.Fields{"kind": d.Kind, "nonce": d.Nonce, "digest": d.Digest.Hex()}).Errorln("refusing to sign conflicting checkpoint, is another instance running with the same key or is the Injective node on a fork?")
+		l.reportConflictingSignature(d.Kind)
+
+		return false, nil
+	default:
+		return false, errors.Wrap(err, "failed to record signature in slashing protection database")
+	}
+}
+
+func (l *signer) reportConflictingSignature(kind state.SignatureKind) {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("signer.conflicting_signatures", tagSpec, 1)
+	}, metrics.Tags{"svc": l.svcTags["svc"], "kind": string(kind)})
+}
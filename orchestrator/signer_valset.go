@@ -0,0 +1,198 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/big"
+
+	cometrpc "github.com/cometbft/cometbft/rpc/core/types"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos/peggy"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// Number of Injective blocks it takes for the staking powers Peggy reads at the valset height to reach
+// the Tendermint validator set
+const valsetPowerDelay = 2
+
+// verifyValset rebuilds the bridge validator set from the Tendermint validator set and the delegate Ethereum
+// keys registered on Injective, normalizing the powers the way Peggy does. The staking powers Peggy reads at
+// the end of the valset height take effect in Tendermint one or two blocks later (depending on the order of the
+// end blockers), so the valset is accepted if it matches the Tendermint validator set at either height.
+// Validators are resolved with the staking module state at the valset height, so that validators unbonded since
+// are still known. It returns false (and raises an alert on mismatch) if the valset must not be signed, and an
+// error if it could not be verified.
+func (l *signer) verifyValset(ctx context.Context, vs *peggytypes.Valset) (bool, error) {
+	var latestHeight int64
+	if err := l.retry(ctx, func() (err error) {
+		latestHeight, err = l.injective.GetLatestBlockHeight(ctx)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	valsetHeight := int64(vs.Height)
+	if latestHeight <= valsetHeight {
+		l.Log().WithFields(log.Fields{"valset_nonce": vs.Nonce, "valset_height": vs.Height, "latest_height": latestHeight}).Debugln("Tendermint validator set of valset height not available yet")
+		return false, nil
+	}
+
+	var stakingValidators []stakingtypes.Validator
+	if err := l.retry(ctx, func() (err error) {
+		stakingValidators, err = l.injective.Validators(ctx, valsetHeight)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	keys := make(map[string]gethcommon.Address)
+	for height := valsetHeight + 1; height <= valsetHeight+valsetPowerDelay && height <= latestHeight; height++ {
+		expected, err := l.bridgeValidatorsAt(ctx, height, stakingValidators, keys)
+		if err != nil {
+			return false, err
+		}
+
+		if sameBridgeValidators(expected, vs.Members) {
+			return true, nil
+		}
+	}
+
+	l.Log().WithFields(log.Fields{"valset_nonce": vs.Nonce, "valset_height": vs.Height, "validators": len(vs.Members)}).Errorln("valset does not match the Tendermint validator set, refusing to sign it")
+	l.reportValsetMismatch()
+
+	return false, nil
+}
+
+// bridgeValidatorsAt returns the bridge validators of the Tendermint validator set at the given height
+func (l *signer) bridgeValidatorsAt(
+	ctx context.Context,
+	height int64,
+	stakingValidators []stakingtypes.Validator,
+	keys map[string]gethcommon.Address,
+) (peggytypes.BridgeValidators, error) {
+	var tmValidators *cometrpc.ResultValidators
+	if err := l.retry(ctx, func() (err error) {
+		tmValidators, err = l.injective.GetValidatorSet(ctx, height)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var (
+		members    peggytypes.BridgeValidators
+		totalPower uint64
+	)
+
+	for _, tmValidator := range tmValidators.Validators {
+		operator, err := validatorByConsAddr(stakingValidators, tmValidator.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		ethAddr, ok := keys[operator]
+		if !ok {
+			if ethAddr, err = l.delegateEthereumAddress(ctx, operator); err != nil {
+				return nil, err
+			}
+
+			keys[operator] = ethAddr
+		}
+
+		// validators without registered keys are not part of the bridge validator set
+		if ethAddr == (gethcommon.Address{}) {
+			continue
+		}
+
+		power := uint64(tmValidator.VotingPower)
+		members = append(members, &peggytypes.BridgeValidator{Power: power, EthereumAddress: ethAddr.Hex()})
+		totalPower += power
+	}
+
+	// normalize powers to the [0, 2^32] range of the Peggy contract
+	for _, m := range members {
+		normalized := new(big.Int).SetUint64(m.Power)
+		normalized.Mul(normalized, new(big.Int).SetUint64(math.MaxUint32))
+		normalized.Quo(normalized, new(big.Int).SetUint64(totalPower))
+		m.Power = normalized.Uint64()
+	}
+
+	return members, nil
+}
+
+// delegateEthereumAddress returns the Ethereum key registered by the validator, or the zero address if there is none
+func (l *signer) delegateEthereumAddress(ctx context.Context, operator string) (gethcommon.Address, error) {
+	valAddr, err := cosmostypes.ValAddressFromBech32(operator)
+	if err != nil {
+		return gethcommon.Address{}, errors.Wrapf(err, "failed to decode validator address: %s", operator)
+	}
+
+	var ethAddr gethcommon.Address
+	err = l.retry(ctx, func() (err error) {
+		// delegate keys are registered by the account of the validator
+		ethAddr, err = l.injective.GetDelegateEthereumAddress(ctx, cosmostypes.AccAddress(valAddr))
+		if errors.Is(err, peggy.ErrNotFound) {
+			ethAddr = gethcommon.Address{}
+			return nil
+		}
+
+		return err
+	})
+
+	return ethAddr, err
+}
+
+// validatorByConsAddr returns the operator address of the validator with the given consensus address
+func validatorByConsAddr(validators []stakingtypes.Validator, consAddr []byte) (string, error) {
+	for _, v := range validators {
+		addr, err := v.GetConsAddr()
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get consensus address of validator %s", v.OperatorAddress)
+		}
+
+		if bytes.Equal(addr, consAddr) {
+			return v.OperatorAddress, nil
+		}
+	}
+
+	return "", errors.Errorf("no validator with consensus address %X", consAddr)
+}
+
+// sameBridgeValidators reports whether both sets have the same members with the same powers, in any order
+func sameBridgeValidators(expected peggytypes.BridgeValidators, actual []*peggytypes.BridgeValidator) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	powers := make(map[gethcommon.Address]uint64, len(expected))
+	for _, m := range expected {
+		powers[gethcommon.HexToAddress(m.EthereumAddress)] = m.Power
+	}
+
+	for _, m := range actual {
+		if !gethcommon.IsHexAddress(m.EthereumAddress) {
+			return false
+		}
+
+		power, ok := powers[gethcommon.HexToAddress(m.EthereumAddress)]
+		if !ok || power != m.Power {
+			return false
+		}
+
+		delete(powers, gethcommon.HexToAddress(m.EthereumAddress))
+	}
+
+	return true
+}
+
+func (l *signer) reportValsetMismatch() {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("signer.valset_mismatch", tagSpec, 1)
+	}, l.svcTags)
+}
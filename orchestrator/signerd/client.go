@@ -0,0 +1,263 @@
+package signerd
+
+import (
+	"context"
+	"crypto/tls"
+	"math/big"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd/signerpb"
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// DefaultRequestTimeout is the timeout of a signing request
+const DefaultRequestTimeout = 10 * time.Second
+
+// Client signs with the keys held by a signer server. Nothing returned by the server is trusted: checkpoints
+// are computed locally and every signature is verified against the keys announced by the server.
+type Client struct {
+	conn    *grpc.ClientConn
+	signer  signerpb.SignerClient
+	timeout time.Duration
+
+	ethAddr          gethcommon.Address
+	injPubKey        cryptotypes.PubKey
+	peggyID          gethcommon.Hash
+	ethChainID       uint64
+	injectiveChainID string
+}
+
+// Dial connects to the signer server at addr and fetches its keys. A nil TLS config makes a plaintext connection.
+func Dial(addr string, tlsCfg *tls.Config, timeout time.Duration) (*Client, error) {
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to signer %s", addr)
+	}
+
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	c := &Client{
+		conn:    conn,
+		signer:  signerpb.NewSignerClient(conn),
+		timeout: timeout,
+	}
+
+	if err := c.loadKeys(); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrapf(err, "failed to get keys of signer %s", addr)
+	}
+
+	return c, nil
+}
+
+func (c *Client) loadKeys() error {
+	ctx, cancelFn := context.WithTimeout(context.Background(), c.timeout)
+	defer cancelFn()
+
+	keys, err := c.signer.Keys(ctx, &signerpb.KeysRequest{})
+	if err != nil {
+		return err
+	}
+
+	if !gethcommon.IsHexAddress(keys.EthereumAddress) {
+		return errors.Errorf("invalid Ethereum address %q", keys.EthereumAddress)
+	}
+
+	if len(keys.InjectivePubKey) != ethsecp256k1.PubKeySize {
+		return errors.Errorf("invalid Injective public key length %d", len(keys.InjectivePubKey))
+	}
+
+	c.ethAddr = gethcommon.HexToAddress(keys.EthereumAddress)
+	c.injPubKey = &ethsecp256k1.PubKey{Key: keys.InjectivePubKey}
+	c.peggyID = gethcommon.BytesToHash(keys.PeggyId)
+	c.ethChainID = keys.EthereumChainId
+	c.injectiveChainID = keys.InjectiveChainId
+
+	return nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) EthereumAddress() gethcommon.Address {
+	return c.ethAddr
+}
+
+func (c *Client) PeggyID() gethcommon.Hash {
+	return c.peggyID
+}
+
+func (c *Client) EthereumChainID() uint64 {
+	return c.ethChainID
+}
+
+func (c *Client) InjectiveChainID() string {
+	return c.injectiveChainID
+}
+
+// SignValsetConfirm implements peggy.ConfirmSigner.
+func (c *Client) SignValsetConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, valset *peggytypes.Valset) (gethcommon.Hash, []byte, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), c.timeout)
+	defer cancelFn()
+
+	resp, err := c.signer.SignValsetConfirm(ctx, &signerpb.SignValsetConfirmRequest{
+		PeggyId: peggyID.Bytes(),
+		Valset:  valsetToProto(valset),
+	})
+	if err != nil {
+		return gethcommon.Hash{}, nil, errors.Wrap(err, "signer refused to sign valset checkpoint")
+	}
+
+	return c.verifyConfirm(ethFrom, peggy.EncodeValsetConfirm(peggyID, valset), resp)
+}
+
+// SignBatchConfirm implements peggy.ConfirmSigner.
+func (c *Client) SignBatchConfirm(ethFrom gethcommon.Address, peggyID gethcommon.Hash, batch *peggytypes.OutgoingTxBatch) (gethcommon.Hash, []byte, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), c.timeout)
+	defer cancelFn()
+
+	resp, err := c.signer.SignBatchConfirm(ctx, &signerpb.SignBatchConfirmRequest{
+		PeggyId: peggyID.Bytes(),
+		Batch:   batchToProto(batch),
+	})
+	if err != nil {
+		return gethcommon.Hash{}, nil, errors.Wrap(err, "signer refused to sign batch checkpoint")
+	}
+
+	return c.verifyConfirm(ethFrom, peggy.EncodeTxBatchConfirm(peggyID, batch), resp)
+}
+
+// verifyConfirm checks that the server signed the expected checkpoint with the Ethereum key
+func (c *Client) verifyConfirm(ethFrom gethcommon.Address, digest gethcommon.Hash, resp *signerpb.SignConfirmResponse) (gethcommon.Hash, []byte, error) {
+	if ethFrom != c.ethAddr {
+		return gethcommon.Hash{}, nil, errors.Errorf("signer holds Ethereum key %s, not %s", c.ethAddr.Hex(), ethFrom.Hex())
+	}
+
+	if gethcommon.BytesToHash(resp.Digest) != digest {
+		return gethcommon.Hash{}, nil, errors.Errorf("signer signed checkpoint %x instead of %s", resp.Digest, digest.Hex())
+	}
+
+	if err := verifyEthereumSignature(c.ethAddr, accounts.TextHash(digest.Bytes()), resp.Signature); err != nil {
+		return gethcommon.Hash{}, nil, err
+	}
+
+	return digest, resp.Signature, nil
+}
+
+// EthereumSignerFn returns a SignerFn signing transactions with the Ethereum key of the server.
+func (c *Client) EthereumSignerFn(chainID uint64) bind.SignerFn {
+	signer := gethtypes.LatestSignerForChainID(new(big.Int).SetUint64(chainID))
+
+	return func(from gethcommon.Address, tx *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+		if from != c.ethAddr {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode transaction")
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), c.timeout)
+		defer cancelFn()
+
+		resp, err := c.signer.SignEthereumTx(ctx, &signerpb.SignEthereumTxRequest{ChainId: chainID, Tx: raw})
+		if err != nil {
+			return nil, errors.Wrap(err, "signer refused to sign Ethereum transaction")
+		}
+
+		signedTx := new(gethtypes.Transaction)
+		if err := signedTx.UnmarshalBinary(resp.Tx); err != nil {
+			return nil, errors.Wrap(err, "failed to decode signed transaction")
+		}
+
+		if signer.Hash(signedTx) != signer.Hash(tx) {
+			return nil, errors.New("transaction signed by the signer differs from the requested one")
+		}
+
+		sender, err := gethtypes.Sender(signer, signedTx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to recover transaction sender")
+		}
+
+		if sender != c.ethAddr {
+			return nil, errors.Errorf("signer signed with %s instead of %s", sender.Hex(), c.ethAddr.Hex())
+		}
+
+		return signedTx, nil
+	}
+}
+
+// InjectiveTxSigner returns the signer of Injective transactions, to be used with cosmos.NewTxSignerKeyring.
+func (c *Client) InjectiveTxSigner() *InjectiveTxSigner {
+	return &InjectiveTxSigner{client: c}
+}
+
+// InjectiveTxSigner signs Injective transactions with the Injective key of the server.
+type InjectiveTxSigner struct {
+	client *Client
+}
+
+func (s *InjectiveTxSigner) PubKey() cryptotypes.PubKey {
+	return s.client.injPubKey
+}
+
+func (s *InjectiveTxSigner) SignTx(signDoc []byte) ([]byte, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), s.client.timeout)
+	defer cancelFn()
+
+	resp, err := s.client.signer.SignInjectiveTx(ctx, &signerpb.SignInjectiveTxRequest{SignDoc: signDoc})
+	if err != nil {
+		return nil, errors.Wrap(err, "signer refused to sign Injective transaction")
+	}
+
+	if !s.client.injPubKey.VerifySignature(signDoc, resp.Signature) {
+		return nil, errors.New("invalid Injective transaction signature from the signer")
+	}
+
+	return resp.Signature, nil
+}
+
+func verifyEthereumSignature(account gethcommon.Address, hash, sig []byte) error {
+	if len(sig) != crypto.SignatureLength {
+		return errors.Errorf("invalid signature length %d", len(sig))
+	}
+
+	// some key backends return V in the {27, 28} range
+	sig = gethcommon.CopyBytes(sig)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to recover signer")
+	}
+
+	if signer := crypto.PubkeyToAddress(*pubKey); signer != account {
+		return errors.Errorf("signer signed with %s instead of %s", signer.Hex(), account.Hex())
+	}
+
+	return nil
+}
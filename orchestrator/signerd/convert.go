@@ -0,0 +1,135 @@
+package signerd
+
+import (
+	sdkmath "cosmossdk.io/math"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd/signerpb"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+func valsetToProto(vs *peggytypes.Valset) *signerpb.Valset {
+	members := make([]*signerpb.BridgeValidator, len(vs.Members))
+	for i, m := range vs.Members {
+		members[i] = &signerpb.BridgeValidator{EthereumAddress: m.EthereumAddress, Power: m.Power}
+	}
+
+	return &signerpb.Valset{
+		Nonce:        vs.Nonce,
+		Members:      members,
+		Height:       vs.Height,
+		RewardAmount: vs.RewardAmount.String(),
+		RewardToken:  vs.RewardToken,
+	}
+}
+
+func valsetFromProto(vs *signerpb.Valset) (*peggytypes.Valset, error) {
+	if vs == nil {
+		return nil, errors.New("missing valset")
+	}
+
+	rewardAmount, err := parseInt(vs.RewardAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid valset reward amount")
+	}
+
+	members := make([]*peggytypes.BridgeValidator, len(vs.Members))
+	for i, m := range vs.Members {
+		members[i] = &peggytypes.BridgeValidator{EthereumAddress: m.EthereumAddress, Power: m.Power}
+	}
+
+	return &peggytypes.Valset{
+		Nonce:        vs.Nonce,
+		Members:      members,
+		Height:       vs.Height,
+		RewardAmount: rewardAmount,
+		RewardToken:  vs.RewardToken,
+	}, nil
+}
+
+func batchToProto(batch *peggytypes.OutgoingTxBatch) *signerpb.OutgoingTxBatch {
+	txs := make([]*signerpb.OutgoingTransferTx, len(batch.Transactions))
+	for i, tx := range batch.Transactions {
+		txs[i] = &signerpb.OutgoingTransferTx{
+			Id:          tx.Id,
+			Sender:      tx.Sender,
+			DestAddress: tx.DestAddress,
+			Erc20Token:  erc20TokenToProto(tx.Erc20Token),
+			Erc20Fee:    erc20TokenToProto(tx.Erc20Fee),
+		}
+	}
+
+	return &signerpb.OutgoingTxBatch{
+		BatchNonce:    batch.BatchNonce,
+		BatchTimeout:  batch.BatchTimeout,
+		Transactions:  txs,
+		TokenContract: batch.TokenContract,
+		Block:         batch.Block,
+	}
+}
+
+func batchFromProto(batch *signerpb.OutgoingTxBatch) (*peggytypes.OutgoingTxBatch, error) {
+	if batch == nil {
+		return nil, errors.New("missing batch")
+	}
+
+	txs := make([]*peggytypes.OutgoingTransferTx, len(batch.Transactions))
+	for i, tx := range batch.Transactions {
+		token, err := erc20TokenFromProto(tx.Erc20Token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid token of batch tx %d", tx.Id)
+		}
+
+		fee, err := erc20TokenFromProto(tx.Erc20Fee)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid fee of batch tx %d", tx.Id)
+		}
+
+		txs[i] = &peggytypes.OutgoingTransferTx{
+			Id:          tx.Id,
+			Sender:      tx.Sender,
+			DestAddress: tx.DestAddress,
+			Erc20Token:  token,
+			Erc20Fee:    fee,
+		}
+	}
+
+	return &peggytypes.OutgoingTxBatch{
+		BatchNonce:    batch.BatchNonce,
+		BatchTimeout:  batch.BatchTimeout,
+		Transactions:  txs,
+		TokenContract: batch.TokenContract,
+		Block:         batch.Block,
+	}, nil
+}
+
+func erc20TokenToProto(token *peggytypes.ERC20Token) *signerpb.ERC20Token {
+	if token == nil {
+		return nil
+	}
+
+	return &signerpb.ERC20Token{Contract: token.Contract, Amount: token.Amount.String()}
+}
+
+func erc20TokenFromProto(token *signerpb.ERC20Token) (*peggytypes.ERC20Token, error) {
+	if token == nil {
+		return nil, errors.New("missing ERC20 token")
+	}
+
+	amount, err := parseInt(token.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &peggytypes.ERC20Token{Contract: token.Contract, Amount: amount}, nil
+}
+
+// parseInt parses a non-negative decimal integer, since checkpoints encode amounts as uint256
+func parseInt(s string) (sdkmath.Int, error) {
+	i, ok := sdkmath.NewIntFromString(s)
+	if !ok || i.IsNegative() {
+		return sdkmath.Int{}, errors.Errorf("invalid amount %q", s)
+	}
+
+	return i, nil
+}
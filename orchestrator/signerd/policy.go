@@ -0,0 +1,93 @@
+package signerd
+
+import (
+	"fmt"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+)
+
+// ErrNonceNotMonotonic is returned when a valset checkpoint is requested for a nonce lower than the highest one
+// signed, which a healthy Injective node never asks for.
+var ErrNonceNotMonotonic = errors.New("nonce is not monotonic")
+
+// checkpointPolicy checks the checkpoints before they are signed: on top of the slashing protection database,
+// which refuses to sign two different digests for the same nonce, valset nonces must only increase. Batches may be
+// signed out of order, since a batch held by the outflow queue is approved after later batches of its token, and
+// the Peggy contract ignores a batch older than the last one relayed for its token. A digest already signed can be
+// signed again, so that the orchestrator can retry failed confirmations.
+type checkpointPolicy struct {
+	mux sync.Mutex
+
+	peggyID gethcommon.Hash
+	store   state.SlashingProtection
+
+	// digests signed for the peggy ID, by kind and nonce
+	signed map[string]gethcommon.Hash
+	// highest valset nonce signed
+	highestValset uint64
+}
+
+func newCheckpointPolicy(peggyID gethcommon.Hash, store state.SlashingProtection) (*checkpointPolicy, error) {
+	p := &checkpointPolicy{
+		peggyID: peggyID,
+		store:   store,
+		signed:  make(map[string]gethcommon.Hash),
+	}
+
+	digests, err := store.SignedDigests()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load signed digests")
+	}
+
+	for _, d := range digests {
+		if d.PeggyID == peggyID {
+			p.remember(d)
+		}
+	}
+
+	return p, nil
+}
+
+// checkAndRecord records the digest in the slashing protection database if it can be signed
+func (p *checkpointPolicy) checkAndRecord(d state.SignedDigest) error {
+	d.PeggyID = p.peggyID
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if digest, ok := p.signed[digestKey(d)]; ok {
+		if digest != d.Digest {
+			return errors.Wrapf(state.ErrConflictingSignature, "%s nonce %d already signed with digest %s, refusing to sign %s", d.Kind, d.Nonce, digest.Hex(), d.Digest.Hex())
+		}
+
+		return nil
+	}
+
+	if d.Kind == state.ValsetSignature && d.Nonce < p.highestValset {
+		return errors.Wrapf(ErrNonceNotMonotonic, "%s nonce %d is lower than the last signed nonce %d", d.Kind, d.Nonce, p.highestValset)
+	}
+
+	if err := p.store.CheckAndRecordSignature(d); err != nil {
+		return err
+	}
+
+	p.remember(d)
+
+	return nil
+}
+
+func (p *checkpointPolicy) remember(d state.SignedDigest) {
+	p.signed[digestKey(d)] = d.Digest
+
+	if d.Kind == state.ValsetSignature && d.Nonce > p.highestValset {
+		p.highestValset = d.Nonce
+	}
+}
+
+func digestKey(d state.SignedDigest) string {
+	return fmt.Sprintf("%s/%d", d.Kind, d.Nonce)
+}
@@ -0,0 +1,375 @@
+package signerd
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	cosmostx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	log "github.com/xlab/suplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/InjectiveLabs/peggo/orchestrator/audit"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd/signerpb"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+// signedMsgTypes are the only messages signed with the Injective key: the ones the orchestrator sends in its loops.
+// Other Peggy messages, such as MsgSendToEth or MsgSetOrchestratorAddresses, would let a compromised orchestrator
+// host move funds or delegate keys.
+var signedMsgTypes = map[string]bool{
+	cosmostypes.MsgTypeURL(&peggytypes.MsgDepositClaim{}):       true,
+	cosmostypes.MsgTypeURL(&peggytypes.MsgWithdrawClaim{}):      true,
+	cosmostypes.MsgTypeURL(&peggytypes.MsgValsetUpdatedClaim{}): true,
+	cosmostypes.MsgTypeURL(&peggytypes.MsgERC20DeployedClaim{}): true,
+	cosmostypes.MsgTypeURL(&peggytypes.MsgValsetConfirm{}):      true,
+	cosmostypes.MsgTypeURL(&peggytypes.MsgConfirmBatch{}):       true,
+	cosmostypes.MsgTypeURL(&peggytypes.MsgRequestBatch{}):       true,
+}
+
+// Config is the configuration of the signer server: the keys it holds and the networks it signs for.
+type Config struct {
+	PeggyID          gethcommon.Hash
+	EthereumChainID  uint64
+	InjectiveChainID string
+
+	// PeggyContractAddr is the only recipient of the Ethereum transactions signed
+	PeggyContractAddr gethcommon.Address
+
+	EthereumAddr           gethcommon.Address
+	EthereumSignerFn       bind.SignerFn
+	EthereumPersonalSignFn keystore.PersonalSignFn
+
+	InjectiveKeyring keyring.Keyring
+	InjectiveAddr    cosmostypes.AccAddress
+
+	// SlashingProtection records the signed checkpoints, it must be kept across restarts
+	SlashingProtection state.SlashingProtection
+
+	// AuditLog records the signatures produced with the Ethereum key. Nil disables it.
+	AuditLog *audit.Log
+}
+
+// Server signs checkpoints and transactions for a remote orchestrator. Every request is checked against the
+// peggy ID and chain IDs of the configuration, and valset nonces must never decrease.
+type Server struct {
+	signerpb.UnimplementedSignerServer
+
+	cfg       Config
+	ethSigner gethtypes.Signer
+	injPubKey cryptotypes.PubKey
+	policy    *checkpointPolicy
+	svcTags   metrics.Tags
+}
+
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.PeggyID == (gethcommon.Hash{}) {
+		return nil, errors.New("peggy ID must be set")
+	}
+
+	if cfg.EthereumChainID == 0 || cfg.InjectiveChainID == "" {
+		return nil, errors.New("Ethereum and Injective chain IDs must be set")
+	}
+
+	if cfg.PeggyContractAddr == (gethcommon.Address{}) {
+		return nil, errors.New("Peggy contract address must be set")
+	}
+
+	record, err := cfg.InjectiveKeyring.KeyByAddress(cfg.InjectiveAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find Injective key")
+	}
+
+	injPubKey, err := record.GetPubKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Injective public key")
+	}
+
+	policy, err := newCheckpointPolicy(cfg.PeggyID, cfg.SlashingProtection)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		cfg:       cfg,
+		ethSigner: gethtypes.LatestSignerForChainID(new(big.Int).SetUint64(cfg.EthereumChainID)),
+		injPubKey: injPubKey,
+		policy:    policy,
+		svcTags:   metrics.Tags{"svc": "signerd"},
+	}
+
+	return s, nil
+}
+
+func (s *Server) Keys(_ context.Context, _ *signerpb.KeysRequest) (*signerpb.KeysResponse, error) {
+	return &signerpb.KeysResponse{
+		EthereumAddress:  s.cfg.EthereumAddr.Hex(),
+		InjectivePubKey:  s.injPubKey.Bytes(),
+		PeggyId:          s.cfg.PeggyID.Bytes(),
+		EthereumChainId:  s.cfg.EthereumChainID,
+		InjectiveChainId: s.cfg.InjectiveChainID,
+	}, nil
+}
+
+func (s *Server) SignValsetConfirm(_ context.Context, req *signerpb.SignValsetConfirmRequest) (*signerpb.SignConfirmResponse, error) {
+	metrics.ReportFuncCall(s.svcTags)
+	doneFn := metrics.ReportFuncTiming(s.svcTags)
+	defer doneFn()
+
+	if err := s.checkPeggyID(req.PeggyId); err != nil {
+		return nil, s.refuse("valset_confirm", err)
+	}
+
+	vs, err := valsetFromProto(req.Valset)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	digest := peggy.EncodeValsetConfirm(s.cfg.PeggyID, vs)
+	if err := s.policy.checkAndRecord(state.SignedDigest{
+		Kind:   state.ValsetSignature,
+		Nonce:  vs.Nonce,
+		Digest: digest,
+	}); err != nil {
+		return nil, s.refuse("valset_confirm", err)
+	}
+
+	signature, err := s.cfg.EthereumPersonalSignFn(s.cfg.EthereumAddr, digest.Bytes())
+	if err != nil {
+		metrics.ReportFuncError(s.svcTags)
+		return nil, status.Error(codes.Internal, errors.Wrap(err, "failed to sign valset checkpoint").Error())
+	}
+
+	s.cfg.AuditLog.Append(audit.Record{
+		Kind:   audit.ValsetConfirm,
+		Digest: digest,
+		Nonce:  vs.Nonce,
+		Signer: s.cfg.EthereumAddr,
+	})
+
+	log.WithFields(log.Fields{"valset_nonce": vs.Nonce, "digest": digest.Hex()}).Infoln("signed valset checkpoint")
+
+	return &signerpb.SignConfirmResponse{Digest: digest.Bytes(), Signature: signature}, nil
+}
+
+func (s *Server) SignBatchConfirm(_ context.Context, req *signerpb.SignBatchConfirmRequest) (*signerpb.SignConfirmResponse, error) {
+	metrics.ReportFuncCall(s.svcTags)
+	doneFn := metrics.ReportFuncTiming(s.svcTags)
+	defer doneFn()
+
+	if err := s.checkPeggyID(req.PeggyId); err != nil {
+		return nil, s.refuse("batch_confirm", err)
+	}
+
+	batch, err := batchFromProto(req.Batch)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if !gethcommon.IsHexAddress(batch.TokenContract) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid token contract %q", batch.TokenContract)
+	}
+
+	digest := peggy.EncodeTxBatchConfirm(s.cfg.PeggyID, batch)
+	if err := s.policy.checkAndRecord(state.SignedDigest{
+		Kind:          state.BatchSignature,
+		Nonce:         batch.BatchNonce,
+		Digest:        digest,
+		TokenContract: batch.TokenContract,
+	}); err != nil {
+		return nil, s.refuse("batch_confirm", err)
+	}
+
+	signature, err := s.cfg.EthereumPersonalSignFn(s.cfg.EthereumAddr, digest.Bytes())
+	if err != nil {
+		metrics.ReportFuncError(s.svcTags)
+		return nil, status.Error(codes.Internal, errors.Wrap(err, "failed to sign batch checkpoint").Error())
+	}
+
+	s.cfg.AuditLog.Append(audit.Record{
+		Kind:          audit.BatchConfirm,
+		Digest:        digest,
+		Nonce:         batch.BatchNonce,
+		TokenContract: batch.TokenContract,
+		Signer:        s.cfg.EthereumAddr,
+	})
+
+	log.WithFields(log.Fields{"token_contract": batch.TokenContract, "batch_nonce": batch.BatchNonce, "digest": digest.Hex()}).Infoln("signed batch checkpoint")
+
+	return &signerpb.SignConfirmResponse{Digest: digest.Bytes(), Signature: signature}, nil
+}
+
+func (s *Server) SignEthereumTx(_ context.Context, req *signerpb.SignEthereumTxRequest) (*signerpb.SignEthereumTxResponse, error) {
+	metrics.ReportFuncCall(s.svcTags)
+	doneFn := metrics.ReportFuncTiming(s.svcTags)
+	defer doneFn()
+
+	if req.ChainId != s.cfg.EthereumChainID {
+		return nil, s.refuse("eth_tx", errors.Errorf("Ethereum chain ID %d does not match %d", req.ChainId, s.cfg.EthereumChainID))
+	}
+
+	tx := new(gethtypes.Transaction)
+	if err := tx.UnmarshalBinary(req.Tx); err != nil {
+		return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "failed to decode transaction").Error())
+	}
+
+	// typed transactions carry the chain ID they are signed for
+	if tx.Type() != gethtypes.LegacyTxType && tx.ChainId().Uint64() != s.cfg.EthereumChainID {
+		return nil, s.refuse("eth_tx", errors.Errorf("transaction chain ID %s does not match %d", tx.ChainId(), s.cfg.EthereumChainID))
+	}
+
+	if err := s.checkPeggyTx(tx); err != nil {
+		return nil, s.refuse("eth_tx", err)
+	}
+
+	signedTx, err := s.cfg.EthereumSignerFn(s.cfg.EthereumAddr, tx)
+	if err != nil {
+		metrics.ReportFuncError(s.svcTags)
+		return nil, status.Error(codes.Internal, errors.Wrap(err, "failed to sign Ethereum transaction").Error())
+	}
+
+	// make sure the key backend signed for the configured chain ID
+	if sender, err := gethtypes.Sender(s.ethSigner, signedTx); err != nil || sender != s.cfg.EthereumAddr {
+		metrics.ReportFuncError(s.svcTags)
+		return nil, status.Error(codes.Internal, "Ethereum transaction was not signed for the configured chain ID")
+	}
+
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		metrics.ReportFuncError(s.svcTags)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.cfg.AuditLog.Append(audit.Record{
+		Kind:   audit.EthTx,
+		Digest: s.ethSigner.Hash(signedTx),
+		Nonce:  signedTx.Nonce(),
+		Signer: s.cfg.EthereumAddr,
+		TxHash: signedTx.Hash().Hex(),
+	})
+
+	log.WithFields(log.Fields{"tx_hash": signedTx.Hash().Hex(), "nonce": signedTx.Nonce()}).Infoln("signed Ethereum transaction")
+
+	return &signerpb.SignEthereumTxResponse{Tx: raw}, nil
+}
+
+func (s *Server) SignInjectiveTx(_ context.Context, req *signerpb.SignInjectiveTxRequest) (*signerpb.SignInjectiveTxResponse, error) {
+	metrics.ReportFuncCall(s.svcTags)
+	doneFn := metrics.ReportFuncTiming(s.svcTags)
+	defer doneFn()
+
+	var signDoc cosmostx.SignDoc
+	if err := signDoc.Unmarshal(req.SignDoc); err != nil {
+		return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "failed to decode sign doc").Error())
+	}
+
+	if signDoc.ChainId != s.cfg.InjectiveChainID {
+		return nil, s.refuse("injective_tx", errors.Errorf("Injective chain ID %s does not match %s", signDoc.ChainId, s.cfg.InjectiveChainID))
+	}
+
+	var body cosmostx.TxBody
+	if err := body.Unmarshal(signDoc.BodyBytes); err != nil {
+		return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "failed to decode tx body").Error())
+	}
+
+	if len(body.Messages) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "transaction has no messages")
+	}
+
+	for _, msg := range body.Messages {
+		if !signedMsgTypes[msg.TypeUrl] {
+			return nil, s.refuse("injective_tx", errors.Errorf("message %s is not an orchestrator message", msg.TypeUrl))
+		}
+	}
+
+	var authInfo cosmostx.AuthInfo
+	if err := authInfo.Unmarshal(signDoc.AuthInfoBytes); err != nil {
+		return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "failed to decode tx auth info").Error())
+	}
+
+	if !s.signedByInjectiveKeyOnly(authInfo) {
+		return nil, s.refuse("injective_tx", errors.New("transaction is not signed by the Injective key only"))
+	}
+
+	signature, _, err := s.cfg.InjectiveKeyring.SignByAddress(s.cfg.InjectiveAddr, req.SignDoc, signing.SignMode_SIGN_MODE_DIRECT)
+	if err != nil {
+		metrics.ReportFuncError(s.svcTags)
+		return nil, status.Error(codes.Internal, errors.Wrap(err, "failed to sign Injective transaction").Error())
+	}
+
+	log.WithFields(log.Fields{"account_number": signDoc.AccountNumber, "msgs": len(body.Messages)}).Infoln("signed Injective transaction")
+
+	return &signerpb.SignInjectiveTxResponse{Signature: signature}, nil
+}
+
+func (s *Server) signedByInjectiveKeyOnly(authInfo cosmostx.AuthInfo) bool {
+	if len(authInfo.SignerInfos) != 1 || authInfo.SignerInfos[0].PublicKey == nil {
+		return false
+	}
+
+	var pubKey ethsecp256k1.PubKey
+	if err := pubKey.Unmarshal(authInfo.SignerInfos[0].PublicKey.Value); err != nil {
+		return false
+	}
+
+	return pubKey.Equals(s.injPubKey)
+}
+
+// checkPeggyTx makes sure the transaction only relays a batch or a valset update: a call to the Peggy contract
+// without value, so that a compromised orchestrator host cannot get ETH or tokens sent elsewhere.
+func (s *Server) checkPeggyTx(tx *gethtypes.Transaction) error {
+	if tx.To() == nil {
+		return errors.New("contract creation is not allowed")
+	}
+
+	if *tx.To() != s.cfg.PeggyContractAddr {
+		return errors.Errorf("recipient %s is not the Peggy contract %s", tx.To().Hex(), s.cfg.PeggyContractAddr.Hex())
+	}
+
+	if tx.Value().Sign() != 0 {
+		return errors.Errorf("transaction sends %s wei", tx.Value())
+	}
+
+	if len(tx.Data()) < 4 || !peggy.IsBatchOrValsetUpdateTx(tx.Data()) {
+		return errors.New("transaction does not call submitBatch or updateValset")
+	}
+
+	return nil
+}
+
+func (s *Server) checkPeggyID(peggyID []byte) error {
+	if !bytes.Equal(peggyID, s.cfg.PeggyID.Bytes()) {
+		return errors.Errorf("peggy ID %x does not match %s", peggyID, s.cfg.PeggyID.Hex())
+	}
+
+	return nil
+}
+
+// refuse logs and reports a request refused by the signing policy
+func (s *Server) refuse(kind string, err error) error {
+	log.WithError(err).WithField("kind", kind).Warningln("refused to sign")
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		_ = s.Incr("signerd.refused", tagSpec, 1)
+	}, metrics.Tags{"svc": s.svcTags["svc"], "kind": kind})
+
+	if errors.Is(err, state.ErrConflictingSignature) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	return status.Error(codes.PermissionDenied, err.Error())
+}
@@ -0,0 +1,346 @@
+package signerd
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cosmostx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/InjectiveLabs/peggo/orchestrator/cosmos"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/keystore"
+	"github.com/InjectiveLabs/peggo/orchestrator/ethereum/peggy"
+	"github.com/InjectiveLabs/peggo/orchestrator/signerd/signerpb"
+	"github.com/InjectiveLabs/peggo/orchestrator/state"
+	wrappers "github.com/InjectiveLabs/peggo/solidity/wrappers/Peggy.sol"
+	peggytypes "github.com/InjectiveLabs/sdk-go/chain/peggy/types"
+)
+
+const (
+	testEthChainID       = 5
+	testInjectiveChainID = "injective-888"
+)
+
+var (
+	testPeggyID  = gethcommon.HexToHash("0x696e6a6563746976652d7065676779696400000000000000000000000000000000")
+	testPeggy    = gethcommon.HexToAddress("0x5048019d259217e6b7BC8e1E6aEfa9976B1ADFfe")
+	tokenA       = "0x36B3D7ACe7201E28040eFf30e815290D7b37ffaD"
+	tokenB       = "0xF955C57f9EA9Dc8781965FEaE0b6A2acE2BAD6f3"
+	testEthKey   = "e85344a3f1d4a1b0b87a6ff6c2f1c4a8c3c1a1c1d6d1a6f5e4d3c2b1a0f9e8d7"
+	testCosmosPK = "b1bab9e4b9d2e6a2e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5"
+)
+
+type testSigner struct {
+	server *Server
+	client *Client
+	ethKey gethcommon.Address
+}
+
+// newTestSigner serves the signer over an in-memory connection
+func newTestSigner(t *testing.T, store state.SlashingProtection) *testSigner {
+	ethKey, err := crypto.HexToECDSA(testEthKey)
+	require.NoError(t, err)
+
+	ethAddr := crypto.PubkeyToAddress(ethKey.PublicKey)
+	signerFn, err := keystore.PrivateKeyPersonalSignFn(ethKey)
+	require.NoError(t, err)
+
+	kr, err := cosmos.NewKeyring(cosmos.KeyringConfig{PrivateKey: testCosmosPK})
+	require.NoError(t, err)
+
+	server, err := NewServer(Config{
+		PeggyID:           testPeggyID,
+		EthereumChainID:   testEthChainID,
+		InjectiveChainID:  testInjectiveChainID,
+		PeggyContractAddr: testPeggy,
+		EthereumAddr:      ethAddr,
+		EthereumSignerFn: func(from gethcommon.Address, tx *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+			return gethtypes.SignTx(tx, gethtypes.LatestSignerForChainID(big.NewInt(testEthChainID)), ethKey)
+		},
+		EthereumPersonalSignFn: signerFn,
+		InjectiveKeyring:       kr,
+		InjectiveAddr:          kr.Addr,
+		SlashingProtection:     store,
+	})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	signerpb.RegisterSignerServer(grpcServer, server)
+
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	client := &Client{conn: conn, signer: signerpb.NewSignerClient(conn), timeout: DefaultRequestTimeout}
+	require.NoError(t, client.loadKeys())
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &testSigner{server: server, client: client, ethKey: ethAddr}
+}
+
+func newTestStore(t *testing.T) state.Store {
+	store, err := state.NewStore(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+func testValset(nonce uint64) *peggytypes.Valset {
+	return &peggytypes.Valset{
+		Nonce:        nonce,
+		Height:       100 + nonce,
+		RewardAmount: sdkmath.ZeroInt(),
+		Members: []*peggytypes.BridgeValidator{
+			{EthereumAddress: "0x0000000000000000000000000000000000000001", Power: 1 << 31},
+			{EthereumAddress: "0x0000000000000000000000000000000000000002", Power: 1 << 30},
+		},
+	}
+}
+
+func testBatch(token string, nonce uint64) *peggytypes.OutgoingTxBatch {
+	return &peggytypes.OutgoingTxBatch{
+		BatchNonce:    nonce,
+		BatchTimeout:  1000,
+		TokenContract: token,
+		Transactions: []*peggytypes.OutgoingTransferTx{{
+			Id:          nonce,
+			Sender:      "inj1jcltmuhplrdcwp7stlr4hlhlhgd4htqhe4c0cs",
+			DestAddress: "0x0000000000000000000000000000000000000003",
+			Erc20Token:  &peggytypes.ERC20Token{Contract: token, Amount: sdkmath.NewInt(1000)},
+			Erc20Fee:    &peggytypes.ERC20Token{Contract: token, Amount: sdkmath.NewInt(10)},
+		}},
+	}
+}
+
+func assertCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+
+	require.Error(t, err)
+	assert.Equal(t, code, status.Code(errors.Cause(err)), err.Error())
+}
+
+func TestKeys(t *testing.T) {
+	s := newTestSigner(t, newTestStore(t))
+
+	assert.Equal(t, s.ethKey, s.client.EthereumAddress())
+	assert.Equal(t, testPeggyID, s.client.PeggyID())
+	assert.Equal(t, uint64(testEthChainID), s.client.EthereumChainID())
+	assert.Equal(t, testInjectiveChainID, s.client.InjectiveChainID())
+
+	kr, err := cosmos.NewKeyring(cosmos.KeyringConfig{PrivateKey: testCosmosPK})
+	require.NoError(t, err)
+
+	signerKr, err := cosmos.NewTxSignerKeyring(s.client.InjectiveTxSigner(), kr.Addr.String())
+	require.NoError(t, err)
+	assert.Equal(t, kr.Addr, signerKr.Addr)
+}
+
+func TestSignValsetConfirm(t *testing.T) {
+	s := newTestSigner(t, newTestStore(t))
+
+	digest, sig, err := s.client.SignValsetConfirm(s.ethKey, testPeggyID, testValset(2))
+	require.NoError(t, err)
+	assert.Equal(t, peggy.EncodeValsetConfirm(testPeggyID, testValset(2)), digest)
+
+	pubKey, err := crypto.SigToPub(accounts.TextHash(digest.Bytes()), sig)
+	require.NoError(t, err)
+	assert.Equal(t, s.ethKey, crypto.PubkeyToAddress(*pubKey))
+
+	// signing the same checkpoint again is allowed, so that confirmations can be retried
+	_, _, err = s.client.SignValsetConfirm(s.ethKey, testPeggyID, testValset(2))
+	require.NoError(t, err)
+
+	t.Run("other peggy ID", func(t *testing.T) {
+		_, _, err := s.client.SignValsetConfirm(s.ethKey, gethcommon.HexToHash("0x01"), testValset(3))
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	t.Run("conflicting checkpoint", func(t *testing.T) {
+		vs := testValset(2)
+		vs.Members[0].Power++
+
+		_, _, err := s.client.SignValsetConfirm(s.ethKey, testPeggyID, vs)
+		assertCode(t, err, codes.AlreadyExists)
+	})
+
+	t.Run("lower nonce", func(t *testing.T) {
+		_, _, err := s.client.SignValsetConfirm(s.ethKey, testPeggyID, testValset(1))
+		assertCode(t, err, codes.PermissionDenied)
+		assert.ErrorContains(t, err, "lower than the last signed nonce 2")
+	})
+
+	t.Run("other Ethereum key", func(t *testing.T) {
+		_, _, err := s.client.SignValsetConfirm(gethcommon.HexToAddress("0x01"), testPeggyID, testValset(3))
+		assert.ErrorContains(t, err, "signer holds Ethereum key")
+	})
+}
+
+func TestSignBatchConfirm(t *testing.T) {
+	store := newTestStore(t)
+	s := newTestSigner(t, store)
+
+	digest, _, err := s.client.SignBatchConfirm(s.ethKey, testPeggyID, testBatch(tokenA, 5))
+	require.NoError(t, err)
+	assert.Equal(t, peggy.EncodeTxBatchConfirm(testPeggyID, testBatch(tokenA, 5)), digest)
+
+	// batches may be signed out of order, a batch held by the outflow queue is approved after later ones
+	_, _, err = s.client.SignBatchConfirm(s.ethKey, testPeggyID, testBatch(tokenB, 3))
+	require.NoError(t, err)
+
+	_, _, err = s.client.SignBatchConfirm(s.ethKey, testPeggyID, testBatch(tokenA, 4))
+	require.NoError(t, err)
+
+	conflicting := func(nonce uint64) *peggytypes.OutgoingTxBatch {
+		batch := testBatch(tokenA, nonce)
+		batch.Transactions[0].Erc20Fee.Amount = sdkmath.NewInt(11)
+
+		return batch
+	}
+
+	t.Run("conflicting checkpoint", func(t *testing.T) {
+		_, _, err := s.client.SignBatchConfirm(s.ethKey, testPeggyID, conflicting(4))
+		assertCode(t, err, codes.AlreadyExists)
+	})
+
+	t.Run("policy survives restarts", func(t *testing.T) {
+		restarted := newTestSigner(t, store)
+
+		_, _, err := restarted.client.SignBatchConfirm(s.ethKey, testPeggyID, conflicting(5))
+		assertCode(t, err, codes.AlreadyExists)
+
+		_, _, err = restarted.client.SignBatchConfirm(s.ethKey, testPeggyID, testBatch(tokenA, 5))
+		require.NoError(t, err)
+
+		_, _, err = restarted.client.SignBatchConfirm(s.ethKey, testPeggyID, testBatch(tokenA, 6))
+		require.NoError(t, err)
+	})
+
+	signed, err := store.SignedDigests()
+	require.NoError(t, err)
+	assert.Len(t, signed, 4)
+}
+
+func TestSignEthereumTx(t *testing.T) {
+	s := newTestSigner(t, newTestStore(t))
+
+	peggyABI, err := abi.JSON(strings.NewReader(wrappers.PeggyABI))
+	require.NoError(t, err)
+
+	// the arguments are not decoded, only the method is checked
+	submitBatch := append(append([]byte{}, peggyABI.Methods["submitBatch"].ID...), 0xde, 0xad)
+
+	newTx := func(to *gethcommon.Address, value int64, data []byte) *gethtypes.Transaction {
+		return gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+			ChainID:   big.NewInt(testEthChainID),
+			Nonce:     1,
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(30e9),
+			Gas:       100000,
+			To:        to,
+			Value:     big.NewInt(value),
+			Data:      data,
+		})
+	}
+
+	peggyAddr, otherAddr := testPeggy, gethcommon.HexToAddress(tokenA)
+	tx := newTx(&peggyAddr, 0, submitBatch)
+
+	signedTx, err := s.client.EthereumSignerFn(testEthChainID)(s.ethKey, tx)
+	require.NoError(t, err)
+
+	sender, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(big.NewInt(testEthChainID)), signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, s.ethKey, sender)
+
+	t.Run("other chain ID", func(t *testing.T) {
+		_, err := s.client.EthereumSignerFn(1)(s.ethKey, tx)
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	refused := []struct {
+		name string
+		tx   *gethtypes.Transaction
+	}{
+		{name: "ETH transfer", tx: newTx(&otherAddr, 1e18, nil)},
+		{name: "call to another contract", tx: newTx(&otherAddr, 0, submitBatch)},
+		{name: "value sent to the Peggy contract", tx: newTx(&peggyAddr, 1, submitBatch)},
+		{name: "other Peggy method", tx: newTx(&peggyAddr, 0, peggyABI.Methods["sendToInjective"].ID)},
+		{name: "contract creation", tx: newTx(nil, 0, submitBatch)},
+	}
+
+	for _, tt := range refused {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.client.EthereumSignerFn(testEthChainID)(s.ethKey, tt.tx)
+			assertCode(t, err, codes.PermissionDenied)
+		})
+	}
+}
+
+func TestSignInjectiveTx(t *testing.T) {
+	s := newTestSigner(t, newTestStore(t))
+	signer := s.client.InjectiveTxSigner()
+
+	signDoc := func(chainID string, typeURL string) []byte {
+		body, err := (&cosmostx.TxBody{Messages: []*codectypes.Any{{TypeUrl: typeURL}}}).Marshal()
+		require.NoError(t, err)
+
+		pubKey, err := codectypes.NewAnyWithValue(signer.PubKey())
+		require.NoError(t, err)
+
+		authInfo, err := (&cosmostx.AuthInfo{SignerInfos: []*cosmostx.SignerInfo{{PublicKey: pubKey}}}).Marshal()
+		require.NoError(t, err)
+
+		doc, err := (&cosmostx.SignDoc{BodyBytes: body, AuthInfoBytes: authInfo, ChainId: chainID, AccountNumber: 7}).Marshal()
+		require.NoError(t, err)
+
+		return doc
+	}
+
+	doc := signDoc(testInjectiveChainID, "/injective.peggy.v1.MsgConfirmBatch")
+	sig, err := signer.SignTx(doc)
+	require.NoError(t, err)
+	assert.True(t, signer.PubKey().VerifySignature(doc, sig))
+
+	t.Run("other chain ID", func(t *testing.T) {
+		_, err := signer.SignTx(signDoc("injective-1", "/injective.peggy.v1.MsgConfirmBatch"))
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	t.Run("not a Peggy message", func(t *testing.T) {
+		_, err := signer.SignTx(signDoc(testInjectiveChainID, "/cosmos.bank.v1beta1.MsgSend"))
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	t.Run("Peggy message not sent by the orchestrator", func(t *testing.T) {
+		_, err := signer.SignTx(signDoc(testInjectiveChainID, "/injective.peggy.v1.MsgSendToEth"))
+		assertCode(t, err, codes.PermissionDenied)
+
+		_, err = signer.SignTx(signDoc(testInjectiveChainID, "/injective.peggy.v1.MsgSetOrchestratorAddresses"))
+		assertCode(t, err, codes.PermissionDenied)
+	})
+}
@@ -0,0 +1,278 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v3.21.12
+// source: signer.proto
+
+package signerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Signer_Keys_FullMethodName              = "/peggo.signer.v1.Signer/Keys"
+	Signer_SignValsetConfirm_FullMethodName = "/peggo.signer.v1.Signer/SignValsetConfirm"
+	Signer_SignBatchConfirm_FullMethodName  = "/peggo.signer.v1.Signer/SignBatchConfirm"
+	Signer_SignEthereumTx_FullMethodName    = "/peggo.signer.v1.Signer/SignEthereumTx"
+	Signer_SignInjectiveTx_FullMethodName   = "/peggo.signer.v1.Signer/SignInjectiveTx"
+)
+
+// SignerClient is the client API for Signer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Signer holds the orchestrator Ethereum and Injective keys on a host that does not talk to any RPC endpoint.
+// It signs only what the orchestrator needs and applies its own policy checks before every signature.
+type SignerClient interface {
+	// Keys returns the keys held by the signer and the networks it signs for.
+	Keys(ctx context.Context, in *KeysRequest, opts ...grpc.CallOption) (*KeysResponse, error)
+	// SignValsetConfirm signs the checkpoint of a validator set with the Ethereum key.
+	SignValsetConfirm(ctx context.Context, in *SignValsetConfirmRequest, opts ...grpc.CallOption) (*SignConfirmResponse, error)
+	// SignBatchConfirm signs the checkpoint of a transaction batch with the Ethereum key.
+	SignBatchConfirm(ctx context.Context, in *SignBatchConfirmRequest, opts ...grpc.CallOption) (*SignConfirmResponse, error)
+	// SignEthereumTx signs an Ethereum transaction with the Ethereum key.
+	SignEthereumTx(ctx context.Context, in *SignEthereumTxRequest, opts ...grpc.CallOption) (*SignEthereumTxResponse, error)
+	// SignInjectiveTx signs an Injective transaction (SIGN_MODE_DIRECT) with the Injective key.
+	SignInjectiveTx(ctx context.Context, in *SignInjectiveTxRequest, opts ...grpc.CallOption) (*SignInjectiveTxResponse, error)
+}
+
+type signerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignerClient(cc grpc.ClientConnInterface) SignerClient {
+	return &signerClient{cc}
+}
+
+func (c *signerClient) Keys(ctx context.Context, in *KeysRequest, opts ...grpc.CallOption) (*KeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeysResponse)
+	err := c.cc.Invoke(ctx, Signer_Keys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) SignValsetConfirm(ctx context.Context, in *SignValsetConfirmRequest, opts ...grpc.CallOption) (*SignConfirmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignConfirmResponse)
+	err := c.cc.Invoke(ctx, Signer_SignValsetConfirm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) SignBatchConfirm(ctx context.Context, in *SignBatchConfirmRequest, opts ...grpc.CallOption) (*SignConfirmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignConfirmResponse)
+	err := c.cc.Invoke(ctx, Signer_SignBatchConfirm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) SignEthereumTx(ctx context.Context, in *SignEthereumTxRequest, opts ...grpc.CallOption) (*SignEthereumTxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignEthereumTxResponse)
+	err := c.cc.Invoke(ctx, Signer_SignEthereumTx_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) SignInjectiveTx(ctx context.Context, in *SignInjectiveTxRequest, opts ...grpc.CallOption) (*SignInjectiveTxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignInjectiveTxResponse)
+	err := c.cc.Invoke(ctx, Signer_SignInjectiveTx_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignerServer is the server API for Signer service.
+// All implementations must embed UnimplementedSignerServer
+// for forward compatibility
+//
+// Signer holds the orchestrator Ethereum and Injective keys on a host that does not talk to any RPC endpoint.
+// It signs only what the orchestrator needs and applies its own policy checks before every signature.
+type SignerServer interface {
+	// Keys returns the keys held by the signer and the networks it signs for.
+	Keys(context.Context, *KeysRequest) (*KeysResponse, error)
+	// SignValsetConfirm signs the checkpoint of a validator set with the Ethereum key.
+	SignValsetConfirm(context.Context, *SignValsetConfirmRequest) (*SignConfirmResponse, error)
+	// SignBatchConfirm signs the checkpoint of a transaction batch with the Ethereum key.
+	SignBatchConfirm(context.Context, *SignBatchConfirmRequest) (*SignConfirmResponse, error)
+	// SignEthereumTx signs an Ethereum transaction with the Ethereum key.
+	SignEthereumTx(context.Context, *SignEthereumTxRequest) (*SignEthereumTxResponse, error)
+	// SignInjectiveTx signs an Injective transaction (SIGN_MODE_DIRECT) with the Injective key.
+	SignInjectiveTx(context.Context, *SignInjectiveTxRequest) (*SignInjectiveTxResponse, error)
+	mustEmbedUnimplementedSignerServer()
+}
+
+// UnimplementedSignerServer must be embedded to have forward compatible implementations.
+type UnimplementedSignerServer struct {
+}
+
+func (UnimplementedSignerServer) Keys(context.Context, *KeysRequest) (*KeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Keys not implemented")
+}
+func (UnimplementedSignerServer) SignValsetConfirm(context.Context, *SignValsetConfirmRequest) (*SignConfirmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignValsetConfirm not implemented")
+}
+func (UnimplementedSignerServer) SignBatchConfirm(context.Context, *SignBatchConfirmRequest) (*SignConfirmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignBatchConfirm not implemented")
+}
+func (UnimplementedSignerServer) SignEthereumTx(context.Context, *SignEthereumTxRequest) (*SignEthereumTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignEthereumTx not implemented")
+}
+func (UnimplementedSignerServer) SignInjectiveTx(context.Context, *SignInjectiveTxRequest) (*SignInjectiveTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignInjectiveTx not implemented")
+}
+func (UnimplementedSignerServer) mustEmbedUnimplementedSignerServer() {}
+
+// UnsafeSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignerServer will
+// result in compilation errors.
+type UnsafeSignerServer interface {
+	mustEmbedUnimplementedSignerServer()
+}
+
+func RegisterSignerServer(s grpc.ServiceRegistrar, srv SignerServer) {
+	s.RegisterService(&Signer_ServiceDesc, srv)
+}
+
+func _Signer_Keys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).Keys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_Keys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).Keys(ctx, req.(*KeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_SignValsetConfirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignValsetConfirmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignValsetConfirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_SignValsetConfirm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignValsetConfirm(ctx, req.(*SignValsetConfirmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_SignBatchConfirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignBatchConfirmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignBatchConfirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_SignBatchConfirm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignBatchConfirm(ctx, req.(*SignBatchConfirmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_SignEthereumTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignEthereumTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignEthereumTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_SignEthereumTx_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignEthereumTx(ctx, req.(*SignEthereumTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_SignInjectiveTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignInjectiveTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignInjectiveTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_SignInjectiveTx_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignInjectiveTx(ctx, req.(*SignInjectiveTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Signer_ServiceDesc is the grpc.ServiceDesc for Signer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Signer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "peggo.signer.v1.Signer",
+	HandlerType: (*SignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Keys",
+			Handler:    _Signer_Keys_Handler,
+		},
+		{
+			MethodName: "SignValsetConfirm",
+			Handler:    _Signer_SignValsetConfirm_Handler,
+		},
+		{
+			MethodName: "SignBatchConfirm",
+			Handler:    _Signer_SignBatchConfirm_Handler,
+		},
+		{
+			MethodName: "SignEthereumTx",
+			Handler:    _Signer_SignEthereumTx_Handler,
+		},
+		{
+			MethodName: "SignInjectiveTx",
+			Handler:    _Signer_SignInjectiveTx_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signer.proto",
+}
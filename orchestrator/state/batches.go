@@ -0,0 +1,39 @@
+package state
+
+import (
+	"time"
+)
+
+const batchCreatorKey = "batchcreator/progress"
+
+// BatchCreatorProgress is what the BatchCreator loop knows about the withdrawal pool and the batches it forced.
+type BatchCreatorProgress struct {
+	// FirstSeen is when each withdrawal of the pool, by ID, was first seen
+	FirstSeen map[uint64]time.Time `json:"first_seen"`
+
+	// ForcedBatches are the times batches were forced because of the withdrawal age, by token contract
+	ForcedBatches map[string][]time.Time `json:"forced_batches,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BatchCreatorState keeps the progress of the BatchCreator loop, so that the age of the withdrawals and the caps on
+// forced batches survive restarts.
+type BatchCreatorState interface {
+	// BatchCreatorProgress returns ErrNotFound if no progress was saved yet.
+	BatchCreatorProgress() (*BatchCreatorProgress, error)
+	SetBatchCreatorProgress(p *BatchCreatorProgress) error
+}
+
+func (s *levelDBStore) BatchCreatorProgress() (*BatchCreatorProgress, error) {
+	p := new(BatchCreatorProgress)
+	if err := s.get([]byte(batchCreatorKey), p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (s *levelDBStore) SetBatchCreatorProgress(p *BatchCreatorProgress) error {
+	return s.put([]byte(batchCreatorKey), p)
+}
@@ -0,0 +1,41 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchCreatorProgress(t *testing.T) {
+	const token = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+
+	dataDir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s, err := NewStore(dataDir)
+	require.NoError(t, err)
+
+	_, err = s.BatchCreatorProgress()
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.SetBatchCreatorProgress(&BatchCreatorProgress{
+		FirstSeen:     map[uint64]time.Time{7: now.Add(-time.Hour), 9: now},
+		ForcedBatches: map[string][]time.Time{token: {now}},
+		UpdatedAt:     now,
+	}))
+	require.NoError(t, s.Close())
+
+	// progress survives a restart
+	s, err = NewStore(dataDir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	p, err := s.BatchCreatorProgress()
+	require.NoError(t, err)
+	assert.Len(t, p.FirstSeen, 2)
+	assert.True(t, p.FirstSeen[7].Equal(now.Add(-time.Hour)))
+	require.Len(t, p.ForcedBatches[token], 1)
+	assert.True(t, p.ForcedBatches[token][0].Equal(now))
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const outflowPrefix = "outflow/"
+
+// OutflowRecord records the amount withdrawn by a batch signed with the Ethereum delegate key.
+type OutflowRecord struct {
+	TokenContract string `json:"token_contract"`
+	BatchNonce    uint64 `json:"batch_nonce"`
+
+	// Amount is the total of the batch transfers and fees, in token units
+	Amount decimal.Decimal `json:"amount"`
+
+	// USDValue is the value of Amount when the batch was signed, if the price was known
+	USDValue *decimal.Decimal `json:"usd_value,omitempty"`
+	SignedAt time.Time        `json:"signed_at"`
+}
+
+// OutflowLedger keeps the amounts withdrawn by the signed batches, so that outflow limits over a rolling
+// window survive restarts.
+type OutflowLedger interface {
+	// RecordOutflow records a signed batch. Recording the same batch again keeps the first record.
+	RecordOutflow(r OutflowRecord) error
+
+	// Outflows returns the batches of the token signed at or after since, in nonce order.
+	Outflows(tokenContract string, since time.Time) ([]OutflowRecord, error)
+}
+
+func (s *levelDBStore) RecordOutflow(r OutflowRecord) error {
+	key := outflowKey(r.TokenContract, r.BatchNonce)
+
+	var recorded OutflowRecord
+	switch err := s.get(key, &recorded); {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound):
+	default:
+		return err
+	}
+
+	if r.SignedAt.IsZero() {
+		r.SignedAt = time.Now().UTC()
+	}
+
+	return s.put(key, r)
+}
+
+func (s *levelDBStore) Outflows(tokenContract string, since time.Time) ([]OutflowRecord, error) {
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(outflowTokenPrefix(tokenContract))), nil)
+	defer iter.Release()
+
+	var records []OutflowRecord
+	for iter.Next() {
+		var r OutflowRecord
+		if err := json.Unmarshal(iter.Value(), &r); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s", iter.Key())
+		}
+
+		if !r.SignedAt.Before(since) {
+			records = append(records, r)
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to read outflow records")
+	}
+
+	return records, nil
+}
+
+func outflowTokenPrefix(tokenContract string) string {
+	return outflowPrefix + strings.ToLower(tokenContract) + "/"
+}
+
+func outflowKey(tokenContract string, nonce uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", outflowTokenPrefix(tokenContract), nonce))
+}
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutflowLedger(t *testing.T) {
+	const token = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+
+	dataDir := t.TempDir()
+	now := time.Now().UTC()
+
+	s, err := NewStore(dataDir)
+	require.NoError(t, err)
+
+	usd := decimal.RequireFromString("99.5")
+	require.NoError(t, s.RecordOutflow(OutflowRecord{TokenContract: token, BatchNonce: 3, Amount: decimal.NewFromInt(10), SignedAt: now.Add(-2 * time.Hour)}))
+	require.NoError(t, s.RecordOutflow(OutflowRecord{TokenContract: token, BatchNonce: 12, Amount: decimal.RequireFromString("1.25"), USDValue: &usd, SignedAt: now}))
+	require.NoError(t, s.RecordOutflow(OutflowRecord{TokenContract: "0x36B3D7ACe7201E28040eFf30e815290D7b37ffaD", BatchNonce: 4, Amount: decimal.NewFromInt(7)}))
+
+	// recording a batch again keeps the first record
+	require.NoError(t, s.RecordOutflow(OutflowRecord{TokenContract: token, BatchNonce: 3, Amount: decimal.NewFromInt(1000), SignedAt: now}))
+	require.NoError(t, s.Close())
+
+	s, err = NewStore(dataDir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	records, err := s.Outflows(token, now.Add(-3*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, uint64(3), records[0].BatchNonce)
+	assert.True(t, records[0].Amount.Equal(decimal.NewFromInt(10)))
+	assert.Nil(t, records[0].USDValue)
+	assert.Equal(t, uint64(12), records[1].BatchNonce)
+	assert.True(t, records[1].USDValue.Equal(usd))
+
+	// token contracts are matched case insensitively, older records are left out
+	records, err = s.Outflows("0xdac17f958d2ee523a2206206994597c13d831ec7", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, uint64(12), records[0].BatchNonce)
+}
@@ -0,0 +1,179 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// ErrConflictingSignature is returned when a signature is requested for a nonce that was already signed over
+// a different digest. Signing it could get the Ethereum delegate key slashed.
+var ErrConflictingSignature = errors.New("conflicting signature")
+
+const (
+	signaturePrefix = "slashing/"
+
+	// version of the slashing protection interchange format
+	interchangeVersion = "1"
+)
+
+// SignatureKind is the kind of Peggy checkpoint signed with the Ethereum delegate key.
+type SignatureKind string
+
+const (
+	ValsetSignature SignatureKind = "valset"
+	BatchSignature  SignatureKind = "batch"
+)
+
+// SignedDigest records a digest signed with the Ethereum delegate key.
+type SignedDigest struct {
+	Kind    SignatureKind   `json:"kind"`
+	PeggyID gethcommon.Hash `json:"peggy_id"`
+	Nonce   uint64          `json:"nonce"`
+	Digest  gethcommon.Hash `json:"digest"`
+
+	// TokenContract is the token of a batch, for information only: batch nonces are global across tokens, so it is
+	// not part of the key
+	TokenContract string    `json:"token_contract,omitempty"`
+	SignedAt      time.Time `json:"signed_at"`
+}
+
+// SlashingProtection keeps every digest signed with the Ethereum delegate key, so that no two different
+// checkpoints are ever signed for the same valset or batch nonce.
+type SlashingProtection interface {
+	// CheckAndRecordSignature records the digest before it is signed. It returns ErrConflictingSignature if a
+	// different digest was already signed for the same kind, peggy ID and nonce. Signing the same digest again
+	// is allowed.
+	CheckAndRecordSignature(d SignedDigest) error
+	SignedDigests() ([]SignedDigest, error)
+	ExportSignedDigests(w io.Writer) error
+	ImportSignedDigests(r io.Reader) (int, error)
+}
+
+// slashingInterchange is the file format used to move the slashing protection database between hosts.
+type slashingInterchange struct {
+	Version       string         `json:"interchange_format_version"`
+	SignedDigests []SignedDigest `json:"signed_digests"`
+}
+
+func (s *levelDBStore) CheckAndRecordSignature(d SignedDigest) error {
+	s.signMux.Lock()
+	defer s.signMux.Unlock()
+
+	return s.recordSignature(d)
+}
+
+func (s *levelDBStore) recordSignature(d SignedDigest) error {
+	var signed SignedDigest
+	switch err := s.get(signatureKey(d.Kind, d.PeggyID, d.Nonce), &signed); {
+	case err == nil:
+		if signed.Digest != d.Digest {
+			return errors.Wrapf(ErrConflictingSignature, "%s nonce %d already signed with digest %s, refusing to sign %s", d.Kind, d.Nonce, signed.Digest.Hex(), d.Digest.Hex())
+		}
+
+		return nil
+	case errors.Is(err, ErrNotFound):
+	default:
+		return err
+	}
+
+	if d.SignedAt.IsZero() {
+		d.SignedAt = time.Now().UTC()
+	}
+
+	return s.put(signatureKey(d.Kind, d.PeggyID, d.Nonce), d)
+}
+
+func (s *levelDBStore) SignedDigests() ([]SignedDigest, error) {
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(signaturePrefix)), nil)
+	defer iter.Release()
+
+	var digests []SignedDigest
+	for iter.Next() {
+		var d SignedDigest
+		if err := json.Unmarshal(iter.Value(), &d); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s", iter.Key())
+		}
+
+		digests = append(digests, d)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to read signed digests")
+	}
+
+	return digests, nil
+}
+
+// ExportSignedDigests writes all the signed digests in the interchange format.
+func (s *levelDBStore) ExportSignedDigests(w io.Writer) error {
+	digests, err := s.SignedDigests()
+	if err != nil {
+		return err
+	}
+
+	if digests == nil {
+		digests = []SignedDigest{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(slashingInterchange{Version: interchangeVersion, SignedDigests: digests})
+}
+
+// ImportSignedDigests merges the signed digests of an interchange file into the database and returns the number
+// of imported records. Nothing is imported if any of them conflicts with a digest signed on this host.
+func (s *levelDBStore) ImportSignedDigests(r io.Reader) (int, error) {
+	var interchange slashingInterchange
+	if err := json.NewDecoder(r).Decode(&interchange); err != nil {
+		return 0, errors.Wrap(err, "failed to decode slashing protection interchange")
+	}
+
+	if interchange.Version != interchangeVersion {
+		return 0, errors.Errorf("unsupported slashing protection interchange format version %q", interchange.Version)
+	}
+
+	s.signMux.Lock()
+	defer s.signMux.Unlock()
+
+	// check the whole file before writing anything, it may also conflict with itself
+	imported := make(map[string]gethcommon.Hash, len(interchange.SignedDigests))
+	for _, d := range interchange.SignedDigests {
+		if d.Kind != ValsetSignature && d.Kind != BatchSignature {
+			return 0, errors.Errorf("unknown signature kind %q", d.Kind)
+		}
+
+		key := string(signatureKey(d.Kind, d.PeggyID, d.Nonce))
+		if digest, ok := imported[key]; ok && digest != d.Digest {
+			return 0, errors.Wrapf(ErrConflictingSignature, "%s nonce %d signed with digests %s and %s", d.Kind, d.Nonce, digest.Hex(), d.Digest.Hex())
+		}
+
+		imported[key] = d.Digest
+
+		var signed SignedDigest
+		if err := s.get([]byte(key), &signed); err == nil && signed.Digest != d.Digest {
+			return 0, errors.Wrapf(ErrConflictingSignature, "%s nonce %d signed with digest %s here and %s in the imported file", d.Kind, d.Nonce, signed.Digest.Hex(), d.Digest.Hex())
+		} else if err != nil && !errors.Is(err, ErrNotFound) {
+			return 0, err
+		}
+	}
+
+	for _, d := range interchange.SignedDigests {
+		if err := s.recordSignature(d); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(interchange.SignedDigests), nil
+}
+
+func signatureKey(kind SignatureKind, peggyID gethcommon.Hash, nonce uint64) []byte {
+	// zero padded nonces keep the keys ordered
+	return []byte(fmt.Sprintf("%s%s/%s/%020d", signaturePrefix, kind, peggyID.Hex(), nonce))
+}
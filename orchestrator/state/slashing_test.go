@@ -0,0 +1,77 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlashingProtection(t *testing.T) {
+	peggyID := gethcommon.HexToHash("0x696e6a6563746976652d7065676779696400000000000000000000000000000000")
+	digest := gethcommon.HexToHash("0x01")
+	otherDigest := gethcommon.HexToHash("0x02")
+
+	s, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.CheckAndRecordSignature(SignedDigest{Kind: ValsetSignature, PeggyID: peggyID, Nonce: 5, Digest: digest}))
+
+	// signing the same digest again is allowed
+	assert.NoError(t, s.CheckAndRecordSignature(SignedDigest{Kind: ValsetSignature, PeggyID: peggyID, Nonce: 5, Digest: digest}))
+
+	// a different digest for the same nonce is refused
+	err = s.CheckAndRecordSignature(SignedDigest{Kind: ValsetSignature, PeggyID: peggyID, Nonce: 5, Digest: otherDigest})
+	assert.ErrorIs(t, err, ErrConflictingSignature)
+
+	// nonces are tracked per kind and peggy ID
+	assert.NoError(t, s.CheckAndRecordSignature(SignedDigest{Kind: BatchSignature, PeggyID: peggyID, Nonce: 5, Digest: otherDigest}))
+	assert.NoError(t, s.CheckAndRecordSignature(SignedDigest{Kind: ValsetSignature, PeggyID: gethcommon.HexToHash("0x03"), Nonce: 5, Digest: otherDigest}))
+
+	digests, err := s.SignedDigests()
+	require.NoError(t, err)
+	assert.Len(t, digests, 3)
+}
+
+func TestSlashingProtectionInterchange(t *testing.T) {
+	peggyID := gethcommon.HexToHash("0x696e6a6563746976652d7065676779696400000000000000000000000000000000")
+
+	src, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer src.Close()
+
+	require.NoError(t, src.CheckAndRecordSignature(SignedDigest{Kind: ValsetSignature, PeggyID: peggyID, Nonce: 5, Digest: gethcommon.HexToHash("0x01")}))
+	require.NoError(t, src.CheckAndRecordSignature(SignedDigest{Kind: BatchSignature, PeggyID: peggyID, Nonce: 7, Digest: gethcommon.HexToHash("0x02")}))
+
+	var exported bytes.Buffer
+	require.NoError(t, src.ExportSignedDigests(&exported))
+
+	dst, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer dst.Close()
+
+	// the destination already signed the same valset, and a different batch
+	require.NoError(t, dst.CheckAndRecordSignature(SignedDigest{Kind: ValsetSignature, PeggyID: peggyID, Nonce: 5, Digest: gethcommon.HexToHash("0x01")}))
+	require.NoError(t, dst.CheckAndRecordSignature(SignedDigest{Kind: BatchSignature, PeggyID: peggyID, Nonce: 7, Digest: gethcommon.HexToHash("0x03")}))
+
+	_, err = dst.ImportSignedDigests(bytes.NewReader(exported.Bytes()))
+	assert.ErrorIs(t, err, ErrConflictingSignature)
+
+	clean, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer clean.Close()
+
+	n, err := clean.ImportSignedDigests(bytes.NewReader(exported.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// imported digests protect the new host
+	err = clean.CheckAndRecordSignature(SignedDigest{Kind: BatchSignature, PeggyID: peggyID, Nonce: 7, Digest: gethcommon.HexToHash("0x03")})
+	assert.ErrorIs(t, err, ErrConflictingSignature)
+
+	_, err = clean.ImportSignedDigests(bytes.NewReader([]byte(`{"interchange_format_version": "0"}`)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+const (
+	dbName = "peggo.db"
+
+	oracleCheckpointPrefix = "oracle/checkpoint/"
+)
+
+// Store persists orchestrator progress on disk so it survives restarts and crashes.
+type Store interface {
+	OracleCheckpoint(peggyContract gethcommon.Address) (*OracleCheckpoint, error)
+	SetOracleCheckpoint(peggyContract gethcommon.Address, cp *OracleCheckpoint) error
+	SlashingProtection
+	OutflowLedger
+	BatchCreatorState
+	Close() error
+}
+
+// OracleCheckpoint is the last known progress of the Oracle loop for a given Peggy contract.
+type OracleCheckpoint struct {
+	LastObservedEthHeight uint64    `json:"last_observed_eth_height"`
+	LastClaimedEventNonce uint64    `json:"last_claimed_event_nonce"`
+	LastScanStart         uint64    `json:"last_scan_start"`
+	LastScanEnd           uint64    `json:"last_scan_end"`
+	UpdatedAt             time.Time `json:"updated_at"`
+
+	// RecentBlocks are the most recently scanned blocks, used to detect reorgs below LastObservedEthHeight
+	RecentBlocks []BlockRef `json:"recent_blocks,omitempty"`
+}
+
+// BlockRef identifies a scanned Ethereum block.
+type BlockRef struct {
+	Height uint64          `json:"height"`
+	Hash   gethcommon.Hash `json:"hash"`
+}
+
+type levelDBStore struct {
+	db *leveldb.DB
+
+	// serializes the check and the record of signatures
+	signMux sync.Mutex
+}
+
+// NewStore opens (or creates) the embedded state database located in dataDir.
+func NewStore(dataDir string) (Store, error) {
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create data dir %s", dataDir)
+	}
+
+	db, err := leveldb.OpenFile(filepath.Join(dataDir, dbName), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open state db in %s", dataDir)
+	}
+
+	return &levelDBStore{db: db}, nil
+}
+
+func (s *levelDBStore) OracleCheckpoint(peggyContract gethcommon.Address) (*OracleCheckpoint, error) {
+	cp := new(OracleCheckpoint)
+	if err := s.get(oracleCheckpointKey(peggyContract), cp); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+func (s *levelDBStore) SetOracleCheckpoint(peggyContract gethcommon.Address, cp *OracleCheckpoint) error {
+	return s.put(oracleCheckpointKey(peggyContract), cp)
+}
+
+func (s *levelDBStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *levelDBStore) get(key []byte, v interface{}) error {
+	data, err := s.db.Get(key, nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return ErrNotFound
+		}
+
+		return errors.Wrapf(err, "failed to read %s", key)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Wrapf(err, "failed to decode %s", key)
+	}
+
+	return nil
+}
+
+func (s *levelDBStore) put(key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s", key)
+	}
+
+	// sync writes so that a crash right after a checkpoint does not lose it
+	if err := s.db.Put(key, data, &opt.WriteOptions{Sync: true}); err != nil {
+		return errors.Wrapf(err, "failed to write %s", key)
+	}
+
+	return nil
+}
+
+func oracleCheckpointKey(peggyContract gethcommon.Address) []byte {
+	return []byte(oracleCheckpointPrefix + peggyContract.Hex())
+}
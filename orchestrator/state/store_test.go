@@ -0,0 +1,45 @@
+package state
+
+import (
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOracleCheckpoint(t *testing.T) {
+	peggyContract := gethcommon.HexToAddress("0x5048019d259217e6b7BC8e1E6aEfa9976B1ADFfe")
+	dataDir := t.TempDir()
+
+	s, err := NewStore(dataDir)
+	require.NoError(t, err)
+
+	_, err = s.OracleCheckpoint(peggyContract)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	cp := &OracleCheckpoint{
+		LastObservedEthHeight: 2100,
+		LastClaimedEventNonce: 103,
+		LastScanStart:         100,
+		LastScanEnd:           2100,
+	}
+
+	require.NoError(t, s.SetOracleCheckpoint(peggyContract, cp))
+	require.NoError(t, s.Close())
+
+	// checkpoint survives a restart
+	s, err = NewStore(dataDir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	loaded, err := s.OracleCheckpoint(peggyContract)
+	require.NoError(t, err)
+	assert.Equal(t, cp.LastObservedEthHeight, loaded.LastObservedEthHeight)
+	assert.Equal(t, cp.LastClaimedEventNonce, loaded.LastClaimedEventNonce)
+	assert.Equal(t, cp.LastScanStart, loaded.LastScanStart)
+	assert.Equal(t, cp.LastScanEnd, loaded.LastScanEnd)
+
+	_, err = s.OracleCheckpoint(gethcommon.HexToAddress("0x3959f5246c452463279F690301D923D5a75bbD88"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}